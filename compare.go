@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jtotty/weather-cli/internal/cli"
+	"github.com/jtotty/weather-cli/internal/output"
+	"github.com/jtotty/weather-cli/internal/service"
+	"github.com/jtotty/weather-cli/internal/weather"
+)
+
+// runCompare fetches every location concurrently and renders them side by
+// side. It fails only when no location could be fetched.
+func runCompare(ctx context.Context, cmd cli.Command) {
+	cfg, format, units := prepare(cmd)
+
+	if format == output.FormatCSV {
+		cli.ExitWithError(errors.New("compare supports text, json, yaml and ndjson output"))
+	}
+
+	svc, err := service.NewWeather(cfg)
+	if err != nil {
+		cli.ExitWithError(err)
+	}
+
+	// Expand @aliases up front; an unknown alias fails only its own column.
+	queries := make([]string, len(cmd.Locations))
+	names := make([]string, len(cmd.Locations))
+	aliasErrs := make([]error, len(cmd.Locations))
+	var fetch []string
+	for i, query := range cmd.Locations {
+		queries[i], names[i], aliasErrs[i] = cfg.ExpandAlias(query)
+		if aliasErrs[i] == nil {
+			fetch = append(fetch, queries[i])
+		}
+	}
+
+	results := svc.GetWeatherMany(ctx, fetch)
+
+	columns := make([]weather.CompareColumn, len(cmd.Locations))
+	comparison := output.NewComparison()
+	failed := 0
+	for i, query := range cmd.Locations {
+		col := weather.CompareColumn{Title: query, Err: aliasErrs[i]}
+		if col.Err == nil {
+			result := results[0]
+			results = results[1:]
+			col.Data, col.Err = result.Data, result.Err
+		}
+
+		if col.Err != nil {
+			if errors.Is(col.Err, context.Canceled) {
+				cli.ExitWithFetchError(col.Err, query)
+			}
+			failed++
+		} else if names[i] != "" {
+			col.Data.Location.Name = names[i]
+		}
+
+		columns[i] = col
+		comparison.Add(query, col.Data, col.Err)
+	}
+
+	if format != output.FormatText {
+		if err := output.RenderComparison(os.Stdout, format, comparison); err != nil {
+			cli.ExitWithError(fmt.Errorf("error writing %s output: %w", format, err))
+		}
+	} else {
+		weather.NewComparison(columns).WithUnits(units).Render()
+	}
+
+	if failed == len(columns) {
+		os.Exit(1)
+	}
+}
@@ -5,12 +5,13 @@ go 1.25.4
 require (
 	github.com/enescakir/emoji v1.0.0
 	github.com/zalando/go-keyring v0.2.6
+	golang.org/x/sys v0.39.0
 	golang.org/x/term v0.38.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	al.essio.dev/pkg/shellescape v1.5.1 // indirect
 	github.com/danieljoos/wincred v1.2.2 // indirect
 	github.com/godbus/dbus/v5 v5.1.0 // indirect
-	golang.org/x/sys v0.39.0 // indirect
 )
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	api "github.com/jtotty/weather-cli/internal/api/weather"
+	"github.com/jtotty/weather-cli/internal/cli"
+	"github.com/jtotty/weather-cli/internal/service"
+	"github.com/jtotty/weather-cli/internal/weather"
+)
+
+// runHistory shows the observed weather for one day or a range of days.
+// Only weatherapi.com has historical data, whatever the provider chain.
+func runHistory(ctx context.Context, cmd cli.Command) {
+	cfg, format, units := prepare(cmd)
+
+	if cfg.Template != "" && cmd.Format != "" {
+		cli.ExitWithError(errors.New("--format and --template cannot be combined"))
+	}
+	if cfg.APIKey == "" && !cfg.Offline {
+		cli.ExitWithError(errors.New("history needs a weatherapi.com API key; run 'weather-cli key set'"))
+	}
+
+	pinLocation(ctx, cfg)
+
+	to := cmd.To
+	if to.IsZero() {
+		to = cmd.Date
+	}
+
+	data, err := service.NewHistory(cfg).GetHistory(ctx, cfg.Location, cmd.Date, to)
+	if err != nil {
+		if errors.Is(err, api.ErrInvalidKey) && cli.OfferSetup() {
+			runHistory(ctx, cmd)
+			return
+		}
+		cli.ExitWithFetchError(err, locationLabel(cfg))
+	}
+
+	if cfg.LocationName != "" {
+		data.Location.Name = cfg.LocationName
+	}
+
+	if writeReport(cfg, format, data) {
+		return
+	}
+
+	display, err := weather.NewDisplay(data, cfg.IsLocal)
+	if err != nil {
+		cli.ExitWithError(fmt.Errorf("error creating display: %w", err))
+	}
+	fmt.Println(display.WithUnits(units).WithDetails(cmd.Details).WithViewerTime(cmd.ViewerTime).History())
+}
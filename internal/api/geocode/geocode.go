@@ -0,0 +1,141 @@
+// Package geocode resolves free-text locations to coordinates for providers
+// that only accept latitude and longitude.
+package geocode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jtotty/weather-cli/internal/api/httpjson"
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+const baseURL = "https://geocoding-api.open-meteo.com/v1/search"
+
+var ErrAutoLocation = errors.New("IP-based location is not supported by this provider; pass a city name or coordinates")
+
+// ErrNotFound reports that no place matches a location. It is the model's
+// ErrLocationNotFound, so an unknown place matches the same error whichever
+// provider was asked.
+var ErrNotFound = forecast.ErrLocationNotFound
+
+// Place is a resolved location.
+type Place struct {
+	Name     string
+	Region   string
+	Country  string
+	Lat      float64
+	Lon      float64
+	Timezone string
+}
+
+// Resolver turns a location string into coordinates. *Client implements it.
+type Resolver interface {
+	Resolve(ctx context.Context, location string) (Place, error)
+}
+
+// Static is a Resolver that answers every location with Place, or fails
+// with Err. Tests use it to keep clients off the geocoding API.
+type Static struct {
+	Place Place
+	Err   error
+}
+
+func (s Static) Resolve(ctx context.Context, location string) (Place, error) {
+	return s.Place, s.Err
+}
+
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+func NewClient() *Client {
+	return &Client{
+		httpClient: httpjson.NewClient(),
+		baseURL:    baseURL,
+	}
+}
+
+type searchResponse struct {
+	Results []struct {
+		Name      string  `json:"name"`
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+		Country   string  `json:"country"`
+		Admin1    string  `json:"admin1"`
+		Timezone  string  `json:"timezone"`
+	} `json:"results"`
+}
+
+// Resolve turns a location string into a Place. Coordinates are parsed
+// locally; anything else is looked up with the Open-Meteo geocoding API.
+func (c *Client) Resolve(ctx context.Context, location string) (Place, error) {
+	location = strings.TrimSpace(location)
+
+	if lat, lon, ok := ParseCoordinates(location); ok {
+		return Place{
+			Name: FormatCoordinates(lat, lon),
+			Lat:  lat,
+			Lon:  lon,
+		}, nil
+	}
+
+	if location == "" || strings.HasPrefix(location, "auto:") {
+		return Place{}, ErrAutoLocation
+	}
+
+	params := url.Values{}
+	params.Add("name", location)
+	params.Add("count", "1")
+	params.Add("format", "json")
+
+	var res searchResponse
+	if err := httpjson.Get(ctx, c.httpClient, c.baseURL+"?"+params.Encode(), nil, &res); err != nil {
+		return Place{}, fmt.Errorf("geocoding %q: %w", location, err)
+	}
+
+	if len(res.Results) == 0 {
+		return Place{}, fmt.Errorf("%w: %s", ErrNotFound, location)
+	}
+
+	r := res.Results[0]
+	return Place{
+		Name:     r.Name,
+		Region:   r.Admin1,
+		Country:  r.Country,
+		Lat:      r.Latitude,
+		Lon:      r.Longitude,
+		Timezone: r.Timezone,
+	}, nil
+}
+
+// ParseCoordinates parses a "lat,lon" string.
+func ParseCoordinates(s string) (lat, lon float64, ok bool) {
+	latStr, lonStr, found := strings.Cut(s, ",")
+	if !found {
+		return 0, 0, false
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return 0, 0, false
+	}
+
+	lon, err = strconv.ParseFloat(strings.TrimSpace(lonStr), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return 0, 0, false
+	}
+
+	return lat, lon, true
+}
+
+// FormatCoordinates formats coordinates to the precision providers accept.
+func FormatCoordinates(lat, lon float64) string {
+	return strconv.FormatFloat(lat, 'f', 4, 64) + "," + strconv.FormatFloat(lon, 'f', 4, 64)
+}
@@ -0,0 +1,98 @@
+package geocode
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestParseCoordinates(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantLat float64
+		wantLon float64
+		wantOK  bool
+	}{
+		{"51.5,-0.1", 51.5, -0.1, true},
+		{" 40.71 , -74.01 ", 40.71, -74.01, true},
+		{"London", 0, 0, false},
+		{"91,0", 0, 0, false},
+		{"0,181", 0, 0, false},
+		{"abc,def", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			lat, lon, ok := ParseCoordinates(tt.input)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseCoordinates(%q) ok = %v, want %v", tt.input, ok, tt.wantOK)
+			}
+			if lat != tt.wantLat || lon != tt.wantLon {
+				t.Errorf("ParseCoordinates(%q) = %v,%v, want %v,%v", tt.input, lat, lon, tt.wantLat, tt.wantLon)
+			}
+		})
+	}
+}
+
+func TestResolve_Coordinates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("coordinates should not be geocoded remotely")
+	}))
+	defer server.Close()
+
+	place, err := NewTestClient(server.URL).Resolve(context.Background(), "51.5,-0.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if place.Lat != 51.5 || place.Lon != -0.1 {
+		t.Errorf("Resolve() = %v,%v, want 51.5,-0.1", place.Lat, place.Lon)
+	}
+}
+
+func TestResolve_Search(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/search.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("name"); got != "London" {
+			t.Errorf("name = %q, want %q", got, "London")
+		}
+		_, _ = w.Write(fixture)
+	}))
+	defer server.Close()
+
+	place, err := NewTestClient(server.URL).Resolve(context.Background(), "London")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if place.Name != "London" || place.Country != "United Kingdom" {
+		t.Errorf("Resolve() = %+v, want London, United Kingdom", place)
+	}
+	if place.Timezone != "Europe/London" {
+		t.Errorf("Timezone = %q, want %q", place.Timezone, "Europe/London")
+	}
+}
+
+func TestResolve_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"generationtime_ms": 0.1}`))
+	}))
+	defer server.Close()
+
+	client := NewTestClient(server.URL)
+
+	if _, err := client.Resolve(context.Background(), "auto:ip"); !errors.Is(err, ErrAutoLocation) {
+		t.Errorf("Resolve(auto:ip) error = %v, want ErrAutoLocation", err)
+	}
+
+	if _, err := client.Resolve(context.Background(), "Nowhereville"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Resolve(Nowhereville) error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,78 @@
+// Package httpjson provides the HTTP plumbing shared by the weather provider clients.
+package httpjson
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const MaxResponseSize = 10 * 1024 * 1024 // 10MB to prevent DoS
+
+// StatusError is returned when an API responds with a non-200 status.
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+	// Header holds the response headers, e.g. Retry-After.
+	Header http.Header
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("weather API returned status %d", e.StatusCode)
+}
+
+// NewClient returns an HTTP client with the timeouts used by every provider.
+func NewClient() *http.Client {
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			MaxIdleConns:          10,
+			IdleConnTimeout:       30 * time.Second,
+			DisableCompression:    false,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: 10 * time.Second,
+		},
+	}
+}
+
+// Get performs a GET request and decodes the JSON body into v.
+func Get(ctx context.Context, client *http.Client, reqURL string, header http.Header, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, MaxResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return &StatusError{StatusCode: res.StatusCode, Body: body, Header: res.Header}
+	}
+
+	if len(body) == MaxResponseSize {
+		return fmt.Errorf("response too large (exceeded %d bytes)", MaxResponseSize)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package httpjson
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGet_DecodesJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "weather-cli-test" {
+			t.Errorf("User-Agent = %q, want %q", r.Header.Get("User-Agent"), "weather-cli-test")
+		}
+		_, _ = w.Write([]byte(`{"name":"London"}`))
+	}))
+	defer server.Close()
+
+	header := http.Header{}
+	header.Set("User-Agent", "weather-cli-test")
+
+	var got struct {
+		Name string `json:"name"`
+	}
+	if err := Get(context.Background(), NewClient(), server.URL, header, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Name != "London" {
+		t.Errorf("Name = %q, want %q", got.Name, "London")
+	}
+}
+
+func TestGet_StatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"bad"}`))
+	}))
+	defer server.Close()
+
+	var v any
+	err := Get(context.Background(), NewClient(), server.URL, nil, &v)
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("error = %v, want *StatusError", err)
+	}
+	if statusErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("StatusCode = %d, want %d", statusErr.StatusCode, http.StatusBadRequest)
+	}
+	if string(statusErr.Body) != `{"error":"bad"}` {
+		t.Errorf("Body = %q, want error body", statusErr.Body)
+	}
+}
+
+func TestGet_InvalidJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`not json`))
+	}))
+	defer server.Close()
+
+	var v any
+	if err := Get(context.Background(), NewClient(), server.URL, nil, &v); err == nil {
+		t.Error("expected error for invalid JSON, got nil")
+	}
+}
@@ -0,0 +1,147 @@
+package httpjson
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Retry defaults.
+const (
+	DefaultMaxAttempts = 3
+	DefaultBaseDelay   = 500 * time.Millisecond
+	DefaultMaxDelay    = 10 * time.Second
+)
+
+// RetryPolicy retries requests that failed for transient reasons: network
+// errors, 429 Too Many Requests and 5xx responses. The wait doubles after
+// each attempt with random jitter, and a Retry-After header takes precedence.
+// The zero value makes a single attempt.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry; MaxDelay caps every
+	// wait. A Retry-After longer than MaxDelay is not waited for.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	// Now, Sleep and Rand default to the real clock and math/rand; tests
+	// replace them to make retries deterministic.
+	Now   func() time.Time
+	Sleep func(ctx context.Context, d time.Duration) error
+	// Rand returns a value in [0, 1).
+	Rand func() float64
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: DefaultMaxAttempts,
+		BaseDelay:   DefaultBaseDelay,
+		MaxDelay:    DefaultMaxDelay,
+	}
+}
+
+// Do calls attempt until it succeeds, fails permanently, runs out of
+// attempts or ctx is done, and returns the last error.
+func (p RetryPolicy) Do(ctx context.Context, attempt func(ctx context.Context) error) error {
+	for n := 1; ; n++ {
+		err := attempt(ctx)
+		if err == nil || n >= p.MaxAttempts || !Retryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		delay, ok := p.delay(n, err)
+		if !ok {
+			return err
+		}
+		if sleepErr := p.sleep(ctx, delay); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+// Retryable reports whether err is worth retrying: a network error, a
+// timeout, or a 429 or 5xx response. Cancellation is never retried.
+func Retryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests ||
+			statusErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// delay returns how long to wait after the given attempt, or false when the
+// server asked for a longer wait than MaxDelay.
+func (p RetryPolicy) delay(attempt int, err error) (time.Duration, bool) {
+	if wait, ok := p.retryAfter(err); ok {
+		return wait, p.MaxDelay <= 0 || wait <= p.MaxDelay
+	}
+
+	backoff := p.BaseDelay << (attempt - 1)
+	if p.MaxDelay > 0 && (backoff > p.MaxDelay || backoff <= 0) {
+		backoff = p.MaxDelay
+	}
+
+	// Equal jitter: wait at least half the backoff so retries from many
+	// clients spread out without collapsing to zero.
+	random := rand.Float64
+	if p.Rand != nil {
+		random = p.Rand
+	}
+	half := backoff / 2
+	return half + time.Duration(random()*float64(half)), true
+}
+
+// retryAfter parses a Retry-After header given as seconds or an HTTP date.
+func (p RetryPolicy) retryAfter(err error) (time.Duration, bool) {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return 0, false
+	}
+
+	value := statusErr.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	now := time.Now
+	if p.Now != nil {
+		now = p.Now
+	}
+	return max(date.Sub(now()), 0), true
+}
+
+func (p RetryPolicy) sleep(ctx context.Context, d time.Duration) error {
+	if p.Sleep != nil {
+		return p.Sleep(ctx, d)
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
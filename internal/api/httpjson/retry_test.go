@@ -0,0 +1,221 @@
+package httpjson
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock records requested sleeps instead of waiting.
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	return ctx.Err()
+}
+
+func (c *fakeClock) policy(attempts int) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: attempts,
+		BaseDelay:   time.Second,
+		MaxDelay:    10 * time.Second,
+		Now:         func() time.Time { return c.now },
+		Sleep:       c.Sleep,
+		Rand:        func() float64 { return 0.5 },
+	}
+}
+
+// flakyServer answers with statuses in turn, then 200 with a JSON body.
+func flakyServer(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if int(n) <= len(statuses) {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func get(ctx context.Context, p RetryPolicy, url string) error {
+	return p.Do(ctx, func(ctx context.Context) error {
+		var v any
+		return Get(ctx, NewClient(), url, nil, &v)
+	})
+}
+
+func TestRetryPolicy_BacksOffExponentially(t *testing.T) {
+	server, calls := flakyServer(t, nil, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusInternalServerError)
+	clock := &fakeClock{}
+
+	if err := get(context.Background(), clock.policy(4), server.URL); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	if *calls != 4 {
+		t.Errorf("calls = %d, want 4", *calls)
+	}
+	// Equal jitter with Rand = 0.5 waits three quarters of each backoff.
+	want := []time.Duration{750 * time.Millisecond, 1500 * time.Millisecond, 3 * time.Second}
+	if !reflect.DeepEqual(clock.sleeps, want) {
+		t.Errorf("sleeps = %v, want %v", clock.sleeps, want)
+	}
+}
+
+func TestRetryPolicy_CapsBackoff(t *testing.T) {
+	clock := &fakeClock{}
+	p := clock.policy(6)
+	p.MaxDelay = 3 * time.Second
+
+	failing := func(context.Context) error { return &StatusError{StatusCode: http.StatusServiceUnavailable} }
+	if err := p.Do(context.Background(), failing); err == nil {
+		t.Fatal("Do() error = nil, want last failure")
+	}
+
+	for _, d := range clock.sleeps {
+		if d > p.MaxDelay {
+			t.Errorf("sleep %v exceeds MaxDelay %v", d, p.MaxDelay)
+		}
+	}
+	if len(clock.sleeps) != 5 {
+		t.Errorf("sleeps = %v, want 5 retries", clock.sleeps)
+	}
+}
+
+func TestRetryPolicy_RetryAfter(t *testing.T) {
+	now := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		retryAfter string
+		wantCalls  int32
+		wantSleeps []time.Duration
+	}{
+		{"seconds", "2", 2, []time.Duration{2 * time.Second}},
+		{"http date", now.Add(4 * time.Second).Format(http.TimeFormat), 2, []time.Duration{4 * time.Second}},
+		{"longer than max delay", "3600", 1, nil},
+		{"unparseable uses backoff", "soon", 2, []time.Duration{750 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{"Retry-After": {tt.retryAfter}}
+			server, calls := flakyServer(t, header, http.StatusTooManyRequests)
+			clock := &fakeClock{now: now}
+
+			err := get(context.Background(), clock.policy(3), server.URL)
+
+			if *calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d (err = %v)", *calls, tt.wantCalls, err)
+			}
+			if !reflect.DeepEqual(clock.sleeps, tt.wantSleeps) {
+				t.Errorf("sleeps = %v, want %v", clock.sleeps, tt.wantSleeps)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_DoesNotRetryClientErrors(t *testing.T) {
+	server, calls := flakyServer(t, nil, http.StatusBadRequest)
+	clock := &fakeClock{}
+
+	err := get(context.Background(), clock.policy(3), server.URL)
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("error = %v, want 400 status error", err)
+	}
+	if *calls != 1 || len(clock.sleeps) != 0 {
+		t.Errorf("calls = %d, sleeps = %v; want a single attempt", *calls, clock.sleeps)
+	}
+}
+
+func TestRetryPolicy_GivesUpAfterMaxAttempts(t *testing.T) {
+	server, calls := flakyServer(t, nil, 500, 500, 500, 500)
+	clock := &fakeClock{}
+
+	err := get(context.Background(), clock.policy(3), server.URL)
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != 500 {
+		t.Errorf("error = %v, want last status error", err)
+	}
+	if *calls != 3 {
+		t.Errorf("calls = %d, want 3", *calls)
+	}
+}
+
+func TestRetryPolicy_StopsWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+
+	p := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	done := make(chan error)
+	go func() {
+		done <- p.Do(ctx, func(context.Context) error {
+			attempts++
+			return &StatusError{StatusCode: http.StatusServiceUnavailable}
+		})
+	}()
+
+	// The real sleep would wait an hour; cancellation must cut it short.
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err == nil || attempts != 1 {
+			t.Errorf("Do() = %v after %d attempts, want the first failure", err, attempts)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Do() did not return after cancellation")
+	}
+}
+
+func TestRetryPolicy_ZeroValueMakesOneAttempt(t *testing.T) {
+	attempts := 0
+	_ = RetryPolicy{}.Do(context.Background(), func(context.Context) error {
+		attempts++
+		return &StatusError{StatusCode: http.StatusServiceUnavailable}
+	})
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"server error", &StatusError{StatusCode: 502}, true},
+		{"rate limited", &StatusError{StatusCode: 429}, true},
+		{"bad request", &StatusError{StatusCode: 400}, false},
+		{"deadline", context.DeadlineExceeded, true},
+		{"canceled", context.Canceled, false},
+		{"other", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		if got := Retryable(tt.err); got != tt.want {
+			t.Errorf("Retryable(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
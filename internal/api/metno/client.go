@@ -0,0 +1,241 @@
+// Package metno adapts the MET Norway (api.met.no) locationforecast API to
+// the forecast model.
+package metno
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/api/geocode"
+	"github.com/jtotty/weather-cli/internal/api/httpjson"
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/units"
+)
+
+const baseURL = "https://api.met.no/weatherapi/locationforecast/2.0/complete"
+
+// ProviderName identifies MET Norway in provider lists and output.
+const ProviderName = "met-norway"
+
+// MET Norway's terms of service require an identifying User-Agent.
+const userAgent = "weather-cli github.com/jtotty/weather-cli"
+
+type Client struct {
+	httpClient *http.Client
+	geocoder   geocode.Resolver
+	baseURL    string
+}
+
+func NewClient() *Client {
+	return &Client{
+		httpClient: httpjson.NewClient(),
+		geocoder:   geocode.NewClient(),
+		baseURL:    baseURL,
+	}
+}
+
+// WithGeocoder sets how locations are resolved to coordinates.
+func (c *Client) WithGeocoder(r geocode.Resolver) *Client {
+	c.geocoder = r
+	return c
+}
+
+func (c *Client) Name() string {
+	return ProviderName
+}
+
+func (c *Client) Fetch(ctx context.Context, opts forecast.Options) (*forecast.Response, error) {
+	place, err := c.geocoder.Resolve(ctx, opts.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("lat", strconv.FormatFloat(place.Lat, 'f', 4, 64))
+	params.Add("lon", strconv.FormatFloat(place.Lon, 'f', 4, 64))
+	reqURL := fmt.Sprintf("%s?%s", c.baseURL, params.Encode())
+
+	header := http.Header{}
+	header.Set("User-Agent", userAgent)
+
+	var res forecastResponse
+	if err := httpjson.Get(ctx, c.httpClient, reqURL, header, &res); err != nil {
+		return nil, err
+	}
+
+	if len(res.Properties.Timeseries) == 0 {
+		return nil, fmt.Errorf("MET Norway returned no forecast data")
+	}
+
+	return res.toResponse(place, opts.Days), nil
+}
+
+type forecastResponse struct {
+	Properties struct {
+		Timeseries []timestep `json:"timeseries"`
+	} `json:"properties"`
+}
+
+type timestep struct {
+	Time time.Time `json:"time"`
+	Data struct {
+		Instant struct {
+			Details struct {
+				AirPressure       float32 `json:"air_pressure_at_sea_level"`
+				AirTemperature    float32 `json:"air_temperature"`
+				RelativeHumidity  float32 `json:"relative_humidity"`
+				WindFromDirection float64 `json:"wind_from_direction"`
+				WindSpeed         float32 `json:"wind_speed"`
+			} `json:"details"`
+		} `json:"instant"`
+		Next1Hours *period `json:"next_1_hours"`
+		Next6Hours *period `json:"next_6_hours"`
+	} `json:"data"`
+}
+
+type period struct {
+	Summary struct {
+		SymbolCode string `json:"symbol_code"`
+	} `json:"summary"`
+	Details struct {
+		PrecipitationAmount        float32 `json:"precipitation_amount"`
+		ProbabilityOfPrecipitation float32 `json:"probability_of_precipitation"`
+	} `json:"details"`
+}
+
+// next returns the shortest forecast period available for the step.
+func (s *timestep) next() *period {
+	if s.Data.Next1Hours != nil {
+		return s.Data.Next1Hours
+	}
+	return s.Data.Next6Hours
+}
+
+func (r *forecastResponse) toResponse(place geocode.Place, days int) *forecast.Response {
+	loc := time.UTC
+	if place.Timezone != "" {
+		if tz, err := time.LoadLocation(place.Timezone); err == nil {
+			loc = tz
+		}
+	}
+
+	series := r.Properties.Timeseries
+	first := series[0]
+	details := first.Data.Instant.Details
+
+	out := &forecast.Response{
+		Location: forecast.Location{
+			Name:      place.Name,
+			Country:   place.Country,
+			Lat:       place.Lat,
+			Lon:       place.Lon,
+			TimeZone:  place.Timezone,
+			LocalTime: first.Time.In(loc).Format("2006-01-02 15:04"),
+		},
+		Current: forecast.Current{
+			TempC:         details.AirTemperature,
+			Humidity:      forecast.Ptr(details.RelativeHumidity),
+			WindMph:       forecast.Ptr(units.MetersPerSecondToMph(details.WindSpeed)),
+			WindDegree:    forecast.Ptr(int(details.WindFromDirection)),
+			WindDirection: forecast.CompassDirection(details.WindFromDirection),
+			PressureMb:    forecast.Ptr(details.AirPressure),
+		},
+	}
+
+	if p := first.next(); p != nil {
+		out.Current.Condition.Text = conditionText(p.Summary.SymbolCode)
+		out.Current.PrecipMm = forecast.Ptr(p.Details.PrecipitationAmount)
+	}
+
+	var builders []*dayBuilder
+	for i := range series {
+		step := &series[i]
+		date := step.Time.In(loc).Format("2006-01-02")
+
+		if len(builders) == 0 || builders[len(builders)-1].day.Date != date {
+			if len(builders) == days {
+				break
+			}
+			builders = append(builders, newDayBuilder(date))
+		}
+
+		builders[len(builders)-1].add(step, loc)
+	}
+
+	for _, b := range builders {
+		out.Days = append(out.Days, b.day)
+	}
+
+	out.FillUnitVariants()
+	return out
+}
+
+// dayBuilder aggregates the timesteps falling on one local date.
+type dayBuilder struct {
+	day       forecast.Day
+	hasMidday bool
+}
+
+func newDayBuilder(date string) *dayBuilder {
+	return &dayBuilder{
+		day: forecast.Day{
+			Date: date,
+			Summary: forecast.Summary{
+				MaxTempC: -1000,
+				MinTempC: 1000,
+			},
+		},
+	}
+}
+
+func (b *dayBuilder) add(step *timestep, loc *time.Location) {
+	details := step.Data.Instant.Details
+	d := &b.day.Summary
+
+	d.MaxTempC = max(d.MaxTempC, details.AirTemperature)
+	d.MinTempC = min(d.MinTempC, details.AirTemperature)
+	d.AvgTempC = (d.MaxTempC + d.MinTempC) / 2
+	d.MaxWindMph = forecast.Ptr(max(value(d.MaxWindMph), units.MetersPerSecondToMph(details.WindSpeed)))
+
+	p := step.next()
+	if p == nil {
+		return
+	}
+
+	condition := conditionText(p.Summary.SymbolCode)
+	d.TotalPrecipMm = forecast.Ptr(p.Details.PrecipitationAmount + value(d.TotalPrecipMm))
+	d.ChanceOfRain = forecast.Ptr(max(value(d.ChanceOfRain), p.Details.ProbabilityOfPrecipitation))
+
+	// The first period from midday onwards best represents the day.
+	if !b.hasMidday {
+		d.Condition.Text = condition
+		b.hasMidday = step.Time.In(loc).Hour() >= 12
+	}
+
+	if step.Data.Next1Hours != nil {
+		b.day.Hours = append(b.day.Hours, forecast.Hour{
+			TimeUnix:      step.Time.Unix(),
+			TempC:         details.AirTemperature,
+			ChanceOfRain:  forecast.Ptr(p.Details.ProbabilityOfPrecipitation),
+			Condition:     forecast.Condition{Text: condition},
+			WindMph:       forecast.Ptr(units.MetersPerSecondToMph(details.WindSpeed)),
+			WindDegree:    forecast.Ptr(int(details.WindFromDirection)),
+			WindDirection: forecast.CompassDirection(details.WindFromDirection),
+			PressureMb:    forecast.Ptr(details.AirPressure),
+			PrecipMm:      forecast.Ptr(p.Details.PrecipitationAmount),
+			Humidity:      forecast.Ptr(details.RelativeHumidity),
+		})
+	}
+}
+
+// value returns *v, or zero when v is unset.
+func value(v *float32) float32 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
@@ -0,0 +1,111 @@
+package metno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jtotty/weather-cli/internal/api/geocode"
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+var london = geocode.Place{
+	Name:     "London",
+	Country:  "United Kingdom",
+	Lat:      51.5,
+	Lon:      -0.12,
+	Timezone: "Europe/London",
+}
+
+func TestFetch_Fixture(t *testing.T) {
+	body, err := os.ReadFile("testdata/complete.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("User-Agent"), "weather-cli") {
+			t.Errorf("User-Agent = %q, want weather-cli identifier", r.Header.Get("User-Agent"))
+		}
+		if r.URL.Query().Get("lat") != "51.5000" || r.URL.Query().Get("lon") != "-0.1200" {
+			t.Errorf("unexpected coordinates: %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	res, err := NewTestClient(server.URL).WithGeocoder(geocode.Static{Place: london}).Fetch(context.Background(), forecast.Options{Location: "London", Days: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Location.LocalTime != "2024-01-18 12:00" {
+		t.Errorf("LocalTime = %q, want %q", res.Location.LocalTime, "2024-01-18 12:00")
+	}
+	if res.Current.TempC != 8.8 {
+		t.Errorf("Current.TempC = %v, want 8.8", res.Current.TempC)
+	}
+	if res.Current.Condition.Text != "Cloudy" {
+		t.Errorf("Current.Condition = %q, want %q", res.Current.Condition.Text, "Cloudy")
+	}
+	if res.Current.WindDirection != "SW" {
+		t.Errorf("Current.WindDirection = %q, want %q", res.Current.WindDirection, "SW")
+	}
+
+	days := res.Days
+	if len(days) != 3 {
+		t.Fatalf("len(Days) = %d, want 3", len(days))
+	}
+
+	wantHours := []int{12, 24, 0}
+	for i, want := range wantHours {
+		if got := len(days[i].Hours); got != want {
+			t.Errorf("day %d hours = %d, want %d", i, got, want)
+		}
+	}
+
+	if days[1].Summary.MaxTempC < days[1].Summary.MinTempC {
+		t.Errorf("day 1 max %v < min %v", days[1].Summary.MaxTempC, days[1].Summary.MinTempC)
+	}
+	if rain := days[1].Summary.ChanceOfRain; rain == nil || *rain != 48.2 {
+		t.Errorf("day 1 ChanceOfRain = %v, want 48.2", rain)
+	}
+	if h := days[0].Hours[0]; h.WindMph == nil || h.Humidity == nil || h.UV != nil || h.Cloud != nil {
+		t.Errorf("Hours[0] = %+v, want wind and humidity but no UV or cloud", h)
+	}
+	if res.Current.UV != nil || res.Current.AirQuality != nil || res.Current.FeelsLikeC != nil {
+		t.Error("Current UV, air quality and feels-like should be unset, MET Norway does not report them")
+	}
+}
+
+func TestConditionText(t *testing.T) {
+	tests := []struct {
+		symbol string
+		want   string
+	}{
+		{"clearsky_day", "Sunny"},
+		{"clearsky_night", "Clear"},
+		{"partlycloudy_polartwilight", "Partly cloudy"},
+		{"heavyrainandthunder", "Moderate or heavy rain with thunder"},
+		{"unknown", "Cloudy"},
+	}
+
+	for _, tt := range tests {
+		if got := conditionText(tt.symbol); got != tt.want {
+			t.Errorf("conditionText(%q) = %q, want %q", tt.symbol, got, tt.want)
+		}
+	}
+}
+
+func TestFetch_LocationNotFound(t *testing.T) {
+	notFound := fmt.Errorf("%w: Atlantis", geocode.ErrNotFound)
+	_, err := NewClient().WithGeocoder(geocode.Static{Err: notFound}).Fetch(context.Background(), forecast.Options{Location: "Atlantis", Days: 1})
+	if !errors.Is(err, forecast.ErrLocationNotFound) {
+		t.Errorf("error = %v, want forecast.ErrLocationNotFound", err)
+	}
+}
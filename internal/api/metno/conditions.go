@@ -0,0 +1,63 @@
+package metno
+
+import "strings"
+
+// symbolConditions maps MET Norway symbol codes (without their _day, _night
+// or _polartwilight suffix) to the condition text weatherapi.com uses.
+var symbolConditions = map[string]string{
+	"clearsky":                     "Sunny",
+	"fair":                         "Partly cloudy",
+	"partlycloudy":                 "Partly cloudy",
+	"cloudy":                       "Cloudy",
+	"fog":                          "Fog",
+	"lightrain":                    "Light rain",
+	"rain":                         "Moderate rain",
+	"heavyrain":                    "Heavy rain",
+	"lightrainshowers":             "Light rain shower",
+	"rainshowers":                  "Moderate or heavy rain shower",
+	"heavyrainshowers":             "Torrential rain shower",
+	"lightsleet":                   "Light sleet",
+	"sleet":                        "Moderate or heavy sleet",
+	"heavysleet":                   "Moderate or heavy sleet",
+	"lightsleetshowers":            "Light sleet showers",
+	"sleetshowers":                 "Moderate or heavy sleet showers",
+	"heavysleetshowers":            "Moderate or heavy sleet showers",
+	"lightsnow":                    "Light snow",
+	"snow":                         "Moderate snow",
+	"heavysnow":                    "Heavy snow",
+	"lightsnowshowers":             "Light snow showers",
+	"snowshowers":                  "Moderate or heavy snow showers",
+	"heavysnowshowers":             "Moderate or heavy snow showers",
+	"lightrainandthunder":          "Patchy light rain with thunder",
+	"lightrainshowersandthunder":   "Patchy light rain with thunder",
+	"rainandthunder":               "Moderate or heavy rain with thunder",
+	"rainshowersandthunder":        "Moderate or heavy rain with thunder",
+	"heavyrainandthunder":          "Moderate or heavy rain with thunder",
+	"heavyrainshowersandthunder":   "Moderate or heavy rain with thunder",
+	"lightsnowandthunder":          "Patchy light snow with thunder",
+	"lightssnowshowersandthunder":  "Patchy light snow with thunder",
+	"snowandthunder":               "Moderate or heavy snow with thunder",
+	"snowshowersandthunder":        "Moderate or heavy snow with thunder",
+	"heavysnowandthunder":          "Moderate or heavy snow with thunder",
+	"heavysnowshowersandthunder":   "Moderate or heavy snow with thunder",
+	"lightsleetandthunder":         "Patchy light rain with thunder",
+	"sleetandthunder":              "Moderate or heavy rain with thunder",
+	"heavysleetandthunder":         "Moderate or heavy rain with thunder",
+	"lightssleetshowersandthunder": "Patchy light rain with thunder",
+	"sleetshowersandthunder":       "Moderate or heavy rain with thunder",
+	"heavysleetshowersandthunder":  "Moderate or heavy rain with thunder",
+}
+
+func conditionText(symbol string) string {
+	base, variant, _ := strings.Cut(symbol, "_")
+
+	if base == "clearsky" && variant != "day" {
+		return "Clear"
+	}
+
+	if text, ok := symbolConditions[base]; ok {
+		return text
+	}
+
+	return "Cloudy"
+}
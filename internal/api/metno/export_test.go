@@ -0,0 +1,8 @@
+package metno
+
+// NewTestClient creates a client with a custom base URL for testing
+func NewTestClient(baseURL string) *Client {
+	c := NewClient()
+	c.baseURL = baseURL
+	return c
+}
@@ -0,0 +1,283 @@
+// Package nws adapts the US National Weather Service (api.weather.gov) API
+// to the forecast model.
+package nws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/api/geocode"
+	"github.com/jtotty/weather-cli/internal/api/httpjson"
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/units"
+)
+
+const baseURL = "https://api.weather.gov"
+
+// ProviderName identifies the National Weather Service in provider lists and output.
+const ProviderName = "nws"
+
+// api.weather.gov rejects requests without an identifying User-Agent.
+const userAgent = "weather-cli github.com/jtotty/weather-cli"
+
+var ErrOutsideCoverage = errors.New("the National Weather Service only covers the United States")
+
+type Client struct {
+	httpClient *http.Client
+	geocoder   geocode.Resolver
+	baseURL    string
+}
+
+func NewClient() *Client {
+	return &Client{
+		httpClient: httpjson.NewClient(),
+		geocoder:   geocode.NewClient(),
+		baseURL:    baseURL,
+	}
+}
+
+// WithGeocoder sets how locations are resolved to coordinates.
+func (c *Client) WithGeocoder(r geocode.Resolver) *Client {
+	c.geocoder = r
+	return c
+}
+
+func (c *Client) Name() string {
+	return ProviderName
+}
+
+func (c *Client) Fetch(ctx context.Context, opts forecast.Options) (*forecast.Response, error) {
+	place, err := c.geocoder.Resolve(ctx, opts.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	var points pointsResponse
+	if err := c.get(ctx, c.baseURL+"/points/"+geocode.FormatCoordinates(place.Lat, place.Lon), &points); err != nil {
+		var statusErr *httpjson.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			return nil, ErrOutsideCoverage
+		}
+		return nil, err
+	}
+
+	var daily, hourly forecastResponse
+	if err := c.get(ctx, points.Properties.Forecast, &daily); err != nil {
+		return nil, err
+	}
+	if err := c.get(ctx, points.Properties.ForecastHourly, &hourly); err != nil {
+		return nil, err
+	}
+
+	var alerts alertsResponse
+	if opts.Alerts {
+		params := url.Values{}
+		params.Add("point", geocode.FormatCoordinates(place.Lat, place.Lon))
+		if err := c.get(ctx, c.baseURL+"/alerts/active?"+params.Encode(), &alerts); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(hourly.Properties.Periods) == 0 {
+		return nil, fmt.Errorf("NWS returned no forecast data")
+	}
+
+	out := toResponse(&points, &daily, &hourly, &alerts, opts.Days)
+	out.Location.Lat, out.Location.Lon = place.Lat, place.Lon
+	return out, nil
+}
+
+func (c *Client) get(ctx context.Context, reqURL string, v any) error {
+	header := http.Header{}
+	header.Set("User-Agent", userAgent)
+	header.Set("Accept", "application/geo+json")
+
+	return httpjson.Get(ctx, c.httpClient, reqURL, header, v)
+}
+
+type pointsResponse struct {
+	Properties struct {
+		Forecast         string `json:"forecast"`
+		ForecastHourly   string `json:"forecastHourly"`
+		TimeZone         string `json:"timeZone"`
+		RelativeLocation struct {
+			Properties struct {
+				City  string `json:"city"`
+				State string `json:"state"`
+			} `json:"properties"`
+		} `json:"relativeLocation"`
+	} `json:"properties"`
+}
+
+type forecastResponse struct {
+	Properties struct {
+		Periods []forecastPeriod `json:"periods"`
+	} `json:"properties"`
+}
+
+type quantity struct {
+	Value *float64 `json:"value"`
+}
+
+func (q quantity) float() float32 {
+	if q.Value == nil {
+		return 0
+	}
+	return float32(*q.Value)
+}
+
+type forecastPeriod struct {
+	StartTime                  time.Time `json:"startTime"`
+	IsDaytime                  bool      `json:"isDaytime"`
+	Temperature                float64   `json:"temperature"`
+	TemperatureUnit            string    `json:"temperatureUnit"`
+	ProbabilityOfPrecipitation quantity  `json:"probabilityOfPrecipitation"`
+	RelativeHumidity           quantity  `json:"relativeHumidity"`
+	WindSpeed                  string    `json:"windSpeed"`
+	WindDirection              string    `json:"windDirection"`
+	ShortForecast              string    `json:"shortForecast"`
+}
+
+func (p *forecastPeriod) tempC() float32 {
+	if p.TemperatureUnit == "C" {
+		return float32(p.Temperature)
+	}
+	return units.FahrenheitToCelsius(float32(p.Temperature))
+}
+
+func (p *forecastPeriod) date() string {
+	return p.StartTime.Format("2006-01-02")
+}
+
+type alertsResponse struct {
+	Features []struct {
+		Properties struct {
+			Event       string `json:"event"`
+			Description string `json:"description"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+func toResponse(points *pointsResponse, daily, hourly *forecastResponse, alerts *alertsResponse, days int) *forecast.Response {
+	hours := hourly.Properties.Periods
+	now := &hours[0]
+
+	out := &forecast.Response{
+		Location: forecast.Location{
+			Name:      points.Properties.RelativeLocation.Properties.City,
+			Country:   "United States",
+			TimeZone:  points.Properties.TimeZone,
+			LocalTime: now.StartTime.Format("2006-01-02 15:04"),
+		},
+		Current: forecast.Current{
+			TempC:         now.tempC(),
+			IsDay:         now.IsDaytime,
+			Humidity:      forecast.Ptr(now.RelativeHumidity.float()),
+			WindMph:       forecast.Ptr(parseWindSpeed(now.WindSpeed)),
+			WindDirection: now.WindDirection,
+			Condition:     forecast.Condition{Text: conditionText(now.ShortForecast, now.IsDaytime)},
+		},
+	}
+
+	index := make(map[string]int)
+	dayFor := func(date string) *forecast.Day {
+		if i, ok := index[date]; ok {
+			return &out.Days[i]
+		}
+		if len(out.Days) == days {
+			return nil
+		}
+		index[date] = len(out.Days)
+		out.Days = append(out.Days, forecast.Day{
+			Date:    date,
+			Summary: forecast.Summary{MaxTempC: -1000, MinTempC: 1000},
+		})
+		return &out.Days[len(out.Days)-1]
+	}
+
+	for i := range hours {
+		h := &hours[i]
+		day := dayFor(h.date())
+		if day == nil {
+			break
+		}
+
+		temp, s := h.tempC(), &day.Summary
+		s.MaxTempC = max(s.MaxTempC, temp)
+		s.MinTempC = min(s.MinTempC, temp)
+		s.ChanceOfRain = maxOf(s.ChanceOfRain, h.ProbabilityOfPrecipitation.float())
+		s.MaxWindMph = maxOf(s.MaxWindMph, parseWindSpeed(h.WindSpeed))
+		day.Hours = append(day.Hours, forecast.Hour{
+			TimeUnix:      h.StartTime.Unix(),
+			TempC:         temp,
+			IsDay:         h.IsDaytime,
+			ChanceOfRain:  forecast.Ptr(h.ProbabilityOfPrecipitation.float()),
+			Condition:     forecast.Condition{Text: conditionText(h.ShortForecast, h.IsDaytime)},
+			WindMph:       forecast.Ptr(parseWindSpeed(h.WindSpeed)),
+			WindDirection: h.WindDirection,
+			Humidity:      forecast.Ptr(h.RelativeHumidity.float()),
+		})
+	}
+
+	// The 12-hour periods carry NWS's official highs, lows and summaries.
+	for i := range daily.Properties.Periods {
+		p := &daily.Properties.Periods[i]
+		day := dayFor(p.date())
+		if day == nil {
+			break
+		}
+
+		s := &day.Summary
+		if p.IsDaytime {
+			s.MaxTempC = p.tempC()
+			s.Condition.Text = conditionText(p.ShortForecast, true)
+		} else {
+			s.MinTempC = min(s.MinTempC, p.tempC())
+			if s.Condition.Text == "" {
+				s.Condition.Text = conditionText(p.ShortForecast, false)
+			}
+		}
+		s.ChanceOfRain = maxOf(s.ChanceOfRain, p.ProbabilityOfPrecipitation.float())
+	}
+
+	for i := range out.Days {
+		d := &out.Days[i].Summary
+		d.AvgTempC = (d.MaxTempC + d.MinTempC) / 2
+	}
+
+	out.FillUnitVariants()
+
+	for _, f := range alerts.Features {
+		out.Alerts = append(out.Alerts, forecast.Alert{
+			Event:       f.Properties.Event,
+			Description: f.Properties.Description,
+		})
+	}
+
+	return out
+}
+
+// maxOf returns the larger of an optional reading and v.
+func maxOf(reading *float32, v float32) *float32 {
+	if reading != nil && *reading > v {
+		return reading
+	}
+	return forecast.Ptr(v)
+}
+
+// parseWindSpeed extracts the upper bound from NWS strings like "5 to 10 mph".
+func parseWindSpeed(s string) float32 {
+	fields := strings.Fields(s)
+	for i := len(fields) - 1; i >= 0; i-- {
+		if v, err := strconv.ParseFloat(fields[i], 32); err == nil {
+			return float32(v)
+		}
+	}
+	return 0
+}
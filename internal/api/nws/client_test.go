@@ -0,0 +1,162 @@
+package nws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jtotty/weather-cli/internal/api/geocode"
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+var washington = geocode.Place{Lat: 38.8894, Lon: -77.0352}
+
+// newFixtureServer serves the recorded NWS responses, rewriting the absolute
+// api.weather.gov URLs in the points document to point back at the server.
+func newFixtureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	read := func(name string) []byte {
+		body, err := os.ReadFile("testdata/" + name)
+		if err != nil {
+			t.Fatalf("failed to read fixture: %v", err)
+		}
+		return body
+	}
+
+	points, daily, hourly, alerts := read("points.json"), read("forecast.json"), read("forecast_hourly.json"), read("alerts.json")
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") == "" {
+			t.Error("request missing User-Agent")
+		}
+
+		switch {
+		case r.URL.Path == "/points/38.8894,-77.0352":
+			_, _ = w.Write([]byte(strings.ReplaceAll(string(points), "https://api.weather.gov", server.URL)))
+		case r.URL.Path == "/gridpoints/LWX/97,71/forecast":
+			_, _ = w.Write(daily)
+		case r.URL.Path == "/gridpoints/LWX/97,71/forecast/hourly":
+			_, _ = w.Write(hourly)
+		case r.URL.Path == "/alerts/active":
+			_, _ = w.Write(alerts)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestFetch_Fixture(t *testing.T) {
+	server := newFixtureServer(t)
+
+	res, err := NewTestClient(server.URL).WithGeocoder(geocode.Static{Place: washington}).Fetch(context.Background(), forecast.Options{
+		Location: "38.8894,-77.0352",
+		Days:     3,
+		Alerts:   true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Location.Name != "Washington" {
+		t.Errorf("Location.Name = %q, want %q", res.Location.Name, "Washington")
+	}
+	if res.Location.LocalTime != "2024-01-18 13:00" {
+		t.Errorf("LocalTime = %q, want %q", res.Location.LocalTime, "2024-01-18 13:00")
+	}
+	if res.Current.Condition.Text != "Light rain shower" {
+		t.Errorf("Current.Condition = %q, want %q", res.Current.Condition.Text, "Light rain shower")
+	}
+	if c := res.Current; *c.WindMph != 7 || *c.Humidity != 62 {
+		t.Errorf("Current wind/humidity = %v/%v, want 7/62", *c.WindMph, *c.Humidity)
+	}
+
+	days := res.Days
+	if len(days) != 3 {
+		t.Fatalf("len(Days) = %d, want 3", len(days))
+	}
+	if len(days[0].Hours) != 11 || len(days[1].Hours) != 24 || len(days[2].Hours) != 0 {
+		t.Errorf("hours per day = %d, %d, %d, want 11, 24, 0", len(days[0].Hours), len(days[1].Hours), len(days[2].Hours))
+	}
+
+	// Saturday: high 45°F, low 29°F
+	if math.Abs(float64(days[2].Summary.MaxTempC)-7.2) > 0.1 || math.Abs(float64(days[2].Summary.MinTempC)+1.7) > 0.1 {
+		t.Errorf("Saturday high/low = %v/%v, want 7.2/-1.7", days[2].Summary.MaxTempC, days[2].Summary.MinTempC)
+	}
+	if days[2].Summary.Condition.Text != "Partly cloudy" {
+		t.Errorf("Saturday condition = %q, want %q", days[2].Summary.Condition.Text, "Partly cloudy")
+	}
+
+	if res.Current.PressureMb != nil || days[0].Summary.TotalPrecipMm != nil || days[0].Summary.UV != nil {
+		t.Error("pressure, precipitation and UV should be unset, NWS forecasts do not report them")
+	}
+
+	if len(res.Alerts) != 1 || res.Alerts[0].Event != "Wind Advisory" {
+		t.Errorf("Alerts = %+v, want one Wind Advisory", res.Alerts)
+	}
+}
+
+func TestFetch_OutsideCoverage(t *testing.T) {
+	server := newFixtureServer(t)
+
+	_, err := NewTestClient(server.URL).WithGeocoder(geocode.Static{Place: geocode.Place{Lat: 51.5, Lon: -0.12}}).Fetch(context.Background(), forecast.Options{Location: "London", Days: 1})
+	if !errors.Is(err, ErrOutsideCoverage) {
+		t.Errorf("error = %v, want ErrOutsideCoverage", err)
+	}
+}
+
+func TestParseWindSpeed(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float32
+	}{
+		{"10 mph", 10},
+		{"5 to 15 mph", 15},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseWindSpeed(tt.input); got != tt.want {
+			t.Errorf("parseWindSpeed(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestConditionText(t *testing.T) {
+	tests := []struct {
+		input string
+		isDay bool
+		want  string
+	}{
+		{"Sunny", true, "Sunny"},
+		{"Mostly Clear", false, "Partly cloudy"},
+		{"Clear", false, "Clear"},
+		{"Slight Chance Showers And Thunderstorms", true, "Thundery outbreaks possible"},
+		{"Heavy Snow", true, "Heavy snow"},
+		{"Areas Of Fog", false, "Fog"},
+	}
+
+	for _, tt := range tests {
+		if got := conditionText(tt.input, tt.isDay); got != tt.want {
+			t.Errorf("conditionText(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFetch_LocationNotFound(t *testing.T) {
+	notFound := fmt.Errorf("%w: Atlantis", geocode.ErrNotFound)
+	_, err := NewClient().WithGeocoder(geocode.Static{Err: notFound}).Fetch(context.Background(), forecast.Options{Location: "Atlantis", Days: 1})
+	if !errors.Is(err, forecast.ErrLocationNotFound) {
+		t.Errorf("error = %v, want forecast.ErrLocationNotFound", err)
+	}
+}
@@ -0,0 +1,52 @@
+package nws
+
+import "strings"
+
+// conditionKeywords maps phrases in NWS short forecasts to the condition text
+// weatherapi.com uses. Order matters: the first matching phrase wins.
+var conditionKeywords = []struct {
+	keyword string
+	text    string
+}{
+	{"thunder", "Moderate or heavy rain with thunder"},
+	{"blizzard", "Blizzard"},
+	{"freezing rain", "Light freezing rain"},
+	{"freezing drizzle", "Freezing drizzle"},
+	{"sleet", "Light sleet"},
+	{"heavy snow", "Heavy snow"},
+	{"snow showers", "Light snow showers"},
+	{"snow", "Light snow"},
+	{"heavy rain", "Heavy rain"},
+	{"showers", "Light rain shower"},
+	{"drizzle", "Light drizzle"},
+	{"rain", "Light rain"},
+	{"freezing fog", "Freezing fog"},
+	{"fog", "Fog"},
+	{"haze", "Mist"},
+	{"smoke", "Mist"},
+	{"overcast", "Overcast"},
+	{"mostly cloudy", "Cloudy"},
+	{"partly", "Partly cloudy"},
+	{"mostly sunny", "Partly cloudy"},
+	{"mostly clear", "Partly cloudy"},
+	{"cloudy", "Cloudy"},
+}
+
+func conditionText(shortForecast string, isDay bool) string {
+	lower := strings.ToLower(shortForecast)
+
+	if strings.Contains(lower, "chance") && strings.Contains(lower, "thunder") {
+		return "Thundery outbreaks possible"
+	}
+
+	for _, ck := range conditionKeywords {
+		if strings.Contains(lower, ck.keyword) {
+			return ck.text
+		}
+	}
+
+	if isDay {
+		return "Sunny"
+	}
+	return "Clear"
+}
@@ -0,0 +1,212 @@
+// Package openmeteo adapts the Open-Meteo forecast API to the forecast model.
+package openmeteo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/api/geocode"
+	"github.com/jtotty/weather-cli/internal/api/httpjson"
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+const baseURL = "https://api.open-meteo.com/v1/forecast"
+
+// ProviderName identifies Open-Meteo in provider lists and output.
+const ProviderName = "open-meteo"
+
+// Open-Meteo serves at most 16 days of forecast.
+const maxDays = 16
+
+type Client struct {
+	httpClient *http.Client
+	geocoder   geocode.Resolver
+	baseURL    string
+}
+
+func NewClient() *Client {
+	return &Client{
+		httpClient: httpjson.NewClient(),
+		geocoder:   geocode.NewClient(),
+		baseURL:    baseURL,
+	}
+}
+
+// WithGeocoder sets how locations are resolved to coordinates.
+func (c *Client) WithGeocoder(r geocode.Resolver) *Client {
+	c.geocoder = r
+	return c
+}
+
+func (c *Client) Name() string {
+	return ProviderName
+}
+
+func (c *Client) Fetch(ctx context.Context, opts forecast.Options) (*forecast.Response, error) {
+	place, err := c.geocoder.Resolve(ctx, opts.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	var res forecastResponse
+	if err := httpjson.Get(ctx, c.httpClient, c.buildURL(place, opts.Days), nil, &res); err != nil {
+		return nil, err
+	}
+
+	return res.toResponse(place), nil
+}
+
+func (c *Client) buildURL(place geocode.Place, days int) string {
+	if days < 1 {
+		days = 1
+	}
+	if days > maxDays {
+		days = maxDays
+	}
+
+	params := url.Values{}
+	params.Add("latitude", strconv.FormatFloat(place.Lat, 'f', 4, 64))
+	params.Add("longitude", strconv.FormatFloat(place.Lon, 'f', 4, 64))
+	params.Add("current", "temperature_2m,apparent_temperature,relative_humidity_2m,wind_speed_10m,wind_direction_10m,weather_code,is_day,pressure_msl,precipitation")
+	params.Add("hourly", "temperature_2m,precipitation_probability,weather_code,is_day")
+	params.Add("daily", "weather_code,temperature_2m_max,temperature_2m_min,precipitation_sum,precipitation_probability_max,wind_speed_10m_max,sunrise,sunset,uv_index_max")
+	params.Add("wind_speed_unit", "mph")
+	params.Add("timeformat", "unixtime")
+	params.Add("timezone", "auto")
+	params.Add("forecast_days", strconv.Itoa(days))
+
+	return fmt.Sprintf("%s?%s", c.baseURL, params.Encode())
+}
+
+type forecastResponse struct {
+	UTCOffsetSeconds int64  `json:"utc_offset_seconds"`
+	Timezone         string `json:"timezone"`
+	Current          struct {
+		Time                int64   `json:"time"`
+		Temperature         float32 `json:"temperature_2m"`
+		ApparentTemperature float32 `json:"apparent_temperature"`
+		RelativeHumidity    float32 `json:"relative_humidity_2m"`
+		WindSpeed           float32 `json:"wind_speed_10m"`
+		WindDirection       float64 `json:"wind_direction_10m"`
+		WeatherCode         int     `json:"weather_code"`
+		IsDay               int     `json:"is_day"`
+		PressureMSL         float32 `json:"pressure_msl"`
+		Precipitation       float32 `json:"precipitation"`
+	} `json:"current"`
+	Hourly struct {
+		Time                     []int64   `json:"time"`
+		Temperature              []float32 `json:"temperature_2m"`
+		PrecipitationProbability []float32 `json:"precipitation_probability"`
+		WeatherCode              []int     `json:"weather_code"`
+		IsDay                    []int     `json:"is_day"`
+	} `json:"hourly"`
+	Daily struct {
+		Time                        []int64   `json:"time"`
+		WeatherCode                 []int     `json:"weather_code"`
+		TemperatureMax              []float32 `json:"temperature_2m_max"`
+		TemperatureMin              []float32 `json:"temperature_2m_min"`
+		PrecipitationSum            []float32 `json:"precipitation_sum"`
+		PrecipitationProbabilityMax []int     `json:"precipitation_probability_max"`
+		WindSpeedMax                []float32 `json:"wind_speed_10m_max"`
+		Sunrise                     []int64   `json:"sunrise"`
+		Sunset                      []int64   `json:"sunset"`
+		UVIndexMax                  []float32 `json:"uv_index_max"`
+	} `json:"daily"`
+}
+
+// localTime shifts a unix timestamp into the location's wall clock.
+func (r *forecastResponse) localTime(epoch int64) time.Time {
+	return time.Unix(epoch+r.UTCOffsetSeconds, 0).UTC()
+}
+
+func (r *forecastResponse) toResponse(place geocode.Place) *forecast.Response {
+	cur := r.Current
+
+	out := &forecast.Response{
+		Location: forecast.Location{
+			Name:      place.Name,
+			Country:   place.Country,
+			Lat:       place.Lat,
+			Lon:       place.Lon,
+			TimeZone:  r.Timezone,
+			LocalTime: r.localTime(cur.Time).Format("2006-01-02 15:04"),
+		},
+		Current: forecast.Current{
+			TempC:         cur.Temperature,
+			IsDay:         cur.IsDay == 1,
+			FeelsLikeC:    forecast.Ptr(cur.ApparentTemperature),
+			Humidity:      forecast.Ptr(cur.RelativeHumidity),
+			WindMph:       forecast.Ptr(cur.WindSpeed),
+			WindDegree:    forecast.Ptr(int(cur.WindDirection)),
+			WindDirection: forecast.CompassDirection(cur.WindDirection),
+			PressureMb:    forecast.Ptr(cur.PressureMSL),
+			PrecipMm:      forecast.Ptr(cur.Precipitation),
+			Condition:     forecast.Condition{Text: conditionText(cur.WeatherCode, cur.IsDay == 1)},
+		},
+	}
+
+	hoursByDate := r.hoursByDate()
+
+	d := r.Daily
+	for i, epoch := range d.Time {
+		date := r.localTime(epoch).Format("2006-01-02")
+
+		day := forecast.Day{
+			Date: date,
+			Summary: forecast.Summary{
+				MaxTempC:      at(d.TemperatureMax, i),
+				MinTempC:      at(d.TemperatureMin, i),
+				AvgTempC:      (at(d.TemperatureMax, i) + at(d.TemperatureMin, i)) / 2,
+				MaxWindMph:    forecast.Ptr(at(d.WindSpeedMax, i)),
+				TotalPrecipMm: forecast.Ptr(at(d.PrecipitationSum, i)),
+				ChanceOfRain:  forecast.Ptr(float32(at(d.PrecipitationProbabilityMax, i))),
+				Condition:     forecast.Condition{Text: conditionText(at(d.WeatherCode, i), true)},
+				UV:            forecast.Ptr(at(d.UVIndexMax, i)),
+			},
+			Hours: hoursByDate[date],
+		}
+
+		if sunrise, sunset := at(d.Sunrise, i), at(d.Sunset, i); sunrise != 0 && sunset != 0 {
+			day.Astro = forecast.Astro{
+				Sunrise: r.localTime(sunrise).Format("03:04 PM"),
+				Sunset:  r.localTime(sunset).Format("03:04 PM"),
+			}
+		}
+
+		out.Days = append(out.Days, day)
+	}
+
+	out.FillUnitVariants()
+	return out
+}
+
+func (r *forecastResponse) hoursByDate() map[string][]forecast.Hour {
+	h := r.Hourly
+	hours := make(map[string][]forecast.Hour)
+
+	for i, epoch := range h.Time {
+		date := r.localTime(epoch).Format("2006-01-02")
+		hours[date] = append(hours[date], forecast.Hour{
+			TimeUnix:     epoch,
+			TempC:        at(h.Temperature, i),
+			IsDay:        at(h.IsDay, i) == 1,
+			ChanceOfRain: forecast.Ptr(at(h.PrecipitationProbability, i)),
+			Condition:    forecast.Condition{Text: conditionText(at(h.WeatherCode, i), at(h.IsDay, i) == 1)},
+		})
+	}
+
+	return hours
+}
+
+// at returns s[i], or the zero value when the API omitted trailing values.
+func at[T any](s []T, i int) T {
+	var zero T
+	if i < 0 || i >= len(s) {
+		return zero
+	}
+	return s[i]
+}
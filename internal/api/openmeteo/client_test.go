@@ -0,0 +1,135 @@
+package openmeteo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/jtotty/weather-cli/internal/api/geocode"
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+var london = geocode.Place{Name: "London", Country: "United Kingdom", Lat: 51.5, Lon: -0.12}
+
+func newFixtureServer(t *testing.T, fixture string) *httptest.Server {
+	t.Helper()
+
+	body, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("latitude") != "51.5000" || q.Get("longitude") != "-0.1200" {
+			t.Errorf("unexpected coordinates: %s", r.URL.RawQuery)
+		}
+		if q.Get("forecast_days") != "2" {
+			t.Errorf("forecast_days = %q, want %q", q.Get("forecast_days"), "2")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestFetch_Fixture(t *testing.T) {
+	server := newFixtureServer(t, "testdata/forecast.json")
+	client := NewTestClient(server.URL).WithGeocoder(geocode.Static{Place: london})
+
+	res, err := client.Fetch(context.Background(), forecast.Options{Location: "London", Days: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Location.Name != "London" || res.Location.Country != "United Kingdom" {
+		t.Errorf("Location = %+v, want London, United Kingdom", res.Location)
+	}
+	if res.Location.LocalTime != "2024-01-18 12:30" {
+		t.Errorf("LocalTime = %q, want %q", res.Location.LocalTime, "2024-01-18 12:30")
+	}
+
+	cur := res.Current
+	if cur.TempC != 9.4 || *cur.FeelsLikeC != 6.8 {
+		t.Errorf("Current temps = %v/%v, want 9.4/6.8", cur.TempC, *cur.FeelsLikeC)
+	}
+	if *cur.PressureMb != 1008.2 || *cur.PrecipMm != 0.4 {
+		t.Errorf("Current pressure/precip = %v/%v, want 1008.2/0.4", *cur.PressureMb, *cur.PrecipMm)
+	}
+	if cur.TempF < 48.9 || cur.TempF > 49 {
+		t.Errorf("Current.TempF = %v, want ~48.92", cur.TempF)
+	}
+	if cur.WindDirection != "SW" {
+		t.Errorf("WindDirection = %q, want %q", cur.WindDirection, "SW")
+	}
+	if cur.Condition.Text != "Moderate rain" {
+		t.Errorf("Condition = %q, want %q", cur.Condition.Text, "Moderate rain")
+	}
+
+	days := res.Days
+	if len(days) != 2 {
+		t.Fatalf("len(Days) = %d, want 2", len(days))
+	}
+
+	if days[0].Date != "2024-01-18" || days[1].Date != "2024-01-19" {
+		t.Errorf("dates = %q, %q", days[0].Date, days[1].Date)
+	}
+	if len(days[0].Hours) != 24 || len(days[1].Hours) != 24 {
+		t.Errorf("hours per day = %d, %d, want 24, 24", len(days[0].Hours), len(days[1].Hours))
+	}
+	if s := days[0].Summary; s.MaxTempC != 10 || *s.ChanceOfRain != 70 {
+		t.Errorf("Summary = %+v", s)
+	}
+	if days[0].Astro.Sunrise != "08:02 AM" || days[0].Astro.Sunset != "04:35 PM" {
+		t.Errorf("Astro = %+v, want 08:02 AM / 04:35 PM", days[0].Astro)
+	}
+	if got := days[0].Hours[0].Condition.Text; got != "Clear" {
+		t.Errorf("midnight condition = %q, want %q", got, "Clear")
+	}
+}
+
+func TestFetch_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	_, err := NewTestClient(server.URL).WithGeocoder(geocode.Static{Place: london}).Fetch(context.Background(), forecast.Options{Location: "London", Days: 1})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestConditionText(t *testing.T) {
+	tests := []struct {
+		code  int
+		isDay bool
+		want  string
+	}{
+		{0, true, "Sunny"},
+		{0, false, "Clear"},
+		{3, true, "Overcast"},
+		{95, true, "Patchy light rain with thunder"},
+		{42, true, "Cloudy"},
+	}
+
+	for _, tt := range tests {
+		if got := conditionText(tt.code, tt.isDay); got != tt.want {
+			t.Errorf("conditionText(%d, %v) = %q, want %q", tt.code, tt.isDay, got, tt.want)
+		}
+	}
+}
+
+func TestFetch_LocationNotFound(t *testing.T) {
+	notFound := fmt.Errorf("%w: Atlantis", geocode.ErrNotFound)
+	_, err := NewClient().WithGeocoder(geocode.Static{Err: notFound}).Fetch(context.Background(), forecast.Options{Location: "Atlantis", Days: 1})
+	if !errors.Is(err, forecast.ErrLocationNotFound) {
+		t.Errorf("error = %v, want forecast.ErrLocationNotFound", err)
+	}
+}
@@ -0,0 +1,48 @@
+package openmeteo
+
+// wmoConditions maps WMO weather interpretation codes to the condition text
+// weatherapi.com uses, so icons and colors work the same for every provider.
+var wmoConditions = map[int]string{
+	1:  "Partly cloudy",
+	2:  "Partly cloudy",
+	3:  "Overcast",
+	45: "Fog",
+	48: "Freezing fog",
+	51: "Patchy light drizzle",
+	53: "Light drizzle",
+	55: "Light drizzle",
+	56: "Freezing drizzle",
+	57: "Heavy freezing drizzle",
+	61: "Light rain",
+	63: "Moderate rain",
+	65: "Heavy rain",
+	66: "Light freezing rain",
+	67: "Moderate or heavy freezing rain",
+	71: "Light snow",
+	73: "Moderate snow",
+	75: "Heavy snow",
+	77: "Ice pellets",
+	80: "Light rain shower",
+	81: "Moderate or heavy rain shower",
+	82: "Torrential rain shower",
+	85: "Light snow showers",
+	86: "Moderate or heavy snow showers",
+	95: "Patchy light rain with thunder",
+	96: "Moderate or heavy rain with thunder",
+	99: "Moderate or heavy rain with thunder",
+}
+
+func conditionText(code int, isDay bool) string {
+	if code == 0 {
+		if isDay {
+			return "Sunny"
+		}
+		return "Clear"
+	}
+
+	if text, ok := wmoConditions[code]; ok {
+		return text
+	}
+
+	return "Cloudy"
+}
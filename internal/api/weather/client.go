@@ -2,86 +2,75 @@ package weather
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
-	"time"
+
+	"github.com/jtotty/weather-cli/internal/api/httpjson"
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/redact"
 )
 
-const baseURL = "https://api.weatherapi.com/v1/forecast.json"
-const maxResponseSize = 10 * 1024 * 1024 // 10MB to prevent DoS
+const baseURL = "https://api.weatherapi.com/v1"
+
+// ProviderName identifies weatherapi.com in provider lists and output.
+const ProviderName = "weatherapi"
 
 type Client struct {
 	httpClient *http.Client
 	apiKey     string
 	baseURL    string
+	retry      httpjson.RetryPolicy
 }
 
+// NewClient creates a weatherapi.com client. The key is registered with the
+// redact package so it never appears in printed errors.
 func NewClient(apiKey string) *Client {
+	redact.Register(apiKey)
 	return &Client{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
-				MaxIdleConns:          10,
-				IdleConnTimeout:       30 * time.Second,
-				DisableCompression:    false,
-				TLSHandshakeTimeout:   10 * time.Second,
-				ResponseHeaderTimeout: 10 * time.Second,
-			},
-		},
-		apiKey:  apiKey,
-		baseURL: baseURL,
+		httpClient: httpjson.NewClient(),
+		apiKey:     apiKey,
+		baseURL:    baseURL,
+		retry:      httpjson.DefaultRetryPolicy(),
 	}
 }
 
-type FetchOptions struct {
-	Location   string
-	Days       int
-	IncludeAQI bool
-	Alerts     bool
+// WithRetry sets the policy for retrying transient failures.
+func (c *Client) WithRetry(p httpjson.RetryPolicy) *Client {
+	c.retry = p
+	return c
 }
 
-func (c *Client) Fetch(ctx context.Context, opts FetchOptions) (*Response, error) {
-	reqURL := c.buildURL(opts)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+func (c *Client) Name() string {
+	return ProviderName
+}
 
-	res, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("API request failed: %w", err)
-	}
-	defer func() { _ = res.Body.Close() }()
+func (c *Client) Fetch(ctx context.Context, opts forecast.Options) (*forecast.Response, error) {
+	return c.get(ctx, c.buildURL(opts))
+}
 
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("weather API returned status %d", res.StatusCode)
-	}
+// FetchCurrent fetches only the current conditions from current.json, a
+// much smaller response than a forecast. Days and Alerts are ignored.
+func (c *Client) FetchCurrent(ctx context.Context, opts forecast.Options) (*forecast.Response, error) {
+	return c.get(ctx, c.currentURL(opts))
+}
 
-	body, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+func (c *Client) get(ctx context.Context, rawURL string) (*forecast.Response, error) {
+	var response apiResponse
+	err := c.retry.Do(ctx, func(ctx context.Context) error {
+		return httpjson.Get(ctx, c.httpClient, rawURL, nil, &response)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	if len(body) == maxResponseSize {
-		return nil, fmt.Errorf("response too large (exceeded %d bytes)", maxResponseSize)
+		// Request errors quote the URL, which carries the key.
+		return nil, redact.Error(decodeError(err), c.apiKey)
 	}
 
-	var response Response
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
-	}
-
-	return &response, nil
+	return response.toResponse(), nil
 }
 
 // buildURL constructs the API URL with proper encoding to prevent injection
-func (c *Client) buildURL(opts FetchOptions) string {
+func (c *Client) buildURL(opts forecast.Options) string {
 	params := url.Values{}
-	params.Add("key", c.apiKey)
 	params.Add("q", opts.Location)
 	params.Add("days", fmt.Sprintf("%d", opts.Days))
 
@@ -93,5 +82,23 @@ func (c *Client) buildURL(opts FetchOptions) string {
 		params.Add("alerts", "yes")
 	}
 
-	return fmt.Sprintf("%s?%s", c.baseURL, params.Encode())
+	return c.endpoint("forecast.json", params)
+}
+
+// currentURL constructs the current.json URL for opts.
+func (c *Client) currentURL(opts forecast.Options) string {
+	params := url.Values{}
+	params.Add("q", opts.Location)
+
+	if opts.IncludeAQI {
+		params.Add("aqi", "yes")
+	}
+
+	return c.endpoint("current.json", params)
+}
+
+// endpoint returns the URL of an API method with the key added to params.
+func (c *Client) endpoint(method string, params url.Values) string {
+	params.Set("key", c.apiKey)
+	return fmt.Sprintf("%s/%s?%s", c.baseURL, method, params.Encode())
 }
@@ -3,10 +3,16 @@ package weather
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/api/httpjson"
+	"github.com/jtotty/weather-cli/internal/forecast"
 )
 
 func TestBuildURL_EncodesSpecialCharacters(t *testing.T) {
@@ -24,7 +30,7 @@ func TestBuildURL_EncodesSpecialCharacters(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			opts := FetchOptions{Location: tt.location, Days: 1}
+			opts := forecast.Options{Location: tt.location, Days: 1}
 			url := client.BuildURL(opts)
 
 			// The location should be URL-encoded, not appear as raw special chars
@@ -46,13 +52,13 @@ func TestBuildURL_IncludesOptionalParams(t *testing.T) {
 
 	tests := []struct {
 		name       string
-		opts       FetchOptions
+		opts       forecast.Options
 		wantParams []string
 		dontWant   []string
 	}{
 		{
 			name: "all options enabled",
-			opts: FetchOptions{
+			opts: forecast.Options{
 				Location:   "London",
 				Days:       3,
 				IncludeAQI: true,
@@ -62,7 +68,7 @@ func TestBuildURL_IncludesOptionalParams(t *testing.T) {
 		},
 		{
 			name: "options disabled",
-			opts: FetchOptions{
+			opts: forecast.Options{
 				Location:   "London",
 				Days:       1,
 				IncludeAQI: false,
@@ -92,25 +98,12 @@ func TestBuildURL_IncludesOptionalParams(t *testing.T) {
 }
 
 func TestFetch_Success(t *testing.T) {
-	mockResponse := Response{
-		Location: Location{
-			Name:      "London",
-			Country:   "UK",
-			LocalTime: "2024-01-15 12:00",
-		},
-		Current: Current{
-			TempC: 15,
-		},
-		Forecast: Forecast{
-			Forecastday: []ForecastDay{
-				{
-					Astro: Astro{Sunrise: "07:00 AM", Sunset: "05:00 PM"},
-					Hour:  []Hour{},
-				},
-			},
-		},
-		Alerts: Alerts{Alert: []Alert{}},
-	}
+	const mockResponse = `{
+		"location": {"name": "London", "country": "UK", "tz_id": "Europe/London", "localtime": "2024-01-15 12:00"},
+		"current": {"temp_c": 15, "is_day": 1},
+		"forecast": {"forecastday": [{"astro": {"sunrise": "07:00 AM", "sunset": "05:00 PM", "is_sun_up": 1}, "hour": []}]},
+		"alerts": {"alert": [{"event": "Flood Warning", "desc": "River levels are high."}]}
+	}`
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Verify request has expected parameters
@@ -122,14 +115,14 @@ func TestFetch_Success(t *testing.T) {
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(mockResponse)
+		_, _ = w.Write([]byte(mockResponse))
 	}))
 	defer server.Close()
 
 	client := NewTestClient("test-key", server.URL)
 	ctx := context.Background()
 
-	response, err := client.Fetch(ctx, FetchOptions{
+	response, err := client.Fetch(ctx, forecast.Options{
 		Location: "London",
 		Days:     1,
 	})
@@ -146,8 +139,46 @@ func TestFetch_Success(t *testing.T) {
 		t.Errorf("Location.Country = %q, want %q", response.Location.Country, "UK")
 	}
 
-	if response.Current.TempC != 15 {
-		t.Errorf("Current.TempC = %v, want %v", response.Current.TempC, 15)
+	if response.Current.TempC != 15 || !response.Current.IsDay {
+		t.Errorf("Current = %+v, want 15°C by day", response.Current)
+	}
+
+	if response.Location.TimeZone != "Europe/London" || response.Location.LocalTime != "2024-01-15 12:00" {
+		t.Errorf("Location = %+v, want the time zone and local time", response.Location)
+	}
+
+	if len(response.Days) != 1 || response.Days[0].Astro.Sunrise != "07:00 AM" || !*response.Days[0].Astro.IsSunUp {
+		t.Errorf("Days = %+v, want one day with its astronomy", response.Days)
+	}
+
+	if len(response.Alerts) != 1 || response.Alerts[0].Description != "River levels are high." {
+		t.Errorf("Alerts = %+v, want the flood warning", response.Alerts)
+	}
+}
+
+func TestFetchCurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/current.json" {
+			t.Errorf("path = %q, want /current.json", r.URL.Path)
+		}
+		if q := r.URL.Query(); q.Get("aqi") != "yes" || q.Has("days") || q.Has("alerts") {
+			t.Errorf("query = %q, want aqi and no forecast parameters", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"current":{"temp_c":18}}`))
+	}))
+	defer server.Close()
+
+	response, err := NewTestClient("test-key", server.URL).FetchCurrent(context.Background(), forecast.Options{
+		Location:   "London",
+		Days:       7,
+		IncludeAQI: true,
+		Alerts:     true,
+	})
+	if err != nil {
+		t.Fatalf("FetchCurrent() error = %v", err)
+	}
+	if response.Current.TempC != 18 {
+		t.Errorf("Current.TempC = %v, want 18", response.Current.TempC)
 	}
 }
 
@@ -172,7 +203,7 @@ func TestFetch_HTTPErrors(t *testing.T) {
 			client := NewTestClient("test-key", server.URL)
 			ctx := context.Background()
 
-			_, err := client.Fetch(ctx, FetchOptions{Location: "London", Days: 1})
+			_, err := client.Fetch(ctx, forecast.Options{Location: "London", Days: 1})
 
 			if err == nil {
 				t.Fatal("expected error, got nil")
@@ -184,3 +215,204 @@ func TestFetch_HTTPErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestFetch_APIErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		want       error
+		wantCode   int
+	}{
+		{"location not found", http.StatusBadRequest, `{"error":{"code":1006,"message":"No matching location found."}}`, forecast.ErrLocationNotFound, 1006},
+		{"invalid key", http.StatusUnauthorized, `{"error":{"code":2006,"message":"API key is invalid."}}`, ErrInvalidKey, 2006},
+		{"missing key", http.StatusUnauthorized, `{"error":{"code":1002,"message":"API key is invalid or not provided."}}`, ErrInvalidKey, 1002},
+		{"quota exceeded", http.StatusForbidden, `{"error":{"code":2007,"message":"API key has exceeded calls per month quota."}}`, ErrQuotaExceeded, 2007},
+		{"key disabled", http.StatusForbidden, `{"error":{"code":2008,"message":"API key has been disabled."}}`, ErrKeyDisabled, 2008},
+		{"not in plan", http.StatusForbidden, `{"error":{"code":2009,"message":"API key does not have access to the resource."}}`, ErrNotInPlan, 2009},
+		{"unmapped code", http.StatusBadRequest, `{"error":{"code":9999,"message":"Internal application error."}}`, nil, 9999},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := NewTestClient("test-key", server.URL).Fetch(context.Background(), forecast.Options{Location: "London", Days: 1})
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want *APIError", err)
+			}
+			if apiErr.Code != tt.wantCode {
+				t.Errorf("Code = %d, want %d", apiErr.Code, tt.wantCode)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want errors.Is %v", err, tt.want)
+			}
+
+			var statusErr *httpjson.StatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.statusCode {
+				t.Errorf("error = %v, want underlying status %d", err, tt.statusCode)
+			}
+		})
+	}
+}
+
+func TestFetch_NonJSONErrorBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("<html>Bad Gateway</html>"))
+	}))
+	defer server.Close()
+
+	_, err := NewTestClient("test-key", server.URL).Fetch(context.Background(), forecast.Options{Location: "London", Days: 1})
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		t.Errorf("error = %v, want plain status error", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("error = %v, want status 502", err)
+	}
+}
+
+func TestFetch_RetriesTransientFailures(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"location":{"name":"London"}}`))
+	}))
+	defer server.Close()
+
+	client := NewTestClient("test-key", server.URL).WithRetry(httpjson.RetryPolicy{
+		MaxAttempts: 3,
+		Sleep:       func(context.Context, time.Duration) error { return nil },
+	})
+
+	response, err := client.Fetch(context.Background(), forecast.Options{Location: "London", Days: 1})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if calls != 3 || response.Location.Name != "London" {
+		t.Errorf("calls = %d, location = %q; want 3 calls and London", calls, response.Location.Name)
+	}
+}
+
+func TestFetch_RedactsAPIKey(t *testing.T) {
+	const key = "leaky-secret-key"
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"connection dropped", func(w http.ResponseWriter, r *http.Request) {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_ = conn.Close()
+		}},
+		{"server error", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(r.URL.String()))
+		}},
+		{"invalid JSON", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("not json"))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			_, err := NewTestClient(key, server.URL).Fetch(context.Background(), forecast.Options{Location: "London", Days: 1})
+
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if strings.Contains(err.Error(), key) {
+				t.Errorf("error = %q, leaks the API key", err)
+			}
+		})
+	}
+}
+
+func TestResponse_DecodesUnitVariants(t *testing.T) {
+	res := decodeFixture(t)
+
+	c := res.Current
+	if c.TempF != 89.6 || *c.FeelsLikeF != 89.3 || *c.WindKph != 13 {
+		t.Errorf("Current °F/kph = %v/%v/%v, want 89.6/89.3/13", c.TempF, *c.FeelsLikeF, *c.WindKph)
+	}
+	if *c.PressureMb != 1013 || *c.PressureIn != 29.91 || *c.VisKm != 9 || *c.VisMiles != 5 {
+		t.Errorf("Current pressure/visibility = %+v", c)
+	}
+
+	day := res.Days[0].Summary
+	if day.MaxTempF != 95.8 || day.MinTempF != 78.5 || *day.MaxWindKph != 13.3 {
+		t.Errorf("Summary = %+v", day)
+	}
+
+	if got := res.Days[0].Hours[0].TempF; got != 83.1 {
+		t.Errorf("Hours[0].TempF = %v, want 83.1", got)
+	}
+}
+
+func TestResponse_DecodesDetails(t *testing.T) {
+	res := decodeFixture(t)
+
+	if l := res.Location; l.Lat != 13.92 || l.Lon != 100.5 || l.TimeZone != "Asia/Bangkok" {
+		t.Errorf("Location = %+v", l)
+	}
+
+	c := res.Current
+	if !c.IsDay || c.Condition.Code != 1003 || *c.WindDegree != 10 || *c.Cloud != 25 {
+		t.Errorf("Current is_day/code/degree/cloud = %v/%v/%v/%v", c.IsDay, c.Condition.Code, *c.WindDegree, *c.Cloud)
+	}
+	if *c.UV != 8 || *c.GustMph != 12.5 || *c.GustKph != 20.2 {
+		t.Errorf("Current uv/gusts = %v/%v/%v", *c.UV, *c.GustMph, *c.GustKph)
+	}
+	if c.AirQuality == nil || c.AirQuality.PM25 == 0 {
+		t.Errorf("Current.AirQuality = %+v, want the fixture's pollutants", c.AirQuality)
+	}
+
+	day := res.Days[0].Summary
+	if day.Condition.Code != 1000 || *day.AvgVisKm != 10 {
+		t.Errorf("Summary code/visibility = %v/%v", day.Condition.Code, *day.AvgVisKm)
+	}
+
+	h := res.Days[0].Hours[0]
+	if h.DewPointC == nil || h.HeatIndexC == nil || h.WindChillC == nil {
+		t.Fatal("Hours[0] dew point, heat index or wind chill not decoded")
+	}
+	if *h.DewPointC != 14.7 || *h.HeatIndexC != 28.5 || *h.WindChillC != 28.4 || *h.FeelsLikeC != 28.5 {
+		t.Errorf("Hours[0] dew point/heat index/wind chill/feels like = %v/%v/%v/%v",
+			*h.DewPointC, *h.HeatIndexC, *h.WindChillC, *h.FeelsLikeC)
+	}
+	if *h.WindDegree != 99 || h.WindDirection != "E" || *h.GustMph != 8.7 || *h.PressureMb != 1012 || *h.Cloud != 2 || *h.UV != 1 {
+		t.Errorf("Hours[0] = %+v", h)
+	}
+}
+
+// decodeFixture maps the weatherapi.com response in response.json into
+// the model.
+func decodeFixture(t *testing.T) *forecast.Response {
+	t.Helper()
+
+	body, err := os.ReadFile("../../../response.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	var res apiResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		t.Fatalf("failed to decode fixture: %v", err)
+	}
+	return res.toResponse()
+}
@@ -0,0 +1,83 @@
+package weather
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jtotty/weather-cli/internal/api/httpjson"
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+// Errors reported by weatherapi.com. Match them with errors.Is; an unknown
+// location matches forecast.ErrLocationNotFound.
+var (
+	ErrInvalidKey    = errors.New("API key is invalid")
+	ErrQuotaExceeded = errors.New("API key has exceeded its monthly quota")
+	ErrKeyDisabled   = errors.New("API key has been disabled")
+	ErrNotInPlan     = errors.New("API key's plan does not include this data")
+)
+
+// weatherapi.com error codes, from https://www.weatherapi.com/docs/#intro-error-codes.
+const (
+	codeKeyMissing       = 1002
+	codeLocationNotFound = 1006
+	codeInvalidKey       = 2006
+	codeQuotaExceeded    = 2007
+	codeKeyDisabled      = 2008
+	codeNotInPlan        = 2009
+)
+
+var codeErrors = map[int]error{
+	codeKeyMissing:       ErrInvalidKey,
+	codeLocationNotFound: forecast.ErrLocationNotFound,
+	codeInvalidKey:       ErrInvalidKey,
+	codeQuotaExceeded:    ErrQuotaExceeded,
+	codeKeyDisabled:      ErrKeyDisabled,
+	codeNotInPlan:        ErrNotInPlan,
+}
+
+// APIError is an error described in a weatherapi.com response body. It
+// matches the sentinel error for its code and the underlying
+// *httpjson.StatusError.
+type APIError struct {
+	Code    int
+	Message string
+	Status  *httpjson.StatusError
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("weatherapi.com error %d: %s", e.Code, e.Message)
+}
+
+func (e *APIError) Unwrap() []error {
+	var errs []error
+	if e.Status != nil {
+		errs = append(errs, e.Status)
+	}
+	if sentinel, ok := codeErrors[e.Code]; ok {
+		errs = append(errs, sentinel)
+	}
+	return errs
+}
+
+// decodeError replaces a status error with an *APIError when its body holds
+// a weatherapi.com error object. Other errors are returned unchanged.
+func decodeError(err error) error {
+	var statusErr *httpjson.StatusError
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+
+	var body struct {
+		Error struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(statusErr.Body, &body) != nil || body.Error.Code == 0 {
+		return err
+	}
+
+	return &APIError{Code: body.Error.Code, Message: body.Error.Message, Status: statusErr}
+}
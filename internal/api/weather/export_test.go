@@ -1,13 +1,20 @@
 package weather
 
-// NewTestClient creates a client with a custom base URL for testing
+import (
+	"github.com/jtotty/weather-cli/internal/api/httpjson"
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+// NewTestClient creates a client with a custom base URL for testing. It
+// makes a single attempt unless a test sets a retry policy.
 func NewTestClient(apiKey, baseURL string) *Client {
 	c := NewClient(apiKey)
 	c.baseURL = baseURL
+	c.retry = httpjson.RetryPolicy{}
 	return c
 }
 
 // BuildURL exposes buildURL for testing
-func (c *Client) BuildURL(opts FetchOptions) string {
+func (c *Client) BuildURL(opts forecast.Options) string {
 	return c.buildURL(opts)
 }
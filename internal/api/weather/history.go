@@ -0,0 +1,19 @@
+package weather
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+// History returns the observed weather at location on date as a response
+// with a single Day and no current conditions.
+func (c *Client) History(ctx context.Context, location string, date time.Time) (*forecast.Response, error) {
+	params := url.Values{}
+	params.Add("q", location)
+	params.Add("dt", date.Format("2006-01-02"))
+
+	return c.get(ctx, c.endpoint("history.json", params))
+}
@@ -0,0 +1,33 @@
+package weather
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/history.json" || r.URL.Query().Get("dt") != "2026-09-01" || r.URL.Query().Get("q") != "London" {
+			t.Errorf("request = %s, want history.json for London on 2026-09-01", r.URL)
+		}
+		_, _ = w.Write([]byte(`{
+			"location": {"name": "London", "country": "United Kingdom"},
+			"forecast": {"forecastday": [{"date": "2026-09-01", "day": {"maxtemp_c": 21.4}, "hour": [{"time_epoch": 1788220800, "temp_c": 14.1}]}]}
+		}`))
+	}))
+	defer server.Close()
+
+	date := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	got, err := NewTestClient("test-key", server.URL).History(context.Background(), "London", date)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+
+	days := got.Days
+	if len(days) != 1 || days[0].Summary.MaxTempC != 21.4 || len(days[0].Hours) != 1 {
+		t.Errorf("Days = %+v, want one day with its hours", days)
+	}
+}
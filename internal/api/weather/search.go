@@ -0,0 +1,55 @@
+package weather
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/jtotty/weather-cli/internal/api/geocode"
+	"github.com/jtotty/weather-cli/internal/api/httpjson"
+	"github.com/jtotty/weather-cli/internal/redact"
+)
+
+// SearchResult is a location matching a search query.
+type SearchResult struct {
+	ID      int     `json:"id"`
+	Name    string  `json:"name"`
+	Region  string  `json:"region"`
+	Country string  `json:"country"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+}
+
+// Label names the location with its region and country, e.g. "Paris,
+// Ile-de-France, France".
+func (r SearchResult) Label() string {
+	parts := make([]string, 0, 3)
+	for _, part := range []string{r.Name, r.Region, r.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Query returns the result's coordinates as a forecast location, so the
+// forecast is for exactly this place.
+func (r SearchResult) Query() string {
+	return geocode.FormatCoordinates(r.Lat, r.Lon)
+}
+
+// Search lists the locations matching query, best match first.
+func (c *Client) Search(ctx context.Context, query string) ([]SearchResult, error) {
+	params := url.Values{}
+	params.Add("q", query)
+
+	var results []SearchResult
+	err := c.retry.Do(ctx, func(ctx context.Context) error {
+		return httpjson.Get(ctx, c.httpClient, c.endpoint("search.json", params), nil, &results)
+	})
+	if err != nil {
+		return nil, redact.Error(decodeError(err), c.apiKey)
+	}
+
+	return results, nil
+}
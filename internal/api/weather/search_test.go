@@ -0,0 +1,57 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search.json" || r.URL.Query().Get("q") != "Paris" || r.URL.Query().Get("key") != "test-key" {
+			t.Errorf("request = %s, want search.json for Paris with the key", r.URL)
+		}
+		_, _ = w.Write([]byte(`[
+			{"id":803267,"name":"Paris","region":"Ile-de-France","country":"France","lat":48.87,"lon":2.33,"url":"paris-ile-de-france-france"},
+			{"id":2618724,"name":"Paris","region":"Texas","country":"United States of America","lat":33.66,"lon":-95.56}
+		]`))
+	}))
+	defer server.Close()
+
+	results, err := NewTestClient("test-key", server.URL).Search(context.Background(), "Paris")
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("len(results) = %d, want 2", len(results))
+	}
+	if got := results[1].Label(); got != "Paris, Texas, United States of America" {
+		t.Errorf("Label() = %q", got)
+	}
+	if got := results[0].Query(); got != "48.8700,2.3300" {
+		t.Errorf("Query() = %q, want coordinates", got)
+	}
+}
+
+func TestSearch_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"code":2006,"message":"API key is invalid."}}`))
+	}))
+	defer server.Close()
+
+	_, err := NewTestClient("test-key", server.URL).Search(context.Background(), "Paris")
+	if !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Search() error = %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestSearchResult_Label(t *testing.T) {
+	r := SearchResult{Name: "Monaco", Country: "Monaco"}
+	if got := r.Label(); got != "Monaco, Monaco" {
+		t.Errorf("Label() = %q, want empty region skipped", got)
+	}
+}
@@ -0,0 +1,294 @@
+package weather
+
+import "github.com/jtotty/weather-cli/internal/forecast"
+
+// apiResponse is weatherapi.com's forecast.json, current.json and
+// history.json response body. toResponse maps it into the model.
+type apiResponse struct {
+	Location apiLocation `json:"location"`
+	Current  apiCurrent  `json:"current"`
+	Forecast struct {
+		Forecastday []apiForecastDay `json:"forecastday"`
+	} `json:"forecast"`
+	Alerts struct {
+		Alert []apiAlert `json:"alert"`
+	} `json:"alerts"`
+}
+
+type apiLocation struct {
+	Name      string  `json:"name"`
+	Region    string  `json:"region"`
+	Country   string  `json:"country"`
+	Lat       float64 `json:"lat"`
+	Lon       float64 `json:"lon"`
+	TzID      string  `json:"tz_id"`
+	LocalTime string  `json:"localtime"`
+}
+
+type apiCondition struct {
+	Text string `json:"text"`
+	Code int    `json:"code"`
+}
+
+type apiAstro struct {
+	Sunrise          string `json:"sunrise"`
+	Sunset           string `json:"sunset"`
+	Moonrise         string `json:"moonrise"`
+	Moonset          string `json:"moonset"`
+	MoonPhase        string `json:"moon_phase"`
+	MoonIllumination int    `json:"moon_illumination"`
+	IsSunUp          int    `json:"is_sun_up"`
+	IsMoonUp         int    `json:"is_moon_up"`
+}
+
+type apiAlert struct {
+	Event string `json:"event"`
+	Desc  string `json:"desc"`
+}
+
+type apiCurrent struct {
+	TempC      float32        `json:"temp_c"`
+	TempF      float32        `json:"temp_f"`
+	IsDay      int            `json:"is_day"`
+	FeelsLikeC float32        `json:"feelslike_c"`
+	FeelsLikeF float32        `json:"feelslike_f"`
+	WindChillC *float32       `json:"windchill_c"`
+	WindChillF *float32       `json:"windchill_f"`
+	HeatIndexC *float32       `json:"heatindex_c"`
+	HeatIndexF *float32       `json:"heatindex_f"`
+	DewPointC  *float32       `json:"dewpoint_c"`
+	DewPointF  *float32       `json:"dewpoint_f"`
+	Humidity   float32        `json:"humidity"`
+	Cloud      float32        `json:"cloud"`
+	WindMph    float32        `json:"wind_mph"`
+	WindKph    float32        `json:"wind_kph"`
+	WindDegree int            `json:"wind_degree"`
+	WindDir    string         `json:"wind_dir"`
+	GustMph    float32        `json:"gust_mph"`
+	GustKph    float32        `json:"gust_kph"`
+	PressureMb float32        `json:"pressure_mb"`
+	PressureIn float32        `json:"pressure_in"`
+	PrecipMm   float32        `json:"precip_mm"`
+	PrecipIn   float32        `json:"precip_in"`
+	VisKm      float32        `json:"vis_km"`
+	VisMiles   float32        `json:"vis_miles"`
+	UV         float32        `json:"uv"`
+	Condition  apiCondition   `json:"condition"`
+	AirQuality *apiAirQuality `json:"air_quality"`
+}
+
+// apiAirQuality holds the pollutants weatherapi.com reports with aqi=yes.
+type apiAirQuality struct {
+	PM25 float32 `json:"pm2_5"`
+	PM10 float32 `json:"pm10"`
+}
+
+type apiForecastDay struct {
+	Date       string         `json:"date"`
+	Day        apiDay         `json:"day"`
+	Hour       []apiHour      `json:"hour"`
+	AirQuality *apiAirQuality `json:"air_quality"`
+	Astro      apiAstro       `json:"astro"`
+}
+
+type apiDay struct {
+	MaxTempC      float32      `json:"maxtemp_c"`
+	MaxTempF      float32      `json:"maxtemp_f"`
+	MinTempC      float32      `json:"mintemp_c"`
+	MinTempF      float32      `json:"mintemp_f"`
+	AvgTempC      float32      `json:"avgtemp_c"`
+	AvgTempF      float32      `json:"avgtemp_f"`
+	MaxWindMph    float32      `json:"maxwind_mph"`
+	MaxWindKph    float32      `json:"maxwind_kph"`
+	TotalPrecipMm float32      `json:"totalprecip_mm"`
+	TotalPrecipIn float32      `json:"totalprecip_in"`
+	TotalSnowCm   float32      `json:"totalsnow_cm"`
+	AvgVisKm      float32      `json:"avgvis_km"`
+	AvgVisMiles   float32      `json:"avgvis_miles"`
+	AvgHumidity   float32      `json:"avghumidity"`
+	ChanceOfRain  int          `json:"daily_chance_of_rain"`
+	ChanceOfSnow  int          `json:"daily_chance_of_snow"`
+	Condition     apiCondition `json:"condition"`
+	UV            float32      `json:"uv"`
+}
+
+type apiHour struct {
+	TimeEpoch    int64        `json:"time_epoch"`
+	TempC        float32      `json:"temp_c"`
+	TempF        float32      `json:"temp_f"`
+	IsDay        int          `json:"is_day"`
+	Condition    apiCondition `json:"condition"`
+	WindMph      float32      `json:"wind_mph"`
+	WindKph      float32      `json:"wind_kph"`
+	WindDegree   int          `json:"wind_degree"`
+	WindDir      string       `json:"wind_dir"`
+	GustMph      float32      `json:"gust_mph"`
+	GustKph      float32      `json:"gust_kph"`
+	PressureMb   float32      `json:"pressure_mb"`
+	PressureIn   float32      `json:"pressure_in"`
+	PrecipMm     float32      `json:"precip_mm"`
+	PrecipIn     float32      `json:"precip_in"`
+	SnowCm       float32      `json:"snow_cm"`
+	Humidity     float32      `json:"humidity"`
+	Cloud        float32      `json:"cloud"`
+	FeelsLikeC   float32      `json:"feelslike_c"`
+	FeelsLikeF   float32      `json:"feelslike_f"`
+	WindChillC   *float32     `json:"windchill_c"`
+	WindChillF   *float32     `json:"windchill_f"`
+	HeatIndexC   *float32     `json:"heatindex_c"`
+	HeatIndexF   *float32     `json:"heatindex_f"`
+	DewPointC    *float32     `json:"dewpoint_c"`
+	DewPointF    *float32     `json:"dewpoint_f"`
+	ChanceOfRain float32      `json:"chance_of_rain"`
+	ChanceOfSnow float32      `json:"chance_of_snow"`
+	VisKm        float32      `json:"vis_km"`
+	VisMiles     float32      `json:"vis_miles"`
+	UV           float32      `json:"uv"`
+}
+
+func (r *apiResponse) toResponse() *forecast.Response {
+	l, c := r.Location, r.Current
+	out := &forecast.Response{
+		Location: forecast.Location{
+			Name:      l.Name,
+			Region:    l.Region,
+			Country:   l.Country,
+			Lat:       l.Lat,
+			Lon:       l.Lon,
+			TimeZone:  l.TzID,
+			LocalTime: l.LocalTime,
+		},
+		Current: forecast.Current{
+			TempC:         c.TempC,
+			TempF:         c.TempF,
+			IsDay:         c.IsDay == 1,
+			Condition:     c.Condition.toCondition(),
+			FeelsLikeC:    forecast.Ptr(c.FeelsLikeC),
+			FeelsLikeF:    forecast.Ptr(c.FeelsLikeF),
+			WindChillC:    c.WindChillC,
+			WindChillF:    c.WindChillF,
+			HeatIndexC:    c.HeatIndexC,
+			HeatIndexF:    c.HeatIndexF,
+			DewPointC:     c.DewPointC,
+			DewPointF:     c.DewPointF,
+			Humidity:      forecast.Ptr(c.Humidity),
+			Cloud:         forecast.Ptr(c.Cloud),
+			WindMph:       forecast.Ptr(c.WindMph),
+			WindKph:       forecast.Ptr(c.WindKph),
+			WindDegree:    forecast.Ptr(c.WindDegree),
+			WindDirection: c.WindDir,
+			GustMph:       forecast.Ptr(c.GustMph),
+			GustKph:       forecast.Ptr(c.GustKph),
+			PressureMb:    forecast.Ptr(c.PressureMb),
+			PressureIn:    forecast.Ptr(c.PressureIn),
+			PrecipMm:      forecast.Ptr(c.PrecipMm),
+			PrecipIn:      forecast.Ptr(c.PrecipIn),
+			VisKm:         forecast.Ptr(c.VisKm),
+			VisMiles:      forecast.Ptr(c.VisMiles),
+			UV:            forecast.Ptr(c.UV),
+			AirQuality:    c.AirQuality.toAirQuality(),
+		},
+	}
+
+	for _, fd := range r.Forecast.Forecastday {
+		out.Days = append(out.Days, fd.toDay())
+	}
+
+	for _, a := range r.Alerts.Alert {
+		out.Alerts = append(out.Alerts, forecast.Alert{Event: a.Event, Description: a.Desc})
+	}
+
+	return out
+}
+
+func (c apiCondition) toCondition() forecast.Condition {
+	return forecast.Condition{Text: c.Text, Code: c.Code}
+}
+
+// toAirQuality maps the pollutants, nil when weatherapi.com sent none.
+func (a *apiAirQuality) toAirQuality() *forecast.AirQuality {
+	if a == nil {
+		return nil
+	}
+	return &forecast.AirQuality{PM25: a.PM25, PM10: a.PM10}
+}
+
+func (fd *apiForecastDay) toDay() forecast.Day {
+	d, a := fd.Day, fd.Astro
+	out := forecast.Day{
+		Date: fd.Date,
+		Summary: forecast.Summary{
+			MaxTempC:      d.MaxTempC,
+			MaxTempF:      d.MaxTempF,
+			MinTempC:      d.MinTempC,
+			MinTempF:      d.MinTempF,
+			AvgTempC:      d.AvgTempC,
+			AvgTempF:      d.AvgTempF,
+			Condition:     d.Condition.toCondition(),
+			MaxWindMph:    forecast.Ptr(d.MaxWindMph),
+			MaxWindKph:    forecast.Ptr(d.MaxWindKph),
+			ChanceOfRain:  forecast.Ptr(float32(d.ChanceOfRain)),
+			ChanceOfSnow:  forecast.Ptr(float32(d.ChanceOfSnow)),
+			TotalPrecipMm: forecast.Ptr(d.TotalPrecipMm),
+			TotalPrecipIn: forecast.Ptr(d.TotalPrecipIn),
+			TotalSnowCm:   forecast.Ptr(d.TotalSnowCm),
+			AvgVisKm:      forecast.Ptr(d.AvgVisKm),
+			AvgVisMiles:   forecast.Ptr(d.AvgVisMiles),
+			AvgHumidity:   forecast.Ptr(d.AvgHumidity),
+			UV:            forecast.Ptr(d.UV),
+		},
+		AirQuality: fd.AirQuality.toAirQuality(),
+		Astro: forecast.Astro{
+			Sunrise:          a.Sunrise,
+			Sunset:           a.Sunset,
+			Moonrise:         a.Moonrise,
+			Moonset:          a.Moonset,
+			MoonPhase:        a.MoonPhase,
+			MoonIllumination: forecast.Ptr(a.MoonIllumination),
+			IsSunUp:          forecast.Ptr(a.IsSunUp == 1),
+			IsMoonUp:         forecast.Ptr(a.IsMoonUp == 1),
+		},
+	}
+
+	for _, h := range fd.Hour {
+		out.Hours = append(out.Hours, h.toHour())
+	}
+
+	return out
+}
+
+func (h *apiHour) toHour() forecast.Hour {
+	return forecast.Hour{
+		TimeUnix:      h.TimeEpoch,
+		TempC:         h.TempC,
+		TempF:         h.TempF,
+		IsDay:         h.IsDay == 1,
+		Condition:     h.Condition.toCondition(),
+		FeelsLikeC:    forecast.Ptr(h.FeelsLikeC),
+		FeelsLikeF:    forecast.Ptr(h.FeelsLikeF),
+		WindChillC:    h.WindChillC,
+		WindChillF:    h.WindChillF,
+		HeatIndexC:    h.HeatIndexC,
+		HeatIndexF:    h.HeatIndexF,
+		DewPointC:     h.DewPointC,
+		DewPointF:     h.DewPointF,
+		Humidity:      forecast.Ptr(h.Humidity),
+		Cloud:         forecast.Ptr(h.Cloud),
+		WindMph:       forecast.Ptr(h.WindMph),
+		WindKph:       forecast.Ptr(h.WindKph),
+		WindDegree:    forecast.Ptr(h.WindDegree),
+		WindDirection: h.WindDir,
+		GustMph:       forecast.Ptr(h.GustMph),
+		GustKph:       forecast.Ptr(h.GustKph),
+		PressureMb:    forecast.Ptr(h.PressureMb),
+		PressureIn:    forecast.Ptr(h.PressureIn),
+		PrecipMm:      forecast.Ptr(h.PrecipMm),
+		PrecipIn:      forecast.Ptr(h.PrecipIn),
+		SnowCm:        forecast.Ptr(h.SnowCm),
+		ChanceOfRain:  forecast.Ptr(h.ChanceOfRain),
+		ChanceOfSnow:  forecast.Ptr(h.ChanceOfSnow),
+		VisKm:         forecast.Ptr(h.VisKm),
+		VisMiles:      forecast.Ptr(h.VisMiles),
+		UV:            forecast.Ptr(h.UV),
+	}
+}
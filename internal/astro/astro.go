@@ -0,0 +1,105 @@
+// Package astro computes sunrise, sunset and twilight locally from a
+// location's coordinates, using the NOAA approximation of the sunrise
+// equation. Results are accurate to about a minute outside the polar
+// regions.
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// Altitudes of the sun's center, in degrees, that bound each period.
+// Sunrise allows for refraction and the sun's radius.
+const (
+	sunriseAltitude      = -0.833
+	civilAltitude        = -6
+	nauticalAltitude     = -12
+	astronomicalAltitude = -18
+)
+
+// j2000 is the Julian date of 2000-01-01 12:00 UTC.
+const j2000 = 2451545.0
+
+// Crossing is when the sun rises above and sets below an altitude on one
+// day. When it does not cross the altitude, Rise and Set are zero and
+// AlwaysAbove or AlwaysBelow says which side it stays on.
+type Crossing struct {
+	Rise, Set   time.Time
+	AlwaysAbove bool
+	AlwaysBelow bool
+}
+
+// Duration is how long the sun spends above the altitude.
+func (c Crossing) Duration() time.Duration {
+	switch {
+	case c.AlwaysAbove:
+		return 24 * time.Hour
+	case c.AlwaysBelow:
+		return 0
+	default:
+		return c.Set.Sub(c.Rise)
+	}
+}
+
+// Sun holds the day's sunrise and sunset (Daylight) and the start and end
+// of civil, nautical and astronomical twilight. Times are UTC.
+type Sun struct {
+	Daylight     Crossing
+	Civil        Crossing
+	Nautical     Crossing
+	Astronomical Crossing
+}
+
+// SunOn computes the sun's crossings at lat, lon on the calendar date of
+// date; the time of day and zone are ignored.
+func SunOn(date time.Time, lat, lon float64) Sun {
+	year, month, day := date.Date()
+	noon := time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+
+	// Days since J2000, at local mean noon.
+	n := math.Round(julian(noon) - j2000)
+	meanNoon := n - lon/360
+
+	anomaly := radians(math.Mod(357.5291+0.98560028*meanNoon, 360))
+	center := 1.9148*math.Sin(anomaly) + 0.02*math.Sin(2*anomaly) + 0.0003*math.Sin(3*anomaly)
+	longitude := radians(math.Mod(degrees(anomaly)+center+180+102.9372, 360))
+
+	transit := j2000 + meanNoon + 0.0053*math.Sin(anomaly) - 0.0069*math.Sin(2*longitude)
+	declination := math.Asin(math.Sin(longitude) * math.Sin(radians(23.4397)))
+
+	crossing := func(altitude float64) Crossing {
+		phi := radians(lat)
+		cosHour := (math.Sin(radians(altitude)) - math.Sin(phi)*math.Sin(declination)) /
+			(math.Cos(phi) * math.Cos(declination))
+
+		switch {
+		case cosHour < -1:
+			return Crossing{AlwaysAbove: true}
+		case cosHour > 1:
+			return Crossing{AlwaysBelow: true}
+		}
+
+		hourAngle := degrees(math.Acos(cosHour)) / 360
+		return Crossing{Rise: fromJulian(transit - hourAngle), Set: fromJulian(transit + hourAngle)}
+	}
+
+	return Sun{
+		Daylight:     crossing(sunriseAltitude),
+		Civil:        crossing(civilAltitude),
+		Nautical:     crossing(nauticalAltitude),
+		Astronomical: crossing(astronomicalAltitude),
+	}
+}
+
+func julian(t time.Time) float64 {
+	return float64(t.Unix())/86400 + 2440587.5
+}
+
+func fromJulian(j float64) time.Time {
+	return time.Unix(int64(math.Round((j-2440587.5)*86400)), 0).UTC()
+}
+
+func radians(d float64) float64 { return d * math.Pi / 180 }
+
+func degrees(r float64) float64 { return r * 180 / math.Pi }
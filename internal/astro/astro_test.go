@@ -0,0 +1,68 @@
+package astro
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSunOn(t *testing.T) {
+	london, _ := time.LoadLocation("Europe/London")
+	sydney, _ := time.LoadLocation("Australia/Sydney")
+
+	tests := []struct {
+		name         string
+		date         time.Time
+		lat, lon     float64
+		zone         *time.Location
+		rise, set    string
+		civilDawn    string
+		wantDuration time.Duration
+	}{
+		{"London midsummer", time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), 51.5074, -0.1278, london, "04:43", "21:21", "03:55", 16*time.Hour + 38*time.Minute},
+		{"London midwinter", time.Date(2026, 12, 21, 0, 0, 0, 0, time.UTC), 51.5074, -0.1278, london, "08:04", "15:54", "07:24", 7*time.Hour + 50*time.Minute},
+		{"Sydney equinox", time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC), -33.8688, 151.2093, sydney, "06:58", "19:08", "06:34", 12*time.Hour + 10*time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sun := SunOn(tt.date, tt.lat, tt.lon)
+
+			checkTime(t, "sunrise", sun.Daylight.Rise.In(tt.zone), tt.rise)
+			checkTime(t, "sunset", sun.Daylight.Set.In(tt.zone), tt.set)
+			checkTime(t, "civil dawn", sun.Civil.Rise.In(tt.zone), tt.civilDawn)
+
+			if diff := sun.Daylight.Duration() - tt.wantDuration; diff < -2*time.Minute || diff > 2*time.Minute {
+				t.Errorf("day length = %v, want about %v", sun.Daylight.Duration(), tt.wantDuration)
+			}
+			if !sun.Astronomical.Rise.Before(sun.Nautical.Rise) && !sun.Astronomical.AlwaysAbove {
+				t.Errorf("astronomical dawn %v is not before nautical dawn %v", sun.Astronomical.Rise, sun.Nautical.Rise)
+			}
+		})
+	}
+}
+
+func TestSunOn_Polar(t *testing.T) {
+	tromso := func(month time.Month) Sun {
+		return SunOn(time.Date(2026, month, 21, 0, 0, 0, 0, time.UTC), 69.65, 18.96)
+	}
+
+	if winter := tromso(time.December).Daylight; !winter.AlwaysBelow || winter.Duration() != 0 {
+		t.Errorf("polar night = %+v, want the sun always below the horizon", winter)
+	}
+	if summer := tromso(time.June).Daylight; !summer.AlwaysAbove || summer.Duration() != 24*time.Hour {
+		t.Errorf("midnight sun = %+v, want the sun always above the horizon", summer)
+	}
+	if london := SunOn(time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), 51.5, 0).Astronomical; !london.AlwaysAbove {
+		t.Errorf("London midsummer astronomical twilight = %+v, want no astronomical night", london)
+	}
+}
+
+// checkTime fails unless got is within two minutes of the wall clock want.
+func checkTime(t *testing.T, name string, got time.Time, want string) {
+	t.Helper()
+	clock, _ := time.Parse("15:04", want)
+	wantTime := time.Date(got.Year(), got.Month(), got.Day(), clock.Hour(), clock.Minute(), 0, 0, got.Location())
+	if diff := got.Sub(wantTime); diff < -2*time.Minute || diff > 2*time.Minute {
+		t.Errorf("%s = %s, want about %s", name, got.Format("15:04"), want)
+	}
+}
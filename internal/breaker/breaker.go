@@ -0,0 +1,125 @@
+// Package breaker implements a persistent circuit breaker that stops the CLI
+// from repeatedly calling a weather provider that keeps failing.
+package breaker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	DefaultThreshold = 3
+	DefaultWindow    = 10 * time.Minute
+	fileName         = "breaker.json"
+)
+
+// Breaker trips for a provider once it has failed Threshold times within
+// Window. State is persisted so separate invocations share it.
+type Breaker struct {
+	Failures map[string][]time.Time `json:"failures"`
+
+	path      string
+	threshold int
+	window    time.Duration
+	now       func() time.Time
+}
+
+// New loads the breaker state stored in dir.
+func New(dir string, threshold int, window time.Duration) *Breaker {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	if window <= 0 {
+		window = DefaultWindow
+	}
+
+	b := &Breaker{
+		Failures:  make(map[string][]time.Time),
+		path:      filepath.Join(dir, fileName),
+		threshold: threshold,
+		window:    window,
+		now:       time.Now,
+	}
+
+	if err := b.load(); err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load provider state (starting fresh): %v\n", err)
+		b.Failures = make(map[string][]time.Time)
+	}
+
+	return b
+}
+
+// Allow reports whether the provider may be called.
+func (b *Breaker) Allow(name string) bool {
+	return len(b.recent(name)) < b.threshold
+}
+
+// RecordFailure notes a failed call to the provider.
+func (b *Breaker) RecordFailure(name string) error {
+	b.Failures[name] = append(b.recent(name), b.now().UTC())
+	return b.save()
+}
+
+// RecordSuccess closes the breaker for the provider.
+func (b *Breaker) RecordSuccess(name string) error {
+	if _, ok := b.Failures[name]; !ok {
+		return nil
+	}
+	delete(b.Failures, name)
+	return b.save()
+}
+
+// recent returns the provider's failures that fall inside the window.
+func (b *Breaker) recent(name string) []time.Time {
+	cutoff := b.now().Add(-b.window)
+
+	var recent []time.Time
+	for _, t := range b.Failures[name] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	return recent
+}
+
+func (b *Breaker) load() error {
+	data, err := os.ReadFile(b.path)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, b); err != nil {
+		return err
+	}
+
+	if b.Failures == nil {
+		b.Failures = make(map[string][]time.Time)
+	}
+	return nil
+}
+
+func (b *Breaker) save() error {
+	if err := os.MkdirAll(filepath.Dir(b.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal provider state: %w", err)
+	}
+
+	tmpFile := b.path + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write provider state: %w", err)
+	}
+
+	if err := os.Rename(tmpFile, b.path); err != nil {
+		_ = os.Remove(tmpFile)
+		return fmt.Errorf("failed to rename provider state: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package breaker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestBreaker(t *testing.T, dir string, now *time.Time) *Breaker {
+	t.Helper()
+	b := New(dir, 2, 5*time.Minute)
+	b.now = func() time.Time { return *now }
+	return b
+}
+
+func TestBreaker_TripsAfterThreshold(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newTestBreaker(t, t.TempDir(), &now)
+
+	if !b.Allow("weatherapi") {
+		t.Fatal("Allow() = false before any failures")
+	}
+
+	_ = b.RecordFailure("weatherapi")
+	if !b.Allow("weatherapi") {
+		t.Error("Allow() = false after one failure, threshold is two")
+	}
+
+	_ = b.RecordFailure("weatherapi")
+	if b.Allow("weatherapi") {
+		t.Error("Allow() = true after reaching threshold")
+	}
+
+	if !b.Allow("open-meteo") {
+		t.Error("failures should not affect other providers")
+	}
+}
+
+func TestBreaker_WindowExpires(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newTestBreaker(t, t.TempDir(), &now)
+
+	_ = b.RecordFailure("weatherapi")
+	_ = b.RecordFailure("weatherapi")
+
+	now = now.Add(6 * time.Minute)
+	if !b.Allow("weatherapi") {
+		t.Error("Allow() = false after failures left the window")
+	}
+}
+
+func TestBreaker_SuccessResets(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newTestBreaker(t, t.TempDir(), &now)
+
+	_ = b.RecordFailure("weatherapi")
+	_ = b.RecordFailure("weatherapi")
+	_ = b.RecordSuccess("weatherapi")
+
+	if !b.Allow("weatherapi") {
+		t.Error("Allow() = false after success")
+	}
+}
+
+func TestBreaker_Persistence(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	b1 := newTestBreaker(t, dir, &now)
+	_ = b1.RecordFailure("nws")
+	_ = b1.RecordFailure("nws")
+
+	if _, err := os.Stat(filepath.Join(dir, "breaker.json")); err != nil {
+		t.Fatalf("state file not written: %v", err)
+	}
+
+	b2 := newTestBreaker(t, dir, &now)
+	if b2.Allow("nws") {
+		t.Error("Allow() = true, want persisted open breaker")
+	}
+}
+
+func TestBreaker_CorruptedFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "breaker.json"), []byte("{{{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	b := New(dir, 0, 0)
+	if !b.Allow("weatherapi") {
+		t.Error("Allow() = false with corrupted state, want fresh start")
+	}
+	if err := b.RecordFailure("weatherapi"); err != nil {
+		t.Errorf("RecordFailure() error = %v", err)
+	}
+}
@@ -6,10 +6,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
-	"github.com/jtotty/weather-cli/internal/api/weather"
+	"github.com/jtotty/weather-cli/internal/forecast"
 )
 
 const (
@@ -17,35 +18,115 @@ const (
 	cacheSubDir     = "weather-cli"
 	cacheFileName   = "cache.json"
 	maxCacheEntries = 100
+
+	// maxHistoryEntries caps the history namespace, whose entries are
+	// single days kept indefinitely: a year of days at a few places.
+	maxHistoryEntries = 2000
 )
 
+// FormatVersion is bumped whenever the cached forecast model changes shape;
+// files written in another format are discarded on load.
+const FormatVersion = 1
+
+// KeepStale is how long expired entries are kept past their TTL, to be
+// served when no provider can be reached.
+const KeepStale = 48 * time.Hour
+
+// NoExpiry keeps entries until they are evicted to make room or cleared.
+const NoExpiry time.Duration = -1
+
+// ErrNotCached reports that an offline lookup found nothing in the cache.
+var ErrNotCached = errors.New("not in the cache")
+
+// HistoryNamespace holds historical observations, which never change.
+const HistoryNamespace = "history"
+
+// PlacesNamespace remembers which place a free-text location was resolved
+// to. Each entry holds only the place's Location.
+const PlacesNamespace = "places"
+
 type Entry struct {
-	Location string            `json:"location"`
-	Data     *weather.Response `json:"data"`
-	CachedAt time.Time         `json:"cached_at"`
+	Location string             `json:"location"`
+	Data     *forecast.Response `json:"data"`
+	CachedAt time.Time          `json:"cached_at"`
+
+	// Key records the request a forecast entry answers; entries stored
+	// with Set have none.
+	Key *Key `json:"key,omitempty"`
+
+	// Fetched records when sections refreshed on their own since CachedAt
+	// were last fetched.
+	Fetched map[string]time.Time `json:"fetched,omitempty"`
 }
 
 func (e *Entry) IsValid(ttl time.Duration) bool {
-	return time.Since(e.CachedAt) < ttl
+	return ttl == NoExpiry || time.Since(e.CachedAt) < ttl
+}
+
+// Size returns the number of bytes e takes up in the cache file.
+func (e *Entry) Size() int {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// answers reports whether e holds a forecast for want. With byName it also
+// matches an entry cached under coordinates by its place name, as an
+// offline lookup cannot search for the coordinates a name resolves to.
+func (e *Entry) answers(want Key, byName bool) bool {
+	if e.Key == nil || e.Data == nil {
+		return false
+	}
+	if e.Key.Covers(want) {
+		return true
+	}
+
+	named := *e.Key
+	named.Location = normalizeKey(e.Data.Location.Name)
+	return byName && named.Covers(want)
 }
 
 type Cache struct {
+	Version int               `json:"version"`
 	Entries map[string]*Entry `json:"entries"`
 	path    string            `json:"-"`
 	ttl     time.Duration     `json:"-"`
+
+	// sections holds per-section TTLs; when zero every section uses ttl.
+	sections SectionTTLs `json:"-"`
+
+	// maxEntries caps the number of entries; zero means maxCacheEntries.
+	maxEntries int `json:"-"`
 }
 
 func New(ttl time.Duration) (*Cache, error) {
+	return open(cacheFileName, ttl)
+}
+
+// NewNamespace opens a cache kept apart from the forecast cache, in its own
+// file, so entries with different lifetimes never evict each other. The
+// history namespace holds more entries than the others.
+func NewNamespace(name string, ttl time.Duration) (*Cache, error) {
+	c, err := open(name+".json", ttl)
+	if err == nil && name == HistoryNamespace {
+		c.maxEntries = maxHistoryEntries
+	}
+	return c, err
+}
+
+func open(fileName string, ttl time.Duration) (*Cache, error) {
 	if ttl == 0 {
 		ttl = DefaultTTL
 	}
 
-	cacheDir, err := getCacheDir()
+	cacheDir, err := Dir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cache directory: %w", err)
 	}
 
-	cachePath := filepath.Join(cacheDir, cacheFileName)
+	cachePath := filepath.Join(cacheDir, fileName)
 
 	cache := &Cache{
 		Entries: make(map[string]*Entry),
@@ -64,21 +145,21 @@ func New(ttl time.Duration) (*Cache, error) {
 	return cache, nil
 }
 
-func (c *Cache) Get(location string) *weather.Response {
+func (c *Cache) Get(location string) *forecast.Response {
 	key := normalizeKey(location)
 	entry, ok := c.Entries[key]
 	if !ok {
 		return nil
 	}
 
-	if !entry.IsValid(c.ttl) {
+	if !c.valid(entry) {
 		return nil
 	}
 
 	return entry.Data
 }
 
-func (c *Cache) Set(location string, data *weather.Response) error {
+func (c *Cache) Set(location string, data *forecast.Response) error {
 	if data == nil {
 		return errors.New("cannot cache nil weather data")
 	}
@@ -88,35 +169,210 @@ func (c *Cache) Set(location string, data *weather.Response) error {
 		return errors.New("cannot cache empty location")
 	}
 
-	c.cleanupExpired()
+	return c.update(func() {
+		c.cleanupExpired()
+
+		if len(c.Entries) >= c.limit() {
+			c.removeOldest()
+		}
+
+		key := normalizeKey(location)
+		c.Entries[key] = &Entry{
+			Location: location,
+			Data:     data,
+			CachedAt: time.Now().UTC(),
+		}
+	})
+}
+
+// Lookup returns a valid forecast that answers want: one fetched with the
+// same options, or else the newest broader one, trimmed to what want asks
+// for. current reports whether its current conditions are fresh too.
+func (c *Cache) Lookup(want Key) (data *forecast.Response, current bool) {
+	if entry, ok := c.Entries[want.String()]; ok && c.valid(entry) {
+		return entry.Data, c.fresh(entry, SectionCurrent, time.Now())
+	}
 
-	if len(c.Entries) >= maxCacheEntries {
-		c.removeOldest()
+	entry := c.newest(want, c.valid)
+	if entry == nil {
+		return nil, false
 	}
+	return narrow(entry.Data, want), c.fresh(entry, SectionCurrent, time.Now())
+}
 
-	key := normalizeKey(location)
-	c.Entries[key] = &Entry{
-		Location: location,
-		Data:     data,
-		CachedAt: time.Now().UTC(),
+// Stale returns the newest forecast that answers want however old it is,
+// and when it was cached, or nil when there is none. It is the fallback
+// when no provider can be reached.
+func (c *Cache) Stale(want Key) (*forecast.Response, time.Time) {
+	entry := c.newest(want, nil)
+	if entry == nil {
+		return nil, time.Time{}
 	}
+	return narrow(entry.Data, want), entry.CachedAt
+}
 
-	return c.save()
+// newest returns the most recently cached entry answering want among those
+// usable accepts, or among every entry, stale ones included, when usable
+// is nil.
+func (c *Cache) newest(want Key, usable func(*Entry) bool) *Entry {
+	stale := usable == nil
+	var best *Entry
+	for _, entry := range c.Entries {
+		if !stale && !usable(entry) || !entry.answers(want, stale) {
+			continue
+		}
+		if best == nil || entry.CachedAt.After(best.CachedAt) {
+			best = entry
+		}
+	}
+	return best
+}
+
+// Store caches a forecast fetched for key under key's fingerprint,
+// replacing the narrower entries it now answers.
+func (c *Cache) Store(key Key, location string, data *forecast.Response) error {
+	if data == nil {
+		return errors.New("cannot cache nil weather data")
+	}
+
+	location = strings.TrimSpace(location)
+	if location == "" || key.Location == "" {
+		return errors.New("cannot cache empty location")
+	}
+
+	return c.update(func() {
+		c.cleanupExpired()
+
+		for name, entry := range c.Entries {
+			if entry.Key != nil && key.Covers(*entry.Key) {
+				delete(c.Entries, name)
+			}
+		}
+
+		if len(c.Entries) >= c.limit() {
+			c.removeOldest()
+		}
+
+		c.Entries[key.String()] = &Entry{
+			Location: location,
+			Data:     data,
+			CachedAt: time.Now().UTC(),
+			Key:      &key,
+		}
+	})
 }
 
 func (c *Cache) Clear() error {
+	return c.update(func() {
+		c.Entries = make(map[string]*Entry)
+	})
+}
+
+// update applies change to the entries on disk and saves them while holding
+// the cache's file lock, so concurrent processes never overwrite each
+// other's entries. The in-memory entries are replaced by the merged result.
+func (c *Cache) update(change func()) error {
+	unlock, err := c.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Re-read what other processes have written since this cache was
+	// opened; an unreadable file is replaced.
 	c.Entries = make(map[string]*Entry)
+	if err := c.load(); err != nil || c.Entries == nil {
+		c.Entries = make(map[string]*Entry)
+	}
+
+	change()
 	return c.save()
 }
 
+// lock takes an exclusive advisory lock on the file beside the cache,
+// blocking while another process holds it.
+func (c *Cache) lock() (unlock func(), err error) {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	f, err := os.OpenFile(c.path+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache lock: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to lock cache: %w", err)
+	}
+
+	return func() {
+		_ = unlockFile(f)
+		_ = f.Close()
+	}, nil
+}
+
+// Prune removes every expired entry, including those kept as an offline
+// fallback, and returns how many were removed.
+func (c *Cache) Prune() (int, error) {
+	removed := 0
+	err := c.update(func() {
+		for key, entry := range c.Entries {
+			if c.Expired(entry) {
+				delete(c.Entries, key)
+				removed++
+			}
+		}
+	})
+	return removed, err
+}
+
+// List returns every entry ordered by location, newest first within a
+// location.
+func (c *Cache) List() []*Entry {
+	entries := make([]*Entry, 0, len(c.Entries))
+	for _, entry := range c.Entries {
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := normalizeKey(entries[i].Location), normalizeKey(entries[j].Location)
+		if a != b {
+			return a < b
+		}
+		return entries[i].CachedAt.After(entries[j].CachedAt)
+	})
+	return entries
+}
+
+// Find returns the entries for location, matched case-insensitively against
+// the location they were requested for, ignoring a history entry's date,
+// or the place name in the data.
+func (c *Cache) Find(location string) []*Entry {
+	want := normalizeKey(location)
+
+	var found []*Entry
+	for _, entry := range c.List() {
+		requested, _, _ := strings.Cut(entry.Location, "|")
+		if normalizeKey(requested) == want || (entry.Data != nil && normalizeKey(entry.Data.Location.Name) == want) {
+			found = append(found, entry)
+		}
+	}
+	return found
+}
+
 func (c *Cache) Path() string {
 	return c.path
 }
 
+// TTL returns how long entries stay valid, or NoExpiry.
+func (c *Cache) TTL() time.Duration {
+	return c.ttl
+}
+
 func (c *Cache) Stats() (total, valid, expired int) {
 	total = len(c.Entries)
 	for _, entry := range c.Entries {
-		if entry.IsValid(c.ttl) {
+		if !c.Expired(entry) {
 			valid++
 		} else {
 			expired++
@@ -131,7 +387,13 @@ func (c *Cache) load() error {
 		return err
 	}
 
-	return json.Unmarshal(data, c)
+	if err := json.Unmarshal(data, c); err != nil {
+		return err
+	}
+	if c.Version != FormatVersion {
+		c.Entries = make(map[string]*Entry)
+	}
+	return nil
 }
 
 func (c *Cache) save() error {
@@ -140,6 +402,7 @@ func (c *Cache) save() error {
 		return fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
+	c.Version = FormatVersion
 	data, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal cache: %w", err)
@@ -160,14 +423,26 @@ func (c *Cache) save() error {
 	return nil
 }
 
+// cleanupExpired removes entries more than KeepStale past their TTL.
 func (c *Cache) cleanupExpired() {
+	if c.ttl == NoExpiry {
+		return
+	}
 	for key, entry := range c.Entries {
-		if !entry.IsValid(c.ttl) {
+		if !entry.IsValid(c.ttl + KeepStale) {
 			delete(c.Entries, key)
 		}
 	}
 }
 
+// limit returns the most entries c holds before evicting the oldest.
+func (c *Cache) limit() int {
+	if c.maxEntries != 0 {
+		return c.maxEntries
+	}
+	return maxCacheEntries
+}
+
 func (c *Cache) removeOldest() {
 	var oldestKey string
 	var oldestTime time.Time
@@ -186,7 +461,8 @@ func (c *Cache) removeOldest() {
 	}
 }
 
-func getCacheDir() (string, error) {
+// Dir returns the directory holding the cache file and other per-user state.
+func Dir() (string, error) {
 	userCacheDir, err := os.UserCacheDir()
 	if err != nil {
 		return "", err
@@ -3,11 +3,14 @@ package cache
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
-	"github.com/jtotty/weather-cli/internal/api/weather"
+	"github.com/jtotty/weather-cli/internal/forecast"
 )
 
 func TestNormalizeKey(t *testing.T) {
@@ -77,6 +80,12 @@ func TestEntryIsValid(t *testing.T) {
 			ttl:      30 * time.Minute,
 			want:     true,
 		},
+		{
+			name:     "no expiry",
+			cachedAt: time.Now().AddDate(-5, 0, 0),
+			ttl:      NoExpiry,
+			want:     true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -105,8 +114,8 @@ func TestCache(t *testing.T) {
 	}
 
 	// Create mock weather response
-	mockResponse := &weather.Response{
-		Location: weather.Location{
+	mockResponse := &forecast.Response{
+		Location: forecast.Location{
 			Name:    "London",
 			Country: "UK",
 		},
@@ -171,6 +180,46 @@ func TestCache(t *testing.T) {
 	})
 }
 
+func TestNewNamespace(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	history, err := NewNamespace(HistoryNamespace, NoExpiry)
+	if err != nil {
+		t.Fatalf("NewNamespace() error = %v", err)
+	}
+	forecasts, err := New(DefaultTTL)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if filepath.Base(history.Path()) != "history.json" || filepath.Dir(history.Path()) != filepath.Dir(forecasts.Path()) {
+		t.Errorf("history path = %s, want history.json beside %s", history.Path(), forecasts.Path())
+	}
+
+	if err := history.Set("London|2026-09-01", &forecast.Response{}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if forecasts.Get("London|2026-09-01") != nil {
+		t.Error("history entry visible in the forecast cache")
+	}
+
+	reopened, _ := NewNamespace(HistoryNamespace, NoExpiry)
+	if reopened.Get("london|2026-09-01") == nil {
+		t.Error("history entry not persisted")
+	}
+
+	// History keeps more days than the forecast cache's entry cap.
+	for i := range maxCacheEntries + 10 {
+		if err := history.Set(fmt.Sprintf("London|day %d", i), &forecast.Response{}); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+	if total, _, _ := history.Stats(); total != maxCacheEntries+11 {
+		t.Errorf("history entries = %d, want all %d kept", total, maxCacheEntries+11)
+	}
+}
+
 func TestCachePersistence(t *testing.T) {
 	// Create a temporary directory for testing
 	tmpDir, err := os.MkdirTemp("", "weather-cli-cache-persist-test")
@@ -182,8 +231,8 @@ func TestCachePersistence(t *testing.T) {
 	cachePath := filepath.Join(tmpDir, "cache.json")
 
 	// Create mock weather response
-	mockResponse := &weather.Response{
-		Location: weather.Location{
+	mockResponse := &forecast.Response{
+		Location: forecast.Location{
 			Name:    "London",
 			Country: "UK",
 		},
@@ -223,6 +272,36 @@ func TestCachePersistence(t *testing.T) {
 	}
 }
 
+func TestCacheDiscardsOtherFormats(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+
+	// A file from before the format was versioned, in the old model.
+	old := `{"entries": {"london": {"location": "London", "cached_at": "` +
+		time.Now().Format(time.RFC3339) + `", "data": {"forecast": {"forecastday": []}}}}}`
+	if err := os.WriteFile(cachePath, []byte(old), 0o600); err != nil {
+		t.Fatalf("failed to write cache: %v", err)
+	}
+
+	c := &Cache{Entries: make(map[string]*Entry), path: cachePath, ttl: 30 * time.Minute}
+	if err := c.load(); err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	if len(c.Entries) != 0 {
+		t.Fatalf("load() kept %d entries from another format, want 0", len(c.Entries))
+	}
+
+	if err := c.Set("London", &forecast.Response{Location: forecast.Location{Name: "London"}}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	reopened := &Cache{Entries: make(map[string]*Entry), path: cachePath, ttl: 30 * time.Minute}
+	if err := reopened.load(); err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	if reopened.Get("London") == nil {
+		t.Error("Get() = nil after reopening, want the entry written in the current format")
+	}
+}
+
 func TestCacheInputValidation(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "weather-cli-cache-validation-test")
 	if err != nil {
@@ -244,8 +323,8 @@ func TestCacheInputValidation(t *testing.T) {
 	})
 
 	t.Run("empty location", func(t *testing.T) {
-		mockResponse := &weather.Response{
-			Location: weather.Location{Name: "London"},
+		mockResponse := &forecast.Response{
+			Location: forecast.Location{Name: "London"},
 		}
 		err := cache.Set("", mockResponse)
 		if err == nil {
@@ -254,8 +333,8 @@ func TestCacheInputValidation(t *testing.T) {
 	})
 
 	t.Run("whitespace only location", func(t *testing.T) {
-		mockResponse := &weather.Response{
-			Location: weather.Location{Name: "London"},
+		mockResponse := &forecast.Response{
+			Location: forecast.Location{Name: "London"},
 		}
 		err := cache.Set("   ", mockResponse)
 		if err == nil {
@@ -297,8 +376,8 @@ func TestCacheCorruptedFile(t *testing.T) {
 	}
 
 	// Should be able to write new data
-	mockResponse := &weather.Response{
-		Location: weather.Location{Name: "London"},
+	mockResponse := &forecast.Response{
+		Location: forecast.Location{Name: "London"},
 	}
 	err = cache.Set("London", mockResponse)
 	if err != nil {
@@ -319,8 +398,8 @@ func TestCacheStats(t *testing.T) {
 		ttl:     1 * time.Hour,
 	}
 
-	mockResponse := &weather.Response{
-		Location: weather.Location{Name: "London"},
+	mockResponse := &forecast.Response{
+		Location: forecast.Location{Name: "London"},
 	}
 
 	// Add some entries
@@ -360,8 +439,8 @@ func TestCacheMaxEntries(t *testing.T) {
 		ttl:     1 * time.Hour,
 	}
 
-	mockResponse := &weather.Response{
-		Location: weather.Location{Name: "Test"},
+	mockResponse := &forecast.Response{
+		Location: forecast.Location{Name: "Test"},
 	}
 
 	// Add more than maxCacheEntries
@@ -395,8 +474,8 @@ func TestCacheAtomicWrite(t *testing.T) {
 		ttl:     30 * time.Minute,
 	}
 
-	mockResponse := &weather.Response{
-		Location: weather.Location{Name: "London"},
+	mockResponse := &forecast.Response{
+		Location: forecast.Location{Name: "London"},
 	}
 
 	err = cache.Set("London", mockResponse)
@@ -422,3 +501,147 @@ func TestCacheAtomicWrite(t *testing.T) {
 		t.Errorf("cache file permissions = %o, want 0600", perm)
 	}
 }
+
+func TestCacheMergesConcurrentWriters(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+
+	// Both caches are opened before either writes, as two invocations
+	// started together would be.
+	first := &Cache{Entries: make(map[string]*Entry), path: cachePath, ttl: time.Hour}
+	second := &Cache{Entries: make(map[string]*Entry), path: cachePath, ttl: time.Hour}
+
+	if err := first.Set("London", &forecast.Response{}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := second.Set("Paris", &forecast.Response{}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	reopened := &Cache{Entries: make(map[string]*Entry), path: cachePath, ttl: time.Hour}
+	if err := reopened.load(); err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	if reopened.Get("London") == nil || reopened.Get("Paris") == nil {
+		t.Errorf("entries = %v, want London and Paris", reopened.Entries)
+	}
+	if second.Get("London") == nil {
+		t.Error("Set() did not merge the other writer's entries into memory")
+	}
+}
+
+// writeLocations sets count distinct locations through its own Cache, as a
+// separate invocation would.
+func writeLocations(cachePath, prefix string, count int) error {
+	c := &Cache{Entries: make(map[string]*Entry), path: cachePath, ttl: time.Hour}
+	for i := range count {
+		location := fmt.Sprintf("%s-%d", prefix, i)
+		if err := c.Set(location, &forecast.Response{Location: forecast.Location{Name: location}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func assertLocations(t *testing.T, cachePath string, writers, perWriter int) {
+	t.Helper()
+
+	c := &Cache{Entries: make(map[string]*Entry), path: cachePath, ttl: time.Hour}
+	if err := c.load(); err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	for w := range writers {
+		for i := range perWriter {
+			if location := fmt.Sprintf("writer%d-%d", w, i); c.Get(location) == nil {
+				t.Errorf("entry %s lost", location)
+			}
+		}
+	}
+}
+
+func TestCacheConcurrentGoroutines(t *testing.T) {
+	const writers, perWriter = 20, 5
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for w := range writers {
+		wg.Go(func() {
+			errs <- writeLocations(cachePath, fmt.Sprintf("writer%d", w), perWriter)
+		})
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+	assertLocations(t, cachePath, writers, perWriter)
+}
+
+func TestCacheConcurrentProcesses(t *testing.T) {
+	if path := os.Getenv("WEATHER_TEST_CACHE_PATH"); path != "" {
+		count, _ := strconv.Atoi(os.Getenv("WEATHER_TEST_CACHE_COUNT"))
+		if err := writeLocations(path, os.Getenv("WEATHER_TEST_CACHE_PREFIX"), count); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	const writers, perWriter = 8, 10
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+
+	cmds := make([]*exec.Cmd, writers)
+	for w := range cmds {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestCacheConcurrentProcesses$")
+		cmd.Env = append(os.Environ(),
+			"WEATHER_TEST_CACHE_PATH="+cachePath,
+			"WEATHER_TEST_CACHE_PREFIX="+fmt.Sprintf("writer%d", w),
+			"WEATHER_TEST_CACHE_COUNT="+strconv.Itoa(perWriter),
+		)
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("failed to start writer: %v", err)
+		}
+		cmds[w] = cmd
+	}
+	for _, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("writer failed: %v", err)
+		}
+	}
+
+	assertLocations(t, cachePath, writers, perWriter)
+}
+
+func TestCachePruneAndFind(t *testing.T) {
+	c := &Cache{
+		Entries: make(map[string]*Entry),
+		path:    filepath.Join(t.TempDir(), "cache.json"),
+		ttl:     time.Hour,
+	}
+
+	key := Key{Location: "51.51,-0.13", Provider: "weatherapi", Days: 3}
+	if err := c.Store(key, "51.51,-0.13", &forecast.Response{Location: forecast.Location{Name: "London"}}); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	expired := &Entry{Location: "Paris", Data: &forecast.Response{}, CachedAt: time.Now().Add(-2 * time.Hour)}
+	if err := c.update(func() { c.Entries["paris"] = expired }); err != nil {
+		t.Fatalf("update() error = %v", err)
+	}
+
+	if found := c.Find("LONDON"); len(found) != 1 || found[0].Key == nil {
+		t.Errorf("Find(LONDON) = %v, want the entry by place name", found)
+	}
+	if found := c.Find("paris"); len(found) != 1 {
+		t.Errorf("Find(paris) = %v, want the entry by location", found)
+	}
+
+	if removed, err := c.Prune(); err != nil || removed != 1 {
+		t.Errorf("Prune() = %d, %v; want 1 removed", removed, err)
+	}
+	if list := c.List(); len(list) != 1 || list[0].Location != "51.51,-0.13" {
+		t.Errorf("List() after Prune = %v", list)
+	}
+}
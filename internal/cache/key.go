@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+// Key identifies a forecast request: everything in the fetch options that
+// changes the response, plus the provider chain that answered it.
+type Key struct {
+	Location string `json:"location"`
+	Provider string `json:"provider"`
+	Days     int    `json:"days"`
+	AQI      bool   `json:"aqi"`
+	Alerts   bool   `json:"alerts"`
+}
+
+// NewKey builds the key for a request sent to the providers, in order.
+func NewKey(opts forecast.Options, providers []string) Key {
+	return Key{
+		Location: normalizeKey(opts.Location),
+		Provider: strings.ToLower(strings.Join(providers, ",")),
+		Days:     opts.Days,
+		AQI:      opts.IncludeAQI,
+		Alerts:   opts.Alerts,
+	}
+}
+
+// String returns the canonical fingerprint used as the entry's map key, e.g.
+// "london|p=weatherapi|days=7|aqi=1|alerts=0".
+func (k Key) String() string {
+	return fmt.Sprintf("%s|p=%s|days=%d|aqi=%d|alerts=%d",
+		k.Location, k.Provider, k.Days, flag(k.AQI), flag(k.Alerts))
+}
+
+// Covers reports whether a response fetched for k holds everything want
+// asks for: the same place and provider, at least as many days,
+// and air quality and alerts if requested.
+func (k Key) Covers(want Key) bool {
+	return k.Location == want.Location &&
+		k.Provider == want.Provider &&
+		k.Days >= want.Days &&
+		(k.AQI || !want.AQI) &&
+		(k.Alerts || !want.Alerts)
+}
+
+// narrow returns a copy of data holding only what want asks for, so a
+// broader response looks exactly like one fetched for want.
+func narrow(data *forecast.Response, want Key) *forecast.Response {
+	narrowed := *data
+	days := data.Days
+	if want.Days < len(days) {
+		narrowed.Days = slices.Clone(days[:want.Days])
+	}
+	if !want.AQI {
+		narrowed.Current.AirQuality = nil
+		narrowed.Days = slices.Clone(narrowed.Days)
+		for i := range narrowed.Days {
+			narrowed.Days[i].AirQuality = nil
+		}
+	}
+	if !want.Alerts {
+		narrowed.Alerts = nil
+	}
+	return &narrowed
+}
+
+func flag(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
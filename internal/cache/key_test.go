@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+func TestNewKey(t *testing.T) {
+	opts := forecast.Options{Location: "  London ", Days: 7, IncludeAQI: true}
+
+	got := NewKey(opts, []string{"weatherapi", "open-meteo"}).String()
+	want := "london|p=weatherapi,open-meteo|days=7|aqi=1|alerts=0"
+	if got != want {
+		t.Errorf("NewKey().String() = %q, want %q", got, want)
+	}
+}
+
+func TestKeyCovers(t *testing.T) {
+	broad := Key{Location: "london", Provider: "weatherapi", Days: 7, AQI: true, Alerts: true}
+
+	tests := []struct {
+		name string
+		have Key
+		want Key
+		ok   bool
+	}{
+		{"same request", broad, broad, true},
+		{"fewer days", broad, Key{Location: "london", Provider: "weatherapi", Days: 3}, true},
+		{"more days", Key{Location: "london", Provider: "weatherapi", Days: 1}, broad, false},
+		{"needs alerts", Key{Location: "london", Provider: "weatherapi", Days: 7, AQI: true}, broad, false},
+		{"needs aqi", Key{Location: "london", Provider: "weatherapi", Days: 7, Alerts: true}, broad, false},
+		{"other location", broad, Key{Location: "paris", Provider: "weatherapi", Days: 1}, false},
+		{"other provider", broad, Key{Location: "london", Provider: "open-meteo", Days: 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.have.Covers(tt.want); got != tt.ok {
+				t.Errorf("Covers() = %v, want %v", got, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCacheLookup(t *testing.T) {
+	c := &Cache{
+		Entries: make(map[string]*Entry),
+		path:    filepath.Join(t.TempDir(), "cache.json"),
+		ttl:     time.Hour,
+	}
+
+	week := &forecast.Response{
+		Current: forecast.Current{AirQuality: &forecast.AirQuality{PM25: 12}},
+		Days:    make([]forecast.Day, 7),
+		Alerts:  []forecast.Alert{{Event: "Flood"}},
+	}
+	broad := Key{Location: "london", Provider: "weatherapi", Days: 7, AQI: true, Alerts: true}
+	if err := c.Store(broad, "London", week); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	if got, _ := c.Lookup(broad); got != week {
+		t.Error("Lookup() of the stored key should return the stored response")
+	}
+
+	narrow := Key{Location: "london", Provider: "weatherapi", Days: 3}
+	got, _ := c.Lookup(narrow)
+	if got == nil {
+		t.Fatal("Lookup() of a narrower request = nil, want the sliced week")
+	}
+	if len(got.Days) != 3 || got.Current.AirQuality != nil || len(got.Alerts) != 0 {
+		t.Errorf("narrowed response = %d days, aqi %v, alerts %v", len(got.Days), got.Current.AirQuality, got.Alerts)
+	}
+	if len(week.Days) != 7 || week.Current.AirQuality == nil {
+		t.Error("narrowing modified the cached response")
+	}
+
+	longer := Key{Location: "london", Provider: "weatherapi", Days: 10, AQI: true, Alerts: true}
+	if got, _ := c.Lookup(longer); got != nil {
+		t.Error("Lookup() of a longer forecast should miss")
+	}
+
+	// Storing the broader request replaces the narrower entry it answers.
+	if err := c.Store(narrow, "London", &forecast.Response{}); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	if err := c.Store(longer, "London", &forecast.Response{}); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	if len(c.Entries) != 1 || c.Entries[longer.String()] == nil {
+		t.Errorf("entries = %v, want only %s", c.Entries, longer)
+	}
+}
+
+func TestCacheStale(t *testing.T) {
+	c := &Cache{
+		Entries: make(map[string]*Entry),
+		path:    filepath.Join(t.TempDir(), "cache.json"),
+		ttl:     time.Hour,
+	}
+
+	key := Key{Location: "51.51,-0.13", Provider: "weatherapi", Days: 3}
+	cachedAt := time.Now().Add(-5 * time.Hour).UTC()
+	c.Entries[key.String()] = &Entry{
+		Location: "51.51,-0.13",
+		Data:     &forecast.Response{Location: forecast.Location{Name: "London"}},
+		CachedAt: cachedAt,
+		Key:      &key,
+	}
+
+	if got, _ := c.Lookup(key); got != nil {
+		t.Error("Lookup() should ignore the expired entry")
+	}
+	if data, at := c.Stale(key); data == nil || !at.Equal(cachedAt) {
+		t.Errorf("Stale() = %v, %v; want the expired entry cached at %v", data, at, cachedAt)
+	}
+
+	// Offline there is no search, so a place name finds its coordinates.
+	byName := key
+	byName.Location = "london"
+	if data, _ := c.Stale(byName); data == nil {
+		t.Error("Stale() by place name = nil, want the entry cached under coordinates")
+	}
+	if got, _ := c.Lookup(byName); got != nil {
+		t.Error("Lookup() should not match by place name")
+	}
+
+	// Expired entries are kept for KeepStale before being cleaned up.
+	c.cleanupExpired()
+	if len(c.Entries) != 1 {
+		t.Errorf("cleanupExpired() left %d entries, want the stale one kept", len(c.Entries))
+	}
+	c.Entries[key.String()].CachedAt = time.Now().Add(-time.Hour - KeepStale)
+	c.cleanupExpired()
+	if len(c.Entries) != 0 {
+		t.Errorf("cleanupExpired() left %d entries, want none", len(c.Entries))
+	}
+}
@@ -0,0 +1,194 @@
+package cache
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+// Sections of a cached forecast, which expire independently.
+const (
+	SectionCurrent  = "current"
+	SectionForecast = "forecast"
+	SectionAstro    = "astro"
+)
+
+// Default section lifetimes: current conditions change by the minute, the
+// hourly and daily forecast over hours, and sunrise or moon phase once a day.
+const (
+	DefaultCurrentTTL  = 15 * time.Minute
+	DefaultForecastTTL = 2 * time.Hour
+	DefaultAstroTTL    = 24 * time.Hour
+)
+
+// SectionTTLs sets how long each section of a cached forecast stays fresh.
+// Zero values use the defaults.
+type SectionTTLs struct {
+	Current  time.Duration
+	Forecast time.Duration
+	Astro    time.Duration
+}
+
+// withDefaults fills in the zero TTLs.
+func (t SectionTTLs) withDefaults() SectionTTLs {
+	if t.Current == 0 {
+		t.Current = DefaultCurrentTTL
+	}
+	if t.Forecast == 0 {
+		t.Forecast = DefaultForecastTTL
+	}
+	if t.Astro == 0 {
+		t.Astro = DefaultAstroTTL
+	}
+	return t
+}
+
+// of returns the TTL of section, or zero for an unknown one.
+func (t SectionTTLs) of(section string) time.Duration {
+	switch section {
+	case SectionCurrent:
+		return t.Current
+	case SectionForecast:
+		return t.Forecast
+	case SectionAstro:
+		return t.Astro
+	}
+	return 0
+}
+
+// NewForecast opens the forecast cache with a lifetime for each section.
+// An entry stays valid while its forecast is fresh, as only a full fetch
+// replaces it; its current conditions can be refreshed alone with
+// StoreCurrent, and its astronomy is served on its own by LookupAstro for
+// as long as that stays fresh. Entries are kept for the longer of the two.
+func NewForecast(ttls SectionTTLs) (*Cache, error) {
+	ttls = ttls.withDefaults()
+	c, err := open(cacheFileName, max(ttls.Forecast, ttls.Astro))
+	if err != nil {
+		return nil, err
+	}
+	c.sections = ttls
+	return c, nil
+}
+
+// FetchedAt returns when section of e was last fetched.
+func (e *Entry) FetchedAt(section string) time.Time {
+	if at, ok := e.Fetched[section]; ok {
+		return at
+	}
+	return e.CachedAt
+}
+
+// sectionTTL returns how long section stays fresh, falling back to the
+// cache's TTL when no section TTLs are set.
+func (c *Cache) sectionTTL(section string) time.Duration {
+	if ttl := c.sections.of(section); ttl != 0 {
+		return ttl
+	}
+	return c.ttl
+}
+
+// fresh reports whether section of e is still fresh at now. A forecast
+// entry's forecast and astronomy also expire once its first day has ended
+// at the location, whatever their TTL, as they no longer start today.
+func (c *Cache) fresh(e *Entry, section string, now time.Time) bool {
+	ttl := c.sectionTTL(section)
+	if ttl != NoExpiry && now.Sub(e.FetchedAt(section)) >= ttl {
+		return false
+	}
+	return section == SectionCurrent || e.Key == nil || !dayEnded(e.Data, now)
+}
+
+// valid reports whether e can be served without a full fetch: its forecast
+// is fresh, though its current conditions may not be.
+func (c *Cache) valid(e *Entry) bool {
+	return c.fresh(e, SectionForecast, time.Now())
+}
+
+// Expired reports whether nothing but the current conditions of e is fresh,
+// so it can no longer be served, even for its astronomy alone.
+func (c *Cache) Expired(e *Entry) bool {
+	now := time.Now()
+	return !c.fresh(e, SectionForecast, now) && !c.fresh(e, SectionAstro, now)
+}
+
+// LookupAstro returns the newest forecast answering want whose astronomy
+// is fresh, whether or not the rest of it is, for showing sun and moon
+// data alone. It returns nil when there is none.
+func (c *Cache) LookupAstro(want Key) *forecast.Response {
+	entry := c.newest(want, func(e *Entry) bool { return c.fresh(e, SectionAstro, time.Now()) })
+	if entry == nil {
+		return nil
+	}
+	return narrow(entry.Data, want)
+}
+
+// Section describes one section of a cached forecast.
+type Section struct {
+	Name      string
+	FetchedAt time.Time
+	// Left is how long the section stays fresh; zero once it is stale.
+	Left time.Duration
+}
+
+// Sections returns the state of each section of e at now, or nil for an
+// entry that does not hold a forecast.
+func (c *Cache) Sections(e *Entry, now time.Time) []Section {
+	if e.Key == nil {
+		return nil
+	}
+
+	var sections []Section
+	for _, name := range []string{SectionCurrent, SectionForecast, SectionAstro} {
+		section := Section{Name: name, FetchedAt: e.FetchedAt(name)}
+		if c.fresh(e, name, now) {
+			section.Left = section.FetchedAt.Add(c.sectionTTL(name)).Sub(now)
+		}
+		sections = append(sections, section)
+	}
+	return sections
+}
+
+// StoreCurrent refreshes the current conditions of the newest valid entry
+// answering key from data, leaving its forecast and when it was cached
+// alone. It does nothing if there is no such entry.
+func (c *Cache) StoreCurrent(key Key, data *forecast.Response) error {
+	if data == nil {
+		return errors.New("cannot cache nil weather data")
+	}
+
+	return c.update(func() {
+		entry := c.newest(key, c.valid)
+		if entry == nil {
+			return
+		}
+
+		updated := *entry.Data
+		updated.Current = data.Current
+		updated.Location.LocalTime = data.Location.LocalTime
+		if !key.AQI {
+			// data was fetched without air quality; keep the entry's.
+			updated.Current.AirQuality = entry.Data.Current.AirQuality
+		}
+
+		entry.Data = &updated
+		if entry.Fetched == nil {
+			entry.Fetched = make(map[string]time.Time)
+		}
+		entry.Fetched[SectionCurrent] = time.Now().UTC()
+	})
+}
+
+// dayEnded reports whether the first day of data's forecast is over at the
+// location.
+func dayEnded(data *forecast.Response, now time.Time) bool {
+	if data == nil || len(data.Days) == 0 {
+		return false
+	}
+	zone, ok := data.Location.Zone()
+	if !ok {
+		return false
+	}
+	return now.In(zone).Format("2006-01-02") > data.Days[0].Date
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+func TestCacheSectionTTLs(t *testing.T) {
+	today := time.Now().UTC().Format("2006-01-02")
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02")
+
+	tests := []struct {
+		name        string
+		age         time.Duration
+		firstDay    string
+		wantHit     bool
+		wantCurrent bool
+		wantAstro   bool
+	}{
+		{"all fresh", 5 * time.Minute, today, true, true, true},
+		{"current expired", 20 * time.Minute, today, true, false, true},
+		{"forecast expired", 2 * time.Hour, today, false, false, true},
+		{"astro expired", 25 * time.Hour, today, false, false, false},
+		{"first day over", 5 * time.Minute, yesterday, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Cache{
+				Entries:  make(map[string]*Entry),
+				path:     filepath.Join(t.TempDir(), "cache.json"),
+				ttl:      time.Hour,
+				sections: SectionTTLs{Current: 15 * time.Minute, Forecast: time.Hour, Astro: 24 * time.Hour},
+			}
+			key := Key{Location: "london", Provider: "weatherapi", Days: 1}
+			c.Entries[key.String()] = &Entry{
+				Location: "London",
+				Data: &forecast.Response{
+					Location: forecast.Location{Name: "London", TimeZone: "UTC"},
+					Days:     []forecast.Day{{Date: tt.firstDay}},
+				},
+				CachedAt: time.Now().Add(-tt.age),
+				Key:      &key,
+			}
+
+			data, current := c.Lookup(key)
+			if (data != nil) != tt.wantHit || current != tt.wantCurrent {
+				t.Errorf("Lookup() = %v, %v; want hit %v, current %v", data != nil, current, tt.wantHit, tt.wantCurrent)
+			}
+			if astro := c.LookupAstro(key); (astro != nil) != tt.wantAstro {
+				t.Errorf("LookupAstro() hit = %v, want %v", astro != nil, tt.wantAstro)
+			}
+			if expired := c.Expired(c.Entries[key.String()]); expired == tt.wantAstro {
+				t.Errorf("Expired() = %v, want %v", expired, !tt.wantAstro)
+			}
+		})
+	}
+}
+
+func TestCacheStoreCurrent(t *testing.T) {
+	c := &Cache{
+		Entries:  make(map[string]*Entry),
+		path:     filepath.Join(t.TempDir(), "cache.json"),
+		ttl:      time.Hour,
+		sections: SectionTTLs{Current: 15 * time.Minute, Forecast: time.Hour, Astro: time.Hour},
+	}
+
+	key := Key{Location: "london", Provider: "weatherapi", Days: 3, AQI: true}
+	if err := c.Store(key, "London", &forecast.Response{
+		Current: forecast.Current{TempC: 10},
+		Days:    make([]forecast.Day, 3),
+	}); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	cachedAt := time.Now().Add(-30 * time.Minute)
+	c.Entries[key.String()].CachedAt = cachedAt
+	if err := c.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, current := c.Lookup(key); current {
+		t.Fatal("Lookup() reports fresh current conditions 30 minutes after caching")
+	}
+
+	if err := c.StoreCurrent(key, &forecast.Response{Current: forecast.Current{TempC: 14}}); err != nil {
+		t.Fatalf("StoreCurrent() error = %v", err)
+	}
+
+	data, current := c.Lookup(key)
+	if data == nil || !current {
+		t.Fatalf("Lookup() after StoreCurrent() = %v, %v; want fresh current conditions", data, current)
+	}
+	if data.Current.TempC != 14 || len(data.Days) != 3 {
+		t.Errorf("data = %v°C with %d days, want the new current conditions and the cached forecast", data.Current.TempC, len(data.Days))
+	}
+	if entry := c.Entries[key.String()]; !entry.CachedAt.Equal(cachedAt) {
+		t.Errorf("CachedAt = %v, want the forecast's age kept at %v", entry.CachedAt, cachedAt)
+	}
+}
+
+func TestCacheSections(t *testing.T) {
+	c := &Cache{
+		Entries:  make(map[string]*Entry),
+		ttl:      24 * time.Hour,
+		sections: SectionTTLs{Current: 15 * time.Minute, Forecast: time.Hour, Astro: 24 * time.Hour},
+	}
+	now := time.Now()
+	key := Key{Location: "london", Provider: "weatherapi", Days: 1}
+	entry := &Entry{
+		Location: "London",
+		Data:     &forecast.Response{},
+		CachedAt: now.Add(-90 * time.Minute),
+		Key:      &key,
+		Fetched:  map[string]time.Time{SectionCurrent: now.Add(-5 * time.Minute)},
+	}
+
+	want := []Section{
+		{SectionCurrent, now.Add(-5 * time.Minute), 10 * time.Minute},
+		{SectionForecast, entry.CachedAt, 0},
+		{SectionAstro, entry.CachedAt, 22*time.Hour + 30*time.Minute},
+	}
+	got := c.Sections(entry, now)
+	if len(got) != len(want) {
+		t.Fatalf("Sections() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Sections()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if c.Sections(&Entry{Location: "London|2026-09-01", CachedAt: now}, now) != nil {
+		t.Error("Sections() of an entry without a forecast key should be nil")
+	}
+}
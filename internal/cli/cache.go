@@ -0,0 +1,424 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/cache"
+	"github.com/jtotty/weather-cli/internal/config"
+)
+
+const cacheUsage = `usage: weather-cli cache <stats|list|path|clear|prune> [--format json]
+       weather-cli cache show LOCATION [--format json]`
+
+// forecastNamespace names the main forecast cache in listings.
+const forecastNamespace = "forecast"
+
+// namespace is one cache file and the name it is listed under.
+type namespace struct {
+	name  string
+	cache *cache.Cache
+}
+
+// RunCache handles "weather-cli cache <action>" for the forecast, history
+// and remembered places caches.
+func RunCache(args []string, w io.Writer) error {
+	// A broken config file should not stop the cache being inspected or
+	// cleared, so it falls back to the default TTLs.
+	var ttls cache.SectionTTLs
+	if cfg, err := config.Load(); err == nil {
+		ttls = cfg.CacheTTLs()
+	}
+
+	forecast, err := cache.NewForecast(ttls)
+	if err != nil {
+		return err
+	}
+	history, err := cache.NewNamespace(cache.HistoryNamespace, cache.NoExpiry)
+	if err != nil {
+		return err
+	}
+	places, err := cache.NewNamespace(cache.PlacesNamespace, cache.NoExpiry)
+	if err != nil {
+		return err
+	}
+
+	spaces := []namespace{
+		{forecastNamespace, forecast},
+		{cache.HistoryNamespace, history},
+		{cache.PlacesNamespace, places},
+	}
+	return runCache(args, w, spaces, time.Now())
+}
+
+func runCache(args []string, w io.Writer, spaces []namespace, now time.Time) error {
+	args, asJSON, err := cacheFormat(args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New(cacheUsage)
+	}
+
+	action, args := args[0], args[1:]
+	if action == "show" {
+		if len(args) == 0 {
+			return errors.New(cacheUsage)
+		}
+		return cacheShow(w, spaces, strings.Join(args, " "), now, asJSON)
+	}
+
+	actions := map[string]func() error{
+		"stats": func() error { return cacheStats(w, spaces, asJSON) },
+		"list":  func() error { return cacheList(w, spaces, now, asJSON) },
+		"path":  func() error { return cachePaths(w, spaces, asJSON) },
+		"clear": func() error { return cacheRemove(w, spaces, clearCache, asJSON) },
+		"prune": func() error { return cacheRemove(w, spaces, pruneCache, asJSON) },
+	}
+	run, ok := actions[action]
+	switch {
+	case !ok:
+		return fmt.Errorf("unknown cache action %q (available: %s)", action, strings.Join(subcommandArgs["cache"], ", "))
+	case len(args) != 0:
+		return errors.New(cacheUsage)
+	}
+	return run()
+}
+
+// cacheFormat removes "--format FORMAT" from args and reports whether JSON
+// was asked for.
+func cacheFormat(args []string) ([]string, bool, error) {
+	var rest []string
+	format := "text"
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		if name != "--format" && name != "-f" {
+			rest = append(rest, args[i])
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, false, fmt.Errorf("flag %s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		format = strings.ToLower(strings.TrimSpace(value))
+	}
+
+	if format != "text" && format != "json" {
+		return nil, false, fmt.Errorf("invalid value %q for --format: cache supports text and json", format)
+	}
+	return rest, format == "json", nil
+}
+
+// cacheEntry describes one cache entry for "list" and "show".
+type cacheEntry struct {
+	Namespace string    `json:"namespace"`
+	Key       string    `json:"key"`
+	Location  string    `json:"location"`
+	Place     string    `json:"place,omitempty"`
+	Provider  string    `json:"provider,omitempty"`
+	CachedAt  time.Time `json:"cached_at"`
+	AgeSecs   int64     `json:"age_seconds"`
+	// TTLSecs is the time left before the entry expires; nil when it never
+	// does and 0 once it has.
+	TTLSecs   *int64     `json:"ttl_remaining_seconds"`
+	Expired   bool       `json:"expired"`
+	SizeBytes int        `json:"size_bytes"`
+	Request   *cache.Key `json:"request,omitempty"`
+	// Sections describes each section of a forecast entry, which expire
+	// separately.
+	Sections []cacheSection `json:"sections,omitempty"`
+}
+
+// cacheSection describes one section of a cached forecast.
+type cacheSection struct {
+	Name      string    `json:"name"`
+	FetchedAt time.Time `json:"fetched_at"`
+	AgeSecs   int64     `json:"age_seconds"`
+	TTLSecs   int64     `json:"ttl_remaining_seconds"`
+	Expired   bool      `json:"expired"`
+}
+
+// age formats how long ago the section was fetched.
+func (s cacheSection) age() string {
+	return formatDuration(time.Duration(s.AgeSecs) * time.Second)
+}
+
+// ttl formats the time left before the section goes stale.
+func (s cacheSection) ttl() string {
+	if s.Expired {
+		return "expired"
+	}
+	return formatDuration(time.Duration(s.TTLSecs) * time.Second)
+}
+
+func newCacheEntry(ns namespace, e *cache.Entry, now time.Time) cacheEntry {
+	info := cacheEntry{
+		Namespace: ns.name,
+		Key:       strings.ToLower(e.Location),
+		Location:  e.Location,
+		CachedAt:  e.CachedAt,
+		AgeSecs:   int64(now.Sub(e.CachedAt).Seconds()),
+		SizeBytes: e.Size(),
+		Request:   e.Key,
+	}
+	if e.Key != nil {
+		info.Key = e.Key.String()
+	}
+	if e.Data != nil {
+		info.Place = e.Data.Location.Name
+		if e.Data.Location.Country != "" {
+			info.Place += ", " + e.Data.Location.Country
+		}
+		info.Provider = e.Data.Provider
+	}
+
+	if ttl := ns.cache.TTL(); ttl != cache.NoExpiry {
+		left := max(e.CachedAt.Add(ttl).Sub(now), 0)
+		if sections := ns.cache.Sections(e, now); sections != nil {
+			// A forecast entry lasts as long as its longest-lived section
+			// other than the current conditions, which alone can be
+			// refreshed without a full fetch.
+			left = 0
+			for _, section := range sections {
+				info.Sections = append(info.Sections, cacheSection{
+					Name:      section.Name,
+					FetchedAt: section.FetchedAt,
+					AgeSecs:   int64(now.Sub(section.FetchedAt).Seconds()),
+					TTLSecs:   int64(section.Left.Seconds()),
+					Expired:   section.Left == 0,
+				})
+				if section.Name != cache.SectionCurrent {
+					left = max(left, section.Left)
+				}
+			}
+		}
+		secs := int64(left.Seconds())
+		info.TTLSecs = &secs
+		info.Expired = left == 0
+	}
+	return info
+}
+
+// age formats the entry's age, e.g. "3h 5m".
+func (e cacheEntry) age() string {
+	return formatDuration(time.Duration(e.AgeSecs) * time.Second)
+}
+
+// ttl formats the time left before the entry expires.
+func (e cacheEntry) ttl() string {
+	switch {
+	case e.TTLSecs == nil:
+		return "never expires"
+	case e.Expired:
+		return "expired"
+	default:
+		return formatDuration(time.Duration(*e.TTLSecs) * time.Second)
+	}
+}
+
+func cacheList(w io.Writer, spaces []namespace, now time.Time, asJSON bool) error {
+	entries := []cacheEntry{}
+	for _, ns := range spaces {
+		for _, e := range ns.cache.List() {
+			entries = append(entries, newCacheEntry(ns, e, now))
+		}
+	}
+
+	if asJSON {
+		return writeJSON(w, entries)
+	}
+	if len(entries) == 0 {
+		_, err := fmt.Fprintln(w, "The cache is empty.")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tLOCATION\tPROVIDER\tAGE\tTTL\tSIZE")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Namespace, e.Location, e.Provider, e.age(), e.ttl(), formatSize(e.SizeBytes))
+		for _, section := range e.Sections {
+			fmt.Fprintf(tw, "\t  %s\t\t%s\t%s\t\n", section.Name, section.age(), section.ttl())
+		}
+	}
+	return tw.Flush()
+}
+
+func cacheShow(w io.Writer, spaces []namespace, location string, now time.Time, asJSON bool) error {
+	var entries []cacheEntry
+	for _, ns := range spaces {
+		for _, e := range ns.cache.Find(location) {
+			entries = append(entries, newCacheEntry(ns, e, now))
+		}
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("nothing cached for %q", location)
+	}
+
+	if asJSON {
+		return writeJSON(w, entries)
+	}
+
+	for i, e := range entries {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s (%s)\n", e.Location, e.Namespace)
+		fmt.Fprintf(w, "  %-10s %s\n", "Key:", e.Key)
+		if e.Place != "" {
+			fmt.Fprintf(w, "  %-10s %s\n", "Place:", e.Place)
+		}
+		if e.Provider != "" {
+			fmt.Fprintf(w, "  %-10s %s\n", "Provider:", e.Provider)
+		}
+		fmt.Fprintf(w, "  %-10s %s (%s ago)\n", "Cached:", e.CachedAt.Local().Format("2006-01-02 15:04"), e.age())
+		fmt.Fprintf(w, "  %-10s %s\n", "TTL:", e.ttl())
+		for i, section := range e.Sections {
+			label := ""
+			if i == 0 {
+				label = "Sections:"
+			}
+			fmt.Fprintf(w, "  %-10s %-9s %s old, %s\n", label, section.Name, section.age(), sectionLeft(section))
+		}
+		if _, err := fmt.Fprintf(w, "  %-10s %s\n", "Size:", formatSize(e.SizeBytes)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sectionLeft describes how long section stays fresh.
+func sectionLeft(section cacheSection) string {
+	if section.Expired {
+		return "expired"
+	}
+	return section.ttl() + " left"
+}
+
+// namespaceStats describes one cache file for "stats".
+type namespaceStats struct {
+	Namespace string `json:"namespace"`
+	Path      string `json:"path"`
+	Entries   int    `json:"entries"`
+	Valid     int    `json:"valid"`
+	Expired   int    `json:"expired"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+func cacheStats(w io.Writer, spaces []namespace, asJSON bool) error {
+	stats := make([]namespaceStats, len(spaces))
+	for i, ns := range spaces {
+		s := namespaceStats{Namespace: ns.name, Path: ns.cache.Path()}
+		s.Entries, s.Valid, s.Expired = ns.cache.Stats()
+		if fi, err := os.Stat(ns.cache.Path()); err == nil {
+			s.SizeBytes = fi.Size()
+		}
+		stats[i] = s
+	}
+
+	if asJSON {
+		return writeJSON(w, stats)
+	}
+	for _, s := range stats {
+		if _, err := fmt.Fprintf(w, "%s: %d entries (%d valid, %d expired), %s\n",
+			s.Namespace, s.Entries, s.Valid, s.Expired, formatSize(int(s.SizeBytes))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func cachePaths(w io.Writer, spaces []namespace, asJSON bool) error {
+	if asJSON {
+		paths := make(map[string]string, len(spaces))
+		for _, ns := range spaces {
+			paths[ns.name] = ns.cache.Path()
+		}
+		return writeJSON(w, paths)
+	}
+
+	// The forecast cache comes first, so scripts reading one line get it.
+	for _, ns := range spaces {
+		if _, err := fmt.Fprintln(w, ns.cache.Path()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remover removes entries from a cache, returning how many went.
+type remover func(c *cache.Cache) (int, error)
+
+func clearCache(c *cache.Cache) (int, error) {
+	total, _, _ := c.Stats()
+	return total, c.Clear()
+}
+
+func pruneCache(c *cache.Cache) (int, error) {
+	return c.Prune()
+}
+
+// cacheRemove removes entries from every namespace and reports how many
+// went.
+func cacheRemove(w io.Writer, spaces []namespace, remove remover, asJSON bool) error {
+	removed := 0
+	for _, ns := range spaces {
+		n, err := remove(ns.cache)
+		if err != nil {
+			return fmt.Errorf("%s cache: %w", ns.name, err)
+		}
+		removed += n
+	}
+
+	if asJSON {
+		return writeJSON(w, map[string]int{"removed": removed})
+	}
+	_, err := fmt.Fprintf(w, "Removed %d %s.\n", removed, plural(removed, "entry", "entries"))
+	return err
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// formatDuration formats d to the minute, e.g. "45m", "3h 5m" or "2d 4h".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// formatSize formats a byte count, e.g. "512 B" or "45.2 KB".
+func formatSize(bytes int) string {
+	switch {
+	case bytes < 1024:
+		return fmt.Sprintf("%d B", bytes)
+	case bytes < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(bytes)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(bytes)/(1024*1024))
+	}
+}
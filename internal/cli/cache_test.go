@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/cache"
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+// seedCache writes a forecast cache with a fresh London entry and an
+// expired Paris one, and a history cache with one London day. The config
+// file gives forecasts a 30 minute TTL; astronomy keeps its 24 hour one.
+func seedCache(t *testing.T) {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("cache:\n  forecast_ttl: 30m\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("WEATHER_CONFIG", configPath)
+
+	dir, err := cache.Dir()
+	if err != nil {
+		t.Fatalf("cache.Dir() error = %v", err)
+	}
+
+	london := cache.Key{Location: "london", Provider: "weatherapi", Days: 3}
+	paris := cache.Key{Location: "paris", Provider: "open-meteo", Days: 7}
+	forecasts := map[string]*cache.Entry{
+		london.String(): {
+			Location: "London",
+			Data:     &forecast.Response{Location: forecast.Location{Name: "London", Country: "UK"}, Provider: "weatherapi"},
+			CachedAt: time.Now().Add(-10 * time.Minute),
+			Key:      &london,
+			Fetched:  map[string]time.Time{cache.SectionCurrent: time.Now().Add(-5 * time.Minute)},
+		},
+		paris.String(): {
+			Location: "Paris",
+			Data:     &forecast.Response{Location: forecast.Location{Name: "Paris"}, Provider: "open-meteo"},
+			CachedAt: time.Now().Add(-26 * time.Hour),
+			Key:      &paris,
+		},
+	}
+	history := map[string]*cache.Entry{
+		"london|2026-09-01": {
+			Location: "London|2026-09-01",
+			Data:     &forecast.Response{Location: forecast.Location{Name: "London"}, Provider: "weatherapi"},
+			CachedAt: time.Now().Add(-48 * time.Hour),
+		},
+	}
+
+	for name, entries := range map[string]map[string]*cache.Entry{"cache.json": forecasts, "history.json": history} {
+		data, err := json.Marshal(&cache.Cache{Version: cache.FormatVersion, Entries: entries})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func runCacheCommand(args ...string) (string, error) {
+	var buf bytes.Buffer
+	err := RunCache(args, &buf)
+	return buf.String(), err
+}
+
+func TestRunCache_List(t *testing.T) {
+	seedCache(t)
+
+	out, err := runCacheCommand("list")
+	if err != nil {
+		t.Fatalf("cache list error = %v", err)
+	}
+	for _, want := range []string{"NAMESPACE", "forecast   London ", "23h 50m", "  current ", "  forecast ", "20m", "expired", "London|2026-09-01", "never expires"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("cache list = %q, want it to contain %q", out, want)
+		}
+	}
+
+	out, err = runCacheCommand("list", "--format", "json")
+	if err != nil {
+		t.Fatalf("cache list --format json error = %v", err)
+	}
+	var entries []cacheEntry
+	if err := json.Unmarshal([]byte(out), &entries); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+
+	london := entries[0]
+	if london.Location != "London" || london.Provider != "weatherapi" || london.Expired || london.SizeBytes == 0 {
+		t.Errorf("London entry = %+v", london)
+	}
+	if london.TTLSecs == nil || (*london.TTLSecs+30)/60 != 23*60+50 || london.AgeSecs < 10*60 {
+		t.Errorf("London age = %ds, ttl = %v; want about 10m old with its astronomy fresh for 23h 50m", london.AgeSecs, london.TTLSecs)
+	}
+	var left []string
+	for _, section := range london.Sections {
+		left = append(left, fmt.Sprintf("%s %dm old, %dm left", section.Name, section.AgeSecs/60, (section.TTLSecs+30)/60))
+	}
+	if want := "current 5m old, 10m left|forecast 10m old, 20m left|astro 10m old, 1430m left"; strings.Join(left, "|") != want {
+		t.Errorf("London sections = %v, want %v", left, want)
+	}
+	if paris := entries[1]; !paris.Expired || *paris.TTLSecs != 0 {
+		t.Errorf("Paris entry = %+v, want expired", paris)
+	}
+	if history := entries[2]; history.Namespace != cache.HistoryNamespace || history.TTLSecs != nil {
+		t.Errorf("history entry = %+v, want one that never expires", history)
+	}
+}
+
+func TestRunCache_Show(t *testing.T) {
+	seedCache(t)
+
+	out, err := runCacheCommand("show", "london")
+	if err != nil {
+		t.Fatalf("cache show error = %v", err)
+	}
+	for _, want := range []string{"London (forecast)", "london|p=weatherapi|days=3|aqi=0|alerts=0", "London, UK", "current   5m old, 10m left", "astro     10m old, 23h 50m left", "London|2026-09-01 (history)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("cache show = %q, want it to contain %q", out, want)
+		}
+	}
+
+	if _, err := runCacheCommand("show", "Atlantis"); err == nil || !strings.Contains(err.Error(), "nothing cached") {
+		t.Errorf("cache show Atlantis error = %v, want nothing cached", err)
+	}
+}
+
+func TestRunCache_StatsPruneClear(t *testing.T) {
+	seedCache(t)
+
+	out, err := runCacheCommand("stats")
+	if err != nil || !strings.Contains(out, "forecast: 2 entries (1 valid, 1 expired)") || !strings.Contains(out, "history: 1 entries") {
+		t.Errorf("cache stats = %q, %v", out, err)
+	}
+
+	if out, err := runCacheCommand("prune"); err != nil || out != "Removed 1 entry.\n" {
+		t.Errorf("cache prune = %q, %v; want the expired entry removed", out, err)
+	}
+	if out, err := runCacheCommand("clear", "--format=json"); err != nil || !strings.Contains(out, `"removed": 2`) {
+		t.Errorf("cache clear = %q, %v; want the two remaining entries removed", out, err)
+	}
+	if out, _ := runCacheCommand("list"); out != "The cache is empty.\n" {
+		t.Errorf("cache list after clear = %q", out)
+	}
+}
+
+func TestRunCache_Usage(t *testing.T) {
+	seedCache(t)
+
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{nil, "usage: weather-cli cache"},
+		{[]string{"show"}, "usage: weather-cli cache"},
+		{[]string{"stats", "extra"}, "usage: weather-cli cache"},
+		{[]string{"purge"}, `unknown cache action "purge"`},
+		{[]string{"list", "--format", "yaml"}, "cache supports text and json"},
+	}
+
+	for _, tt := range tests {
+		if _, err := runCacheCommand(tt.args...); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("cache %v error = %v, want %q", tt.args, err, tt.wantErr)
+		}
+	}
+}
@@ -5,6 +5,10 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/provider"
+	"github.com/jtotty/weather-cli/internal/redact"
 )
 
 type CommandType int
@@ -15,67 +19,225 @@ const (
 	CommandVersion
 	CommandSetup
 	CommandDeleteKey
+	CommandConfig
+	CommandCache
+	CommandLocations
+	CommandCompletion
+	CommandComplete
+	CommandCompare
+	CommandWatch
+	CommandTUI
+	CommandSearch
+	CommandHistory
 )
 
 type Command struct {
 	Type     CommandType
 	Location string
-}
+	// Locations holds the locations given to "compare".
+	Locations []string
+	Provider  string
+	Format    string
+	Template  string
+	Units     string
+	Days      int
+	Sections  string
+	Color     string
+	// Interval is the refresh interval for "watch"; zero means the default.
+	Interval time.Duration
+	// Date and To are the first and last days for "history"; To is zero
+	// for a single day.
+	Date time.Time
+	To   time.Time
 
-func Parse(args []string) Command {
-	if len(args) < 2 {
-		return Command{Type: CommandWeather}
-	}
+	// Details adds pressure, visibility, gusts, cloud cover, UV and dew
+	// point to the text display.
+	Details bool
+	// ViewerTime shows hourly times on the viewer's clock as well as the
+	// location's.
+	ViewerTime bool
+	// Offline serves only cached data; Refresh skips the cache lookup.
+	Offline bool
+	Refresh bool
 
-	arg := args[1]
-
-	switch arg {
-	case "--help", "-h":
-		return Command{Type: CommandHelp}
-	case "--version", "-v":
-		return Command{Type: CommandVersion}
-	case "--setup":
-		return Command{Type: CommandSetup}
-	case "--delete-key":
-		return Command{Type: CommandDeleteKey}
-	default:
-		// Treat as location if not a flag
-		if strings.HasPrefix(arg, "-") {
-			return Command{Type: CommandHelp}
-		}
-		return Command{Type: CommandWeather, Location: arg}
-	}
+	// AQI and Alerts are nil unless set on the command line.
+	AQI    *bool
+	Alerts *bool
+
+	// Args holds the arguments following a subcommand such as "config".
+	Args []string
 }
 
 func PrintHelp(version string) {
 	fmt.Printf(`weather-cli %s
 
 USAGE:
-    weather-cli [OPTIONS] [LOCATION]
+    weather-cli [COMMAND] [OPTIONS] [LOCATION]
+
+COMMANDS:
+    (none)        Full forecast
+    now           Current conditions
+    hourly        Hourly forecast
+    daily         Daily forecast
+    alerts        Weather alerts
+    astro         Sun and moon: twilight times, day length, moon phase
+    compare       Compare several locations side by side (quote multi-word
+                  names: compare London Paris "New York")
+    watch         Live dashboard that refreshes in place (Ctrl+C to quit)
+    tui           Interactive full-screen view: tabs, scrolling through
+                  every forecast hour and a saved-location switcher
+    search        List the places matching a name, with region, country
+                  and coordinates
+    history       Observed weather for past days (--date, optionally --to)
+    config        Manage the config file: get, set, unset, path, edit
+    cache         Inspect and manage the forecast, history and places caches:
+                  stats, list, show LOCATION, clear, prune (drop expired
+                  entries), path; add --format json for JSON
+    key           Manage the weatherapi.com key: set, delete
+    locations     Manage saved locations: add, list, remove, rename
+    completion    Print a shell completion script: bash, zsh, fish, powershell
 
 ARGUMENTS:
     [LOCATION]    Location for weather lookup (city name, zip code, coordinates)
-                  If omitted, uses your current location via IP geolocation
+                  or @alias for a saved location. If omitted, uses your
+                  current location via IP geolocation. When a name matches
+                  several places you are asked which one you mean; the
+                  choice is remembered until --refresh or cache clear.
+                  Without a terminal the best match is used unremembered.
+                  Use -- before a location that starts with - or matches a
+                  command name.
 
 OPTIONS:
-    -h, --help        Show this help message
-    -v, --version     Show version information
-    --setup           Configure your Weather API key (stored in OS keyring)
-    --delete-key      Remove stored API key from OS keyring
+    -h, --help            Show this help message
+    -v, --version         Show version information
+    -p, --provider LIST   Comma-separated providers to try in order
+                          (default: weatherapi)
+    -f, --format FORMAT   Output format: text, json, yaml, csv, ndjson
+                          (default: text)
+    -t, --template FILE   Render through a Go text/template file
+    -u, --units SYSTEM    Units: metric, imperial, uk, si (default: uk)
+                          Override single quantities with QUANTITY=UNIT,
+                          e.g. --units imperial,wind=kph
+    -d, --days N          Number of forecast days (default: 7)
+    -s, --sections LIST   Sections to show: time, current, hourly, daily,
+                          twilight, astro, alerts (default: all but astro)
+    --color WHEN          Color output: auto, always, never (default: auto)
+    --aqi[=BOOL]          Include air quality (default: true)
+    --alerts[=BOOL]       Include weather alerts (default: true)
+    --details             Show pressure, visibility, gusts, cloud cover, UV
+                          and dew point, with extra table columns
+    --viewer-time         Show hourly times in your time zone alongside the
+                          location's
+    --offline             Use only cached data, however old; never contact
+                          a provider
+    --refresh             Ignore cached data and fetch a fresh forecast
+    -i, --interval DUR    Refresh interval for watch, e.g. 30s, 10m
+                          (default: 1m; data is re-fetched once the
+                          cache expires)
+    --date DATE           First day for history, e.g. 2026-09-01
+    --to DATE             Last day for history (default: --date; at most
+                          31 days; free plans cover the last 7 days)
 
+UNITS:
+    temp      c, f, k
+    wind      mph, kph, ms, kn
+    pressure  mb, hpa, inhg
+    precip    mm, in
+    distance  km, mi
+
+PROVIDERS:
+%s
 EXAMPLES:
     weather-cli                     # Weather for current location
     weather-cli London              # Weather for London
-    weather-cli "New York"          # Weather for New York (use quotes for spaces)
+    weather-cli New York            # Weather for New York
+    weather-cli hourly London -d 2  # Hourly forecast for London
     weather-cli 10001               # Weather for ZIP code 10001
     weather-cli 51.5,-0.1           # Weather for coordinates
+    weather-cli -33.9,151.2         # Negative coordinates need no quoting
+    weather-cli Oslo --provider met-norway
+    weather-cli --provider weatherapi,open-meteo   # Fall back to Open-Meteo
+    weather-cli London --format json | jq .current
+    weather-cli Chicago --units imperial
+    weather-cli hourly Denver --details
+    weather-cli hourly Tokyo --viewer-time
+    weather-cli locations add home 51.5,-0.1 --name Home --units metric
+    weather-cli hourly @home
+    weather-cli compare London Paris "New York" --format json
+    weather-cli watch @home --interval 5m
+    weather-cli tui London
+    weather-cli astro Tromso        # Twilight and moon phase for Tromsø
+    weather-cli search Paris        # Which Paris? Lists every match
+    weather-cli history London --date 2026-09-01 --to 2026-09-07
+    weather-cli --template ~/.config/weather-cli/status.tmpl
+    weather-cli London --offline    # Last cached forecast, even when expired
+    weather-cli cache show London   # What is cached for London, and for how long
+
+TEMPLATES:
+    Templates receive the same data as --format json. Helper functions:
+    colorTemp, icon, aqiIcon, namedIcon, fahrenheit, kph, inches, round,
+    formatTime, formatDate, upper, lower, title, join, truncate, first.
+
+    {{icon .Current.Condition}} {{colorTemp .Current.TempC}} {{.Location.Name}}
+
+CONFIGURATION:
+    Defaults are read from config.yaml in your user config directory
+    (run 'weather-cli config path'). Flags override WEATHER_* environment
+    variables, which override the file.
+
+    weather-cli config set location "New York"
+    weather-cli config set units imperial
+    weather-cli config set units.wind kph
+    weather-cli config unset days
+    weather-cli config get            # show all keys
+
+    Keys: location, days, units, units.temp, units.wind, units.pressure,
+          units.precip, units.distance, sections, provider, colors, aqi,
+          alerts, retry.attempts, retry.delay, retry.max_delay,
+          cache.current_ttl, cache.forecast_ttl, cache.astro_ttl
+
+    Rate-limited (429) and server (5xx) responses are retried with
+    jittered exponential backoff, honoring Retry-After.
+
+    Cached current conditions stay fresh for 15m, the forecast for 2h and
+    sunrise, sunset and moon data for 24h. When only the current conditions
+    have expired, weatherapi.com is asked for just those. The astro and
+    twilight sections shown on their own use the cached sun and moon data
+    until it expires, even once the rest of the forecast has; 'cache list'
+    shows the age of each part.
+
+COMPLETION:
+    bash:        source <(weather-cli completion bash)
+    zsh:         source <(weather-cli completion zsh)
+    fish:        weather-cli completion fish > ~/.config/fish/completions/weather-cli.fish
+    PowerShell:  weather-cli completion powershell | Out-String | Invoke-Expression
+
+    Locations you have looked up before are offered as completions.
+
+EXIT STATUS:
+    0    Success
+    1    General error
+    2    Invalid command line
+    3    Location not found
+    4    API key rejected or disabled
+    5    API key quota exceeded
+    130  Interrupted
 
 API KEY:
     Get a free API key from https://www.weatherapi.com/
-    Run 'weather-cli --setup' to configure your API key.
+    Run 'weather-cli key set' to configure your API key.
 
     Alternatively, set the WEATHER_API_KEY environment variable.
-`, version)
+    Only the weatherapi provider needs a key.
+`, version, providerList())
+}
+
+func providerList() string {
+	var b strings.Builder
+	for _, name := range provider.Names() {
+		fmt.Fprintf(&b, "    %-12s  %s\n", name, provider.Description(name))
+	}
+	return b.String()
 }
 
 func PrintVersion(version string) {
@@ -83,6 +245,12 @@ func PrintVersion(version string) {
 }
 
 func ExitWithError(err error) {
-	fmt.Fprintf(os.Stderr, "%v\n", err)
+	fmt.Fprintln(os.Stderr, redact.String(err.Error()))
 	os.Exit(1)
 }
+
+// ExitWithUsageError reports a bad command line and exits with status 2.
+func ExitWithUsageError(err error) {
+	fmt.Fprintf(os.Stderr, "weather-cli: %v\nRun 'weather-cli --help' for usage.\n", err)
+	os.Exit(2)
+}
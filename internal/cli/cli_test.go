@@ -1,6 +1,12 @@
 package cli
 
-import "testing"
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestParse(t *testing.T) {
 	tests := []struct {
@@ -8,6 +14,10 @@ func TestParse(t *testing.T) {
 		args         []string
 		wantType     CommandType
 		wantLocation string
+		wantProvider string
+		wantFormat   string
+		wantTemplate string
+		wantUnits    string
 	}{
 		{
 			name:         "no arguments returns weather command",
@@ -70,20 +80,123 @@ func TestParse(t *testing.T) {
 			wantType: CommandDeleteKey,
 		},
 		{
-			name:     "unknown flag shows help",
-			args:     []string{"weather-cli", "--unknown"},
-			wantType: CommandHelp,
+			name:         "provider flag",
+			args:         []string{"weather-cli", "--provider", "open-meteo"},
+			wantType:     CommandWeather,
+			wantProvider: "open-meteo",
+		},
+		{
+			name:         "location then provider flag",
+			args:         []string{"weather-cli", "Oslo", "--provider=met-norway"},
+			wantType:     CommandWeather,
+			wantLocation: "Oslo",
+			wantProvider: "met-norway",
+		},
+		{
+			name:         "format flag",
+			args:         []string{"weather-cli", "London", "--format", "json"},
+			wantType:     CommandWeather,
+			wantLocation: "London",
+			wantFormat:   "json",
+		},
+		{
+			name:         "format and provider flags",
+			args:         []string{"weather-cli", "--format=csv", "--provider", "nws", "10001"},
+			wantType:     CommandWeather,
+			wantLocation: "10001",
+			wantProvider: "nws",
+			wantFormat:   "csv",
+		},
+		{
+			name:         "template flag",
+			args:         []string{"weather-cli", "--template", "status.tmpl"},
+			wantType:     CommandWeather,
+			wantTemplate: "status.tmpl",
+		},
+		{
+			name:         "units flag",
+			args:         []string{"weather-cli", "Chicago", "--units=imperial,wind=kph"},
+			wantType:     CommandWeather,
+			wantLocation: "Chicago",
+			wantUnits:    "imperial,wind=kph",
+		},
+		{
+			name:         "config subcommand",
+			args:         []string{"weather-cli", "config", "set", "days", "3"},
+			wantType:     CommandConfig,
+			wantLocation: "",
+		},
+		{
+			name:         "unquoted multi-word location",
+			args:         []string{"weather-cli", "New", "York", "-f", "json"},
+			wantType:     CommandWeather,
+			wantLocation: "New York",
+			wantFormat:   "json",
+		},
+		{
+			name:         "negative coordinates are not flags",
+			args:         []string{"weather-cli", "-33.9,151.2"},
+			wantType:     CommandWeather,
+			wantLocation: "-33.9,151.2",
+		},
+		{
+			name:         "double dash ends flags",
+			args:         []string{"weather-cli", "-p", "nws", "--", "--help", "alerts"},
+			wantType:     CommandWeather,
+			wantLocation: "--help alerts",
+			wantProvider: "nws",
+		},
+		{
+			name:         "subcommand with location and flags",
+			args:         []string{"weather-cli", "-u", "metric", "hourly", "London"},
+			wantType:     CommandWeather,
+			wantLocation: "London",
+			wantUnits:    "metric",
 		},
 		{
-			name:     "single dash unknown flag shows help",
-			args:     []string{"weather-cli", "-x"},
+			name:     "help after subcommand",
+			args:     []string{"weather-cli", "daily", "--help"},
 			wantType: CommandHelp,
 		},
+		{
+			name:     "key set",
+			args:     []string{"weather-cli", "key", "set"},
+			wantType: CommandSetup,
+		},
+		{
+			name:     "key delete",
+			args:     []string{"weather-cli", "key", "delete"},
+			wantType: CommandDeleteKey,
+		},
+		{
+			name:     "cache subcommand",
+			args:     []string{"weather-cli", "cache", "clear"},
+			wantType: CommandCache,
+		},
+		{
+			name:     "locations subcommand",
+			args:     []string{"weather-cli", "locations", "list"},
+			wantType: CommandLocations,
+		},
+		{
+			name:     "completion subcommand",
+			args:     []string{"weather-cli", "completion", "zsh"},
+			wantType: CommandCompletion,
+		},
+		{
+			name:         "display flags",
+			args:         []string{"weather-cli", "--days=3", "--sections", "current,daily", "--color", "never", "Leeds"},
+			wantType:     CommandWeather,
+			wantLocation: "Leeds",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := Parse(tt.args)
+			got, err := Parse(tt.args)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
 
 			if got.Type != tt.wantType {
 				t.Errorf("Parse() Type = %v, want %v", got.Type, tt.wantType)
@@ -92,6 +205,22 @@ func TestParse(t *testing.T) {
 			if got.Location != tt.wantLocation {
 				t.Errorf("Parse() Location = %q, want %q", got.Location, tt.wantLocation)
 			}
+
+			if got.Provider != tt.wantProvider {
+				t.Errorf("Parse() Provider = %q, want %q", got.Provider, tt.wantProvider)
+			}
+
+			if got.Format != tt.wantFormat {
+				t.Errorf("Parse() Format = %q, want %q", got.Format, tt.wantFormat)
+			}
+
+			if got.Template != tt.wantTemplate {
+				t.Errorf("Parse() Template = %q, want %q", got.Template, tt.wantTemplate)
+			}
+
+			if got.Units != tt.wantUnits {
+				t.Errorf("Parse() Units = %q, want %q", got.Units, tt.wantUnits)
+			}
 		})
 	}
 }
@@ -114,3 +243,202 @@ func TestCommandType_String(t *testing.T) {
 		t.Errorf("CommandDeleteKey = %d, want 4", CommandDeleteKey)
 	}
 }
+
+func TestParse_TypedFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want Command
+	}{
+		{
+			name: "long flags",
+			args: []string{"weather-cli", "--days=3", "--sections", "current,daily", "--color", "never"},
+			want: Command{Type: CommandWeather, Days: 3, Sections: "current,daily", Color: "never"},
+		},
+		{
+			name: "short value flag with attached value",
+			args: []string{"weather-cli", "-d3", "-sdaily"},
+			want: Command{Type: CommandWeather, Days: 3, Sections: "daily"},
+		},
+		{
+			name: "subcommand preselects sections",
+			args: []string{"weather-cli", "now", "Paris"},
+			want: Command{Type: CommandWeather, Location: "Paris", Sections: "time,current"},
+		},
+		{
+			name: "astro subcommand",
+			args: []string{"weather-cli", "astro", "Tromso"},
+			want: Command{Type: CommandWeather, Location: "Tromso", Sections: "astro"},
+		},
+		{
+			name: "explicit sections win over subcommand",
+			args: []string{"weather-cli", "daily", "-s", "twilight"},
+			want: Command{Type: CommandWeather, Sections: "twilight"},
+		},
+		{
+			name: "bool flags",
+			args: []string{"weather-cli", "--aqi=false", "--alerts"},
+			want: Command{Type: CommandWeather, AQI: boolPtr("false"), Alerts: boolPtr("true")},
+		},
+		{
+			name: "details",
+			args: []string{"weather-cli", "hourly", "--details", "Denver"},
+			want: Command{Type: CommandWeather, Location: "Denver", Sections: "hourly", Details: true},
+		},
+		{
+			name: "viewer time",
+			args: []string{"weather-cli", "hourly", "Tokyo", "--viewer-time"},
+			want: Command{Type: CommandWeather, Location: "Tokyo", Sections: "hourly", ViewerTime: true},
+		},
+		{
+			name: "offline",
+			args: []string{"weather-cli", "London", "--offline"},
+			want: Command{Type: CommandWeather, Location: "London", Offline: true},
+		},
+		{
+			name: "watch with interval",
+			args: []string{"weather-cli", "watch", "@home", "-i", "10m"},
+			want: Command{Type: CommandWatch, Location: "@home", Interval: 10 * time.Minute},
+		},
+		{
+			name: "search joins words",
+			args: []string{"weather-cli", "search", "San", "Jose", "-f", "json"},
+			want: Command{Type: CommandSearch, Location: "San Jose", Format: "json"},
+		},
+		{
+			name: "history range",
+			args: []string{"weather-cli", "history", "London", "--date", "2026-09-01", "--to=2026-09-07"},
+			want: Command{
+				Type:     CommandHistory,
+				Location: "London",
+				Date:     time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+				To:       time.Date(2026, 9, 7, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.args)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_CombinedShortFlags(t *testing.T) {
+	got, err := Parse([]string{"weather-cli", "-vh"})
+	if err != nil || got.Type != CommandVersion {
+		t.Errorf("Parse(-vh) = %+v, %v; want version", got, err)
+	}
+}
+
+func TestParse_ConfigArgs(t *testing.T) {
+	got, err := Parse([]string{"weather-cli", "config", "set", "location", "--", "New York"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []string{"set", "location", "--", "New York"}
+	if got.Type != CommandConfig || !reflect.DeepEqual(got.Args, want) {
+		t.Errorf("Parse() = %+v, want config command with args %v", got, want)
+	}
+}
+
+func TestParse_FlagsBeforePassthrough(t *testing.T) {
+	got, err := Parse([]string{"weather-cli", "-fjson", "cache", "list"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []string{"--format=json", "list"}
+	if got.Type != CommandCache || !reflect.DeepEqual(got.Args, want) {
+		t.Errorf("Parse() = %+v, want cache command with args %v", got, want)
+	}
+}
+
+func TestParse_UsageErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"unknown long flag", []string{"weather-cli", "--unknown"}, "unknown flag --unknown"},
+		{"unknown short flag", []string{"weather-cli", "-x"}, "unknown flag -x"},
+		{"mistyped flag", []string{"weather-cli", "--dyas", "3"}, "did you mean --days?"},
+		{"missing value", []string{"weather-cli", "--provider"}, "requires a value"},
+		{"missing short value", []string{"weather-cli", "London", "-u"}, "requires a value"},
+		{"non-numeric int", []string{"weather-cli", "--days", "three"}, "must be a whole number"},
+		{"invalid bool", []string{"weather-cli", "--aqi=maybe"}, "must be true or false"},
+		{"invalid duration", []string{"weather-cli", "watch", "--interval", "10"}, "must be a positive duration"},
+		{"negative duration", []string{"weather-cli", "watch", "--interval=-1m"}, "must be a positive duration"},
+		{"invalid date", []string{"weather-cli", "history", "London", "--date", "1/9/2026"}, "must be a date"},
+		{"history without date", []string{"weather-cli", "history", "London"}, "needs --date"},
+		{"mistyped command", []string{"weather-cli", "hourl"}, `did you mean "hourly"`},
+		{"bad key action", []string{"weather-cli", "key", "rotate"}, "usage: weather-cli key"},
+		{"compare one location", []string{"weather-cli", "compare", "London"}, "at least two locations"},
+		{"compare with template", []string{"weather-cli", "compare", "London", "Paris", "--template", "card.tmpl"}, "--template cannot be used with the compare command"},
+		{"offline and refresh", []string{"weather-cli", "--offline", "--refresh"}, "cannot be combined"},
+		{"flag before config", []string{"weather-cli", "--days", "3", "config", "list"}, "--days cannot be used with the config command"},
+		{"flag before cache", []string{"weather-cli", "--offline", "cache", "list"}, "--offline cannot be used with the cache command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.args)
+
+			var usageErr *UsageError
+			if !errors.As(err, &usageErr) {
+				t.Fatalf("Parse() error = %v, want *UsageError", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %q, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParse_CompareLocations(t *testing.T) {
+	got, err := Parse([]string{"weather-cli", "compare", "London", "@home", "-33.9,151.2", "-f", "json"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []string{"London", "@home", "-33.9,151.2"}
+	if got.Type != CommandCompare || !reflect.DeepEqual(got.Locations, want) || got.Format != "json" {
+		t.Errorf("Parse() = %+v, want compare of %v", got, want)
+	}
+}
+
+func TestParse_CapitalizedNameIsLocation(t *testing.T) {
+	got, err := Parse([]string{"weather-cli", "Daly", "City"})
+	if err != nil || got.Location != "Daly City" {
+		t.Errorf("Parse() = %+v, %v; want location Daly City", got, err)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	names := []string{"now", "hourly", "daily", "alerts"}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"hourl", "hourly"},
+		{"dialy", "daily"},
+		{"nwo", "now"},
+		{"paris", ""},
+		{"london", ""},
+		{"now", ""},
+	}
+
+	for _, tt := range tests {
+		if got := suggest(tt.input, names); got != tt.want {
+			t.Errorf("suggest(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jtotty/weather-cli/internal/cache"
+	"github.com/jtotty/weather-cli/internal/config"
+	"github.com/jtotty/weather-cli/internal/output"
+	"github.com/jtotty/weather-cli/internal/provider"
+	"github.com/jtotty/weather-cli/internal/ui"
+	"github.com/jtotty/weather-cli/internal/units"
+	"github.com/jtotty/weather-cli/internal/weather"
+)
+
+// The scripts delegate to the hidden "__complete" command, which receives
+// the words typed so far (the last one possibly partial) and prints one
+// candidate per line. Keeping the logic in Go means the scripts never go
+// stale as commands and flags are added.
+var completionScripts = map[string]string{
+	"bash": `# bash completion for weather-cli
+# Add to ~/.bashrc: source <(weather-cli completion bash)
+_weather_cli() {
+    local IFS=$'\n' candidate
+    COMPREPLY=()
+    for candidate in $(weather-cli __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null); do
+        COMPREPLY+=("$(printf '%q' "$candidate")")
+    done
+}
+complete -o default -F _weather_cli weather-cli
+`,
+	"zsh": `#compdef weather-cli
+# Add to ~/.zshrc: source <(weather-cli completion zsh)
+_weather_cli() {
+    local -a candidates
+    candidates=("${(@f)$(weather-cli __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    candidates=(${candidates:#})
+    if (( ${#candidates} )); then
+        compadd -- "${candidates[@]}"
+    else
+        _files
+    fi
+}
+compdef _weather_cli weather-cli
+`,
+	"fish": `# fish completion for weather-cli
+# Save to ~/.config/fish/completions/weather-cli.fish
+function __weather_cli_complete
+    set -l tokens (commandline -opc) (commandline -ct)
+    weather-cli __complete $tokens[2..-1] 2>/dev/null
+end
+complete -c weather-cli -f -a '(__weather_cli_complete)'
+`,
+	"powershell": `# PowerShell completion for weather-cli
+# Add to $PROFILE: weather-cli completion powershell | Out-String | Invoke-Expression
+Register-ArgumentCompleter -Native -CommandName weather-cli -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $words = @($commandAst.CommandElements | Select-Object -Skip 1 | ForEach-Object { $_.ToString() })
+    if ($wordToComplete -eq '') { $words += '""' }
+    weather-cli __complete @words 2>$null | ForEach-Object {
+        $text = if ($_ -match '\s') { "'$_'" } else { $_ }
+        [System.Management.Automation.CompletionResult]::new($text, $_, 'ParameterValue', $_)
+    }
+}
+`,
+}
+
+// RunCompletion prints the completion script for the named shell.
+func RunCompletion(args []string, w io.Writer) error {
+	shells := make([]string, 0, len(completionScripts))
+	for shell := range completionScripts {
+		shells = append(shells, shell)
+	}
+	sort.Strings(shells)
+
+	if len(args) != 1 {
+		return fmt.Errorf("usage: weather-cli completion <%s>", strings.Join(shells, "|"))
+	}
+
+	script, ok := completionScripts[args[0]]
+	if !ok {
+		return fmt.Errorf("unsupported shell %q (available: %s)", args[0], strings.Join(shells, ", "))
+	}
+
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+// RunComplete prints completion candidates for the words typed so far.
+func RunComplete(args []string, w io.Writer) error {
+	for _, candidate := range Complete(args, completionLocations) {
+		if _, err := fmt.Fprintln(w, candidate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// completionLocations returns saved location aliases and previously
+// looked-up locations from the cache.
+func completionLocations() []string {
+	var locations []string
+	for _, alias := range savedAliases() {
+		locations = append(locations, config.AliasPrefix+alias)
+	}
+
+	// Like the cache command, completion falls back to the default TTLs
+	// when the config file cannot be read.
+	var ttls cache.SectionTTLs
+	if cfg, err := config.Load(); err == nil {
+		ttls = cfg.CacheTTLs()
+	}
+
+	c, err := cache.NewForecast(ttls)
+	if err != nil {
+		return locations
+	}
+
+	for _, entry := range c.Entries {
+		if !strings.HasPrefix(entry.Location, "auto:") {
+			locations = append(locations, entry.Location)
+		}
+	}
+	return locations
+}
+
+func savedAliases() []string {
+	path, err := config.Path()
+	if err != nil {
+		return nil
+	}
+
+	f, err := config.LoadFile(path)
+	if err != nil {
+		return nil
+	}
+	return f.LocationAliases()
+}
+
+// flagValues lists the completions for flags that take a fixed set of values.
+var flagValues = map[string]func() []string{
+	"provider": provider.Names,
+	"format":   output.FormatNames,
+	"units":    units.SystemNames,
+	"sections": func() []string { return weather.Sections },
+	"color": func() []string {
+		return []string{string(ui.ColorAuto), string(ui.ColorAlways), string(ui.ColorNever)}
+	},
+}
+
+// subcommandArgs lists the actions of passthrough subcommands.
+var subcommandArgs = map[string][]string{
+	"config":     {"get", "set", "unset", "path", "edit"},
+	"cache":      {"stats", "list", "show", "path", "clear", "prune"},
+	"key":        {"set", "delete"},
+	"locations":  {"add", "list", "remove", "rename"},
+	"completion": {"bash", "fish", "powershell", "zsh"},
+}
+
+// Complete returns the candidates for the last word of args, given the
+// preceding words. locations supplies dynamic location suggestions.
+func Complete(args []string, locations func() []string) []string {
+	return complete(args, locations, savedAliases)
+}
+
+func complete(args []string, locations, aliases func() []string) []string {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+
+	words, cur := args[:len(args)-1], args[len(args)-1]
+	if cur == `""` {
+		cur = ""
+	}
+
+	return filterPrefix(candidates(words, cur, locations, aliases), cur)
+}
+
+func candidates(words []string, cur string, locations, aliases func() []string) []string {
+	if n := len(words); n > 0 {
+		if values := valueFlagCandidates(words[n-1]); values != nil {
+			return values
+		}
+	}
+
+	if strings.HasPrefix(cur, "-") {
+		return flagCandidates()
+	}
+
+	sub, rest := firstSubcommand(words)
+	if sub != nil && sub.passthrough {
+		return passthroughCandidates(sub.name, rest, locations, aliases)
+	}
+	if sub == nil && len(rest) == 0 {
+		return append(visibleSubcommands(), locations()...)
+	}
+	return locations()
+}
+
+// valueFlagCandidates returns the values for word if it is a flag awaiting a
+// value, or nil otherwise.
+func valueFlagCandidates(word string) []string {
+	var def *flagDef
+	switch {
+	case strings.HasPrefix(word, "--") && !strings.Contains(word, "="):
+		def = lookupFlag(strings.TrimPrefix(word, "--"))
+	case len(word) == 2 && word[0] == '-':
+		def = lookupShort(word[1])
+	}
+
+	if def == nil || def.kind == boolFlag {
+		return nil
+	}
+	if values, ok := flagValues[def.name]; ok {
+		return values()
+	}
+	return []string{}
+}
+
+// firstSubcommand finds the subcommand among words, skipping flags and
+// their values, and returns the positional words after it.
+func firstSubcommand(words []string) (*subcommand, []string) {
+	var positional []string
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if strings.HasPrefix(word, "-") && len(word) > 1 && !isNumeric(word) {
+			if valueFlagCandidates(word) != nil {
+				i++
+			}
+			continue
+		}
+
+		if len(positional) == 0 {
+			if sub := lookupSubcommand(word); sub != nil {
+				return sub, words[i+1:]
+			}
+		}
+		positional = append(positional, word)
+	}
+	return nil, positional
+}
+
+func passthroughCandidates(name string, rest []string, locations, aliases func() []string) []string {
+	if len(rest) == 0 {
+		return subcommandArgs[name]
+	}
+	if len(rest) != 1 {
+		return nil
+	}
+
+	switch name + " " + rest[0] {
+	case "config get", "config set", "config unset":
+		return config.Keys()
+	case "locations remove", "locations rename":
+		return aliases()
+	case "cache show":
+		return locations()
+	}
+	return nil
+}
+
+func flagCandidates() []string {
+	names := make([]string, len(flags))
+	for i, f := range flags {
+		names[i] = "--" + f.name
+	}
+	return names
+}
+
+func visibleSubcommands() []string {
+	var names []string
+	for _, sub := range subcommands {
+		if !sub.hidden {
+			names = append(names, sub.name)
+		}
+	}
+	return names
+}
+
+// filterPrefix returns the sorted, de-duplicated candidates starting with
+// prefix, ignoring case.
+func filterPrefix(candidates []string, prefix string) []string {
+	seen := make(map[string]bool)
+	var matches []string
+	for _, c := range candidates {
+		if seen[c] || !strings.HasPrefix(strings.ToLower(c), strings.ToLower(prefix)) {
+			continue
+		}
+		seen[c] = true
+		matches = append(matches, c)
+	}
+	sort.Strings(matches)
+	return matches
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestComplete(t *testing.T) {
+	locations := func() []string { return []string{"London", "Leeds", "New York"} }
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"subcommands and locations", []string{"l"}, []string{"Leeds", "London", "locations"}},
+		{"location after subcommand", []string{"hourly", "n"}, []string{"New York"}},
+		{"location after flags", []string{"-u", "metric", "daily", "Lo"}, []string{"London"}},
+		{"flags", []string{"--fo"}, []string{"--format"}},
+		{"flag values", []string{"--format", ""}, []string{"csv", "json", "ndjson", "text", "yaml"}},
+		{"short flag values", []string{"-p", "n"}, []string{"nws"}},
+		{"free-form flag value", []string{"--days", ""}, nil},
+		{"config actions", []string{"config", "u"}, []string{"unset"}},
+		{"config keys", []string{"config", "set", "units."}, []string{
+			"units.distance", "units.precip", "units.pressure", "units.temp", "units.wind",
+		}},
+		{"completion shells", []string{"completion", `""`}, []string{"bash", "fish", "powershell", "zsh"}},
+		{"hidden commands are not offered", []string{"__"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Complete(tt.args, locations); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Complete(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComplete_SavedAliases(t *testing.T) {
+	locations := func() []string { return []string{"@home", "London"} }
+	aliases := func() []string { return []string{"home", "work"} }
+
+	if got := complete([]string{"@"}, locations, aliases); !reflect.DeepEqual(got, []string{"@home"}) {
+		t.Errorf("complete(@) = %q, want [@home]", got)
+	}
+	if got := complete([]string{"locations", "rename", "w"}, locations, aliases); !reflect.DeepEqual(got, []string{"work"}) {
+		t.Errorf("complete(locations rename w) = %q, want [work]", got)
+	}
+}
+
+func TestRunCompletion(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		t.Run(shell, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := RunCompletion([]string{shell}, &buf); err != nil {
+				t.Fatalf("RunCompletion() error = %v", err)
+			}
+			if !strings.Contains(buf.String(), "weather-cli __complete") {
+				t.Errorf("%s script does not call __complete:\n%s", shell, buf.String())
+			}
+		})
+	}
+
+	if err := RunCompletion([]string{"tcsh"}, &bytes.Buffer{}); err == nil {
+		t.Error("RunCompletion(tcsh) should fail")
+	}
+}
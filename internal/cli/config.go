@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/jtotty/weather-cli/internal/config"
+)
+
+// RunConfig handles "weather-cli config <action> [KEY] [VALUE]".
+func RunConfig(args []string, w io.Writer) error {
+	path, err := config.Path()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return errors.New("usage: weather-cli config <get|set|unset|path|edit> [KEY] [VALUE]")
+	}
+
+	action, args := args[0], args[1:]
+
+	switch action {
+	case "path":
+		_, err := fmt.Fprintln(w, path)
+		return err
+	case "edit":
+		return editConfig(path)
+	}
+
+	f, err := config.LoadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "get":
+		return configGet(w, f, args)
+	case "set":
+		if len(args) != 2 {
+			return errors.New("usage: weather-cli config set KEY VALUE")
+		}
+		if err := f.Set(args[0], args[1]); err != nil {
+			return err
+		}
+		return f.Save(path)
+	case "unset":
+		if len(args) != 1 {
+			return errors.New("usage: weather-cli config unset KEY")
+		}
+		if err := f.Unset(args[0]); err != nil {
+			return err
+		}
+		return f.Save(path)
+	default:
+		return fmt.Errorf("unknown config action %q (available: get, set, unset, path, edit)", action)
+	}
+}
+
+// configGet prints one key, or every set key as "key = value" when no key
+// is given.
+func configGet(w io.Writer, f *config.File, args []string) error {
+	if len(args) > 1 {
+		return errors.New("usage: weather-cli config get [KEY]")
+	}
+
+	if len(args) == 1 {
+		value, err := f.Get(args[0])
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, value)
+		return err
+	}
+
+	for _, key := range config.Keys() {
+		value, _ := f.Get(key)
+		if value != "" {
+			if _, err := fmt.Fprintf(w, "%s = %s\n", key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// editConfig opens the config file in $VISUAL or $EDITOR and validates the
+// result.
+func editConfig(path string) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if err := (&config.File{}).Save(path); err != nil {
+			return err
+		}
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	// The editor may carry arguments, e.g. "code --wait".
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor failed: %w", err)
+	}
+
+	_, err := config.LoadFile(path)
+	return err
+}
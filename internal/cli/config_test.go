@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("WEATHER_CONFIG", path)
+
+	run := func(args ...string) (string, error) {
+		var buf bytes.Buffer
+		err := RunConfig(args, &buf)
+		return buf.String(), err
+	}
+
+	if out, err := run("path"); err != nil || strings.TrimSpace(out) != path {
+		t.Errorf("config path = %q, %v; want %q", out, err, path)
+	}
+
+	if _, err := run("set", "location", "New York"); err != nil {
+		t.Fatalf("config set error = %v", err)
+	}
+	if _, err := run("set", "days", "3"); err != nil {
+		t.Fatalf("config set error = %v", err)
+	}
+
+	if out, _ := run("get", "location"); out != "New York\n" {
+		t.Errorf("config get location = %q, want %q", out, "New York\n")
+	}
+	if out, _ := run("get"); out != "days = 3\nlocation = New York\n" {
+		t.Errorf("config get = %q", out)
+	}
+
+	if _, err := run("unset", "days"); err != nil {
+		t.Fatalf("config unset error = %v", err)
+	}
+	if out, _ := run("get", "days"); out != "\n" {
+		t.Errorf("config get days after unset = %q, want empty", out)
+	}
+}
+
+func TestRunConfig_Errors(t *testing.T) {
+	t.Setenv("WEATHER_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"no action", nil, "usage"},
+		{"unknown action", []string{"list"}, "unknown config action"},
+		{"set without value", []string{"set", "days"}, "usage"},
+		{"unset without key", []string{"unset"}, "usage"},
+		{"unknown key", []string{"get", "color"}, "unknown config key"},
+		{"invalid value", []string{"set", "days", "99"}, "between 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RunConfig(tt.args, &bytes.Buffer{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("RunConfig() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jtotty/weather-cli/internal/api/weather"
+	"github.com/jtotty/weather-cli/internal/cache"
+	"github.com/jtotty/weather-cli/internal/credentials"
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/redact"
+)
+
+// Exit statuses, so scripts can tell failures apart. Usage errors exit
+// with 2 (see ExitWithUsageError).
+const (
+	ExitFailure          = 1
+	ExitLocationNotFound = 3
+	ExitKeyRejected      = 4
+	ExitQuotaExceeded    = 5
+	ExitCanceled         = 130
+)
+
+// DescribeFetchError returns an actionable message and exit status for an
+// error from fetching the forecast for location.
+func DescribeFetchError(err error, location string) (string, int) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "Request canceled.", ExitCanceled
+	case errors.Is(err, cache.ErrNotCached):
+		return fmt.Sprintf("Nothing is cached for %q.\n"+
+			"Run without --offline to fetch it.", location), ExitFailure
+	case errors.Is(err, forecast.ErrLocationNotFound):
+		return fmt.Sprintf("No location matching %q was found.\n"+
+			"Check the spelling, or try a postcode or lat,lon coordinates.", location), ExitLocationNotFound
+	case errors.Is(err, weather.ErrInvalidKey):
+		return "Your weatherapi.com API key was rejected.\n" + keyHint(), ExitKeyRejected
+	case errors.Is(err, weather.ErrKeyDisabled):
+		return "Your weatherapi.com API key has been disabled.\n" +
+			"Check your account at https://www.weatherapi.com/, then " + keyHint(), ExitKeyRejected
+	case errors.Is(err, weather.ErrNotInPlan):
+		return "Your weatherapi.com plan does not include this data.\n" +
+			"Free plans only cover the last 7 days of history; see https://www.weatherapi.com/pricing.aspx.", ExitFailure
+	case errors.Is(err, weather.ErrQuotaExceeded):
+		return "Your weatherapi.com API key has used up its monthly quota.\n" +
+			"Wait for it to reset, upgrade your plan, or use another provider, e.g. --provider open-meteo.", ExitQuotaExceeded
+	default:
+		return "error fetching weather: " + redact.String(err.Error()), ExitFailure
+	}
+}
+
+func keyHint() string {
+	if credentials.KeyFromEnv() {
+		return "update the WEATHER_API_KEY environment variable."
+	}
+	return "run 'weather-cli key set' to enter a new key."
+}
+
+// ExitWithFetchError reports an error from fetching the forecast for
+// location and exits with the matching status.
+func ExitWithFetchError(err error, location string) {
+	msg, code := DescribeFetchError(err, location)
+	if code == ExitCanceled {
+		msg = "\n" + msg
+	}
+	fmt.Fprintln(os.Stderr, msg)
+	os.Exit(code)
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/jtotty/weather-cli/internal/api/geocode"
+	"github.com/jtotty/weather-cli/internal/api/weather"
+	"github.com/jtotty/weather-cli/internal/cache"
+	"github.com/jtotty/weather-cli/internal/redact"
+)
+
+func TestDescribeFetchError(t *testing.T) {
+	t.Setenv("WEATHER_API_KEY", "")
+
+	apiErr := func(code int) error {
+		return fmt.Errorf("weatherapi: %w", &weather.APIError{Code: code})
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantMsg  string
+	}{
+		{"location not found", apiErr(1006), ExitLocationNotFound, `No location matching "Atlantis"`},
+		{"invalid key", apiErr(2006), ExitKeyRejected, "weather-cli key set"},
+		{"disabled key", apiErr(2008), ExitKeyRejected, "has been disabled"},
+		{"not in plan", apiErr(2009), ExitFailure, "plan does not include"},
+		{"quota exceeded", apiErr(2007), ExitQuotaExceeded, "--provider open-meteo"},
+		{"geocoding not found", fmt.Errorf("open-meteo: %w: Atlantis", geocode.ErrNotFound), ExitLocationNotFound, `No location matching "Atlantis"`},
+		{"among provider errors", errors.Join(errors.New("open-meteo: timeout"), apiErr(1006)), ExitLocationNotFound, "No location"},
+		{"canceled", context.Canceled, ExitCanceled, "canceled"},
+		{"offline miss", fmt.Errorf("no forecast: %w", cache.ErrNotCached), ExitFailure, "without --offline"},
+		{"other", errors.New("connection refused"), ExitFailure, "error fetching weather: connection refused"},
+		{"key in URL", errors.New(`Get "https://api.weatherapi.com/v1/forecast.json?key=abc123secret&q=x": EOF`), ExitFailure, "key=REDACTED&q=x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, code := DescribeFetchError(tt.err, "Atlantis")
+			if code != tt.wantCode {
+				t.Errorf("code = %d, want %d", code, tt.wantCode)
+			}
+			if !strings.Contains(msg, tt.wantMsg) {
+				t.Errorf("message = %q, want it to contain %q", msg, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestDescribeFetchError_KeyFromEnv(t *testing.T) {
+	t.Setenv("WEATHER_API_KEY", "bad-key")
+
+	msg, _ := DescribeFetchError(&weather.APIError{Code: 2006}, "London")
+	if !strings.Contains(msg, "WEATHER_API_KEY") {
+		t.Errorf("message = %q, want hint about WEATHER_API_KEY", msg)
+	}
+}
+
+func TestExitWithError_RedactsSecrets(t *testing.T) {
+	const key = "exit-secret-key"
+
+	if os.Getenv("WEATHER_TEST_EXIT") == "1" {
+		redact.Register(key)
+		ExitWithError(fmt.Errorf("request with %s failed", key))
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestExitWithError_RedactsSecrets$")
+	cmd.Env = append(os.Environ(), "WEATHER_TEST_EXIT=1")
+	out, err := cmd.CombinedOutput()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != ExitFailure {
+		t.Fatalf("exit = %v, want status %d", err, ExitFailure)
+	}
+	if strings.Contains(string(out), key) || !strings.Contains(string(out), "request with REDACTED failed") {
+		t.Errorf("stderr = %q, want the key redacted", out)
+	}
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jtotty/weather-cli/internal/api/geocode"
+	"github.com/jtotty/weather-cli/internal/config"
+)
+
+const locationsUsage = `usage: weather-cli locations add ALIAS LOCATION [--name NAME] [--units UNITS] [--days N]
+       weather-cli locations list
+       weather-cli locations remove ALIAS
+       weather-cli locations rename ALIAS NEW_ALIAS`
+
+// RunLocations handles "weather-cli locations <action>".
+func RunLocations(ctx context.Context, args []string, w io.Writer) error {
+	return runLocations(ctx, args, w, geocode.NewClient())
+}
+
+func runLocations(ctx context.Context, args []string, w io.Writer, resolver geocode.Resolver) error {
+	if len(args) == 0 {
+		return errors.New(locationsUsage)
+	}
+
+	path, err := config.Path()
+	if err != nil {
+		return err
+	}
+
+	f, err := config.LoadFile(path)
+	if err != nil {
+		return err
+	}
+
+	action, args := args[0], args[1:]
+
+	switch {
+	case action == "list" && len(args) == 0:
+		return listLocations(w, f)
+	case action == "add":
+		err = addLocation(ctx, f, args, resolver)
+	case action == "remove" && len(args) == 1:
+		err = f.RemoveLocation(args[0])
+	case action == "rename" && len(args) == 2:
+		err = f.RenameLocation(args[0], args[1])
+	default:
+		return errors.New(locationsUsage)
+	}
+
+	if err != nil {
+		return err
+	}
+	return f.Save(path)
+}
+
+// addLocation parses "ALIAS LOCATION [--name NAME] [--units UNITS]
+// [--days N]" and geocodes LOCATION unless it is already coordinates.
+func addLocation(ctx context.Context, f *config.File, args []string, resolver geocode.Resolver) error {
+	var positional []string
+	var loc config.SavedLocation
+
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		if name != "--name" && name != "--units" && name != "--days" {
+			positional = append(positional, args[i])
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return fmt.Errorf("flag %s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+
+		switch name {
+		case "--name":
+			loc.Name = value
+		case "--units":
+			loc.Units = value
+		case "--days":
+			days, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid value %q for --days: must be a whole number", value)
+			}
+			loc.Days = days
+		}
+	}
+
+	if len(positional) < 2 {
+		return errors.New(locationsUsage)
+	}
+
+	alias, query := positional[0], strings.Join(positional[1:], " ")
+
+	place, err := resolver.Resolve(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	loc.Lat, loc.Lon = place.Lat, place.Lon
+	if loc.Name == "" {
+		if _, _, isCoords := geocode.ParseCoordinates(query); !isCoords {
+			loc.Name = place.Name
+		}
+	}
+
+	return f.AddLocation(alias, loc)
+}
+
+func listLocations(w io.Writer, f *config.File) error {
+	aliases := f.LocationAliases()
+	if len(aliases) == 0 {
+		_, err := fmt.Fprintln(w, "No saved locations. Add one with 'weather-cli locations add ALIAS LOCATION'.")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ALIAS\tNAME\tCOORDINATES\tUNITS\tDAYS")
+	for _, alias := range aliases {
+		loc := f.Locations[alias]
+		days := ""
+		if loc.Days != 0 {
+			days = strconv.Itoa(loc.Days)
+		}
+		fmt.Fprintf(tw, "%s%s\t%s\t%s\t%s\t%s\n", config.AliasPrefix, alias, loc.Name, loc.Coordinates(), loc.Units, days)
+	}
+	return tw.Flush()
+}
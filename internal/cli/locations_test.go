@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jtotty/weather-cli/internal/api/geocode"
+	"github.com/jtotty/weather-cli/internal/config"
+)
+
+type stubResolver map[string]geocode.Place
+
+func (s stubResolver) Resolve(_ context.Context, location string) (geocode.Place, error) {
+	if lat, lon, ok := geocode.ParseCoordinates(location); ok {
+		return geocode.Place{Name: geocode.FormatCoordinates(lat, lon), Lat: lat, Lon: lon}, nil
+	}
+	if place, ok := s[location]; ok {
+		return place, nil
+	}
+	return geocode.Place{}, geocode.ErrNotFound
+}
+
+func TestRunLocations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("WEATHER_CONFIG", path)
+
+	resolver := stubResolver{"New York": {Name: "New York", Lat: 40.7143, Lon: -74.006}}
+	run := func(args ...string) (string, error) {
+		var buf bytes.Buffer
+		err := runLocations(context.Background(), args, &buf, resolver)
+		return buf.String(), err
+	}
+
+	if _, err := run("add", "home", "51.5,-0.1", "--units", "metric", "--days=3"); err != nil {
+		t.Fatalf("add coordinates error = %v", err)
+	}
+	if _, err := run("add", "nyc", "New", "York"); err != nil {
+		t.Fatalf("add city error = %v", err)
+	}
+	if _, err := run("rename", "nyc", "@ny"); err != nil {
+		t.Fatalf("rename error = %v", err)
+	}
+
+	f, err := config.LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	home := f.Locations["home"]
+	if home.Name != "home" || home.Units != "metric" || home.Days != 3 || home.Lat != 51.5 {
+		t.Errorf("home = %+v", home)
+	}
+	if ny := f.Locations["ny"]; ny.Name != "New York" || ny.Lon != -74.006 {
+		t.Errorf("ny = %+v", ny)
+	}
+
+	out, err := run("list")
+	if err != nil {
+		t.Fatalf("list error = %v", err)
+	}
+	for _, want := range []string{"@home", "51.5000,-0.1000", "metric", "@ny", "New York"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("list output missing %q:\n%s", want, out)
+		}
+	}
+
+	if _, err := run("remove", "home"); err != nil {
+		t.Fatalf("remove error = %v", err)
+	}
+	if out, _ := run("list"); strings.Contains(out, "@home") {
+		t.Errorf("list after remove still shows @home:\n%s", out)
+	}
+}
+
+func TestRunLocations_Errors(t *testing.T) {
+	t.Setenv("WEATHER_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"no action", nil, "usage"},
+		{"add without location", []string{"add", "home"}, "usage"},
+		{"add missing flag value", []string{"add", "home", "Leeds", "--days"}, "requires a value"},
+		{"unknown place", []string{"add", "home", "Atlantis"}, "no matching location"},
+		{"remove unknown", []string{"remove", "home"}, "unknown location @home"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runLocations(context.Background(), tt.args, &bytes.Buffer{}, stubResolver{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("runLocations() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,408 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UsageError reports a malformed command line. Callers should print it with
+// a pointer to --help and exit with status 2.
+type UsageError struct {
+	msg string
+}
+
+func (e *UsageError) Error() string {
+	return e.msg
+}
+
+func usageErrorf(format string, args ...any) error {
+	return &UsageError{msg: fmt.Sprintf(format, args...)}
+}
+
+type flagKind int
+
+const (
+	boolFlag flagKind = iota
+	stringFlag
+	intFlag
+	durationFlag
+	dateFlag
+)
+
+type flagDef struct {
+	name  string
+	short byte
+	kind  flagKind
+	// set stores the parsed value. Bool flags receive "true" or "false",
+	// int, duration and date flags a validated string.
+	set func(cmd *Command, value string)
+	// final flags such as --help end parsing immediately.
+	final bool
+}
+
+var flags = []flagDef{
+	{name: "help", short: 'h', final: true, set: func(c *Command, _ string) { c.Type = CommandHelp }},
+	{name: "version", short: 'v', final: true, set: func(c *Command, _ string) { c.Type = CommandVersion }},
+	{name: "setup", final: true, set: func(c *Command, _ string) { c.Type = CommandSetup }},
+	{name: "delete-key", final: true, set: func(c *Command, _ string) { c.Type = CommandDeleteKey }},
+	{name: "provider", short: 'p', kind: stringFlag, set: func(c *Command, v string) { c.Provider = v }},
+	{name: "format", short: 'f', kind: stringFlag, set: func(c *Command, v string) { c.Format = v }},
+	{name: "template", short: 't', kind: stringFlag, set: func(c *Command, v string) { c.Template = v }},
+	{name: "units", short: 'u', kind: stringFlag, set: func(c *Command, v string) { c.Units = v }},
+	{name: "days", short: 'd', kind: intFlag, set: func(c *Command, v string) { c.Days, _ = strconv.Atoi(v) }},
+	{name: "sections", short: 's', kind: stringFlag, set: func(c *Command, v string) { c.Sections = v }},
+	{name: "color", kind: stringFlag, set: func(c *Command, v string) { c.Color = v }},
+	{name: "aqi", set: func(c *Command, v string) { c.AQI = boolPtr(v) }},
+	{name: "alerts", set: func(c *Command, v string) { c.Alerts = boolPtr(v) }},
+	{name: "details", set: func(c *Command, v string) { c.Details = v == "true" }},
+	{name: "viewer-time", set: func(c *Command, v string) { c.ViewerTime = v == "true" }},
+	{name: "offline", set: func(c *Command, v string) { c.Offline = v == "true" }},
+	{name: "refresh", set: func(c *Command, v string) { c.Refresh = v == "true" }},
+	{name: "interval", short: 'i', kind: durationFlag, set: func(c *Command, v string) { c.Interval, _ = time.ParseDuration(v) }},
+	{name: "date", kind: dateFlag, set: func(c *Command, v string) { c.Date, _ = time.Parse(dateLayout, v) }},
+	{name: "to", kind: dateFlag, set: func(c *Command, v string) { c.To, _ = time.Parse(dateLayout, v) }},
+}
+
+// dateLayout is the format of --date and --to.
+const dateLayout = "2006-01-02"
+
+type subcommand struct {
+	name string
+	typ  CommandType
+	// sections preselects display sections for forecast subcommands.
+	sections string
+	// passthrough subcommands receive the remaining arguments unparsed.
+	passthrough bool
+	// flags lists the global flags a passthrough subcommand accepts before
+	// its name; they are passed on ahead of its arguments.
+	flags []string
+	// hidden subcommands are left out of suggestions and completion.
+	hidden bool
+	// multiLocation subcommands take each positional argument as a location.
+	multiLocation bool
+}
+
+var subcommands = []subcommand{
+	{name: "now", sections: "time,current"},
+	{name: "hourly", sections: "hourly"},
+	{name: "daily", sections: "daily"},
+	{name: "alerts", sections: "alerts"},
+	{name: "astro", sections: "astro"},
+	{name: "compare", typ: CommandCompare, multiLocation: true},
+	{name: "watch", typ: CommandWatch},
+	{name: "tui", typ: CommandTUI},
+	{name: "search", typ: CommandSearch},
+	{name: "history", typ: CommandHistory},
+	{name: "config", typ: CommandConfig, passthrough: true},
+	{name: "cache", typ: CommandCache, passthrough: true, flags: []string{"format"}},
+	{name: "key", typ: CommandSetup, passthrough: true},
+	{name: "locations", typ: CommandLocations, passthrough: true},
+	{name: "completion", typ: CommandCompletion, passthrough: true},
+	{name: "__complete", typ: CommandComplete, passthrough: true, hidden: true},
+}
+
+// Parse parses os.Args-style arguments. Flags may appear before or after
+// the subcommand and location; "--" ends flag parsing so a location that
+// starts with "-" or matches a subcommand name can still be given.
+func Parse(args []string) (Command, error) {
+	p := &parser{cmd: Command{Type: CommandWeather}}
+	if len(args) > 0 {
+		args = args[1:]
+	}
+
+	if err := p.parse(args); err != nil {
+		return Command{}, err
+	}
+	if p.cmd.Offline && p.cmd.Refresh {
+		return Command{}, usageErrorf("--offline and --refresh cannot be combined")
+	}
+	return p.cmd, nil
+}
+
+type parser struct {
+	cmd        Command
+	sub        *subcommand
+	positional []string
+	done       bool
+	// leading records the flags given before any positional argument, so
+	// they can be handed to a passthrough subcommand.
+	leading []flagValue
+}
+
+type flagValue struct {
+	def   *flagDef
+	value string
+}
+
+func (p *parser) parse(args []string) error {
+	for i := 0; i < len(args) && !p.done; i++ {
+		arg := args[i]
+
+		var consumed int
+		var err error
+
+		switch {
+		case arg == "--":
+			p.positional = append(p.positional, args[i+1:]...)
+			return p.finish()
+		case strings.HasPrefix(arg, "--"):
+			consumed, err = p.long(arg, args[i+1:])
+		case strings.HasPrefix(arg, "-") && len(arg) > 1 && !isNumeric(arg):
+			consumed, err = p.short(arg, args[i+1:])
+		default:
+			consumed, err = p.positionalArg(arg, args[i+1:])
+		}
+
+		if err != nil {
+			return err
+		}
+		i += consumed
+	}
+
+	if p.done {
+		return nil
+	}
+	return p.finish()
+}
+
+// long handles "--name" and "--name=value". It returns how many of the
+// following arguments it consumed.
+func (p *parser) long(arg string, rest []string) (int, error) {
+	name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+
+	def := lookupFlag(name)
+	if def == nil {
+		return 0, unknownFlag("--" + name)
+	}
+
+	if def.kind == boolFlag {
+		if !hasValue {
+			value = "true"
+		}
+		return 0, p.apply(def, "--"+name, value)
+	}
+
+	if hasValue {
+		return 0, p.apply(def, "--"+name, value)
+	}
+	if len(rest) == 0 {
+		return 0, usageErrorf("flag --%s requires a value", name)
+	}
+	return 1, p.apply(def, "--"+name, rest[0])
+}
+
+// short handles a cluster such as "-hv" or "-d3". The first flag that takes
+// a value uses the rest of the cluster, or the next argument.
+func (p *parser) short(arg string, rest []string) (int, error) {
+	cluster := arg[1:]
+
+	for j := 0; j < len(cluster); j++ {
+		def := lookupShort(cluster[j])
+		if def == nil {
+			return 0, unknownFlag("-" + string(cluster[j]))
+		}
+
+		if def.kind == boolFlag {
+			if err := p.apply(def, "-"+string(cluster[j]), "true"); err != nil || p.done {
+				return 0, err
+			}
+			continue
+		}
+
+		if value := cluster[j+1:]; value != "" {
+			return 0, p.apply(def, "-"+string(cluster[j]), strings.TrimPrefix(value, "="))
+		}
+		if len(rest) == 0 {
+			return 0, usageErrorf("flag -%c requires a value", cluster[j])
+		}
+		return 1, p.apply(def, "-"+string(cluster[j]), rest[0])
+	}
+
+	return 0, nil
+}
+
+func (p *parser) apply(def *flagDef, name, value string) error {
+	switch def.kind {
+	case intFlag:
+		if _, err := strconv.Atoi(value); err != nil {
+			return usageErrorf("invalid value %q for %s: must be a whole number", value, name)
+		}
+	case durationFlag:
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return usageErrorf("invalid value %q for %s: must be a positive duration such as 30s or 10m", value, name)
+		}
+	case dateFlag:
+		if _, err := time.Parse(dateLayout, value); err != nil {
+			return usageErrorf("invalid value %q for %s: must be a date such as 2026-09-01", value, name)
+		}
+	case boolFlag:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return usageErrorf("invalid value %q for %s: must be true or false", value, name)
+		}
+		value = strconv.FormatBool(b)
+	}
+
+	def.set(&p.cmd, value)
+	if p.sub == nil && len(p.positional) == 0 {
+		p.leading = append(p.leading, flagValue{def, value})
+	}
+	if def.final {
+		p.done = true
+	}
+	return nil
+}
+
+// positionalArg records a location word, or selects a subcommand when it is
+// the first positional argument. Passthrough subcommands consume the rest.
+func (p *parser) positionalArg(arg string, rest []string) (int, error) {
+	if p.sub != nil || len(p.positional) > 0 {
+		p.positional = append(p.positional, arg)
+		return 0, nil
+	}
+
+	if sub := lookupSubcommand(arg); sub != nil {
+		p.sub = sub
+		if sub.passthrough {
+			args, err := p.passthroughFlags()
+			if err != nil {
+				return 0, err
+			}
+			p.cmd.Args = append(args, rest...)
+			p.done = true
+			return len(rest), p.finishPassthrough()
+		}
+		return 0, nil
+	}
+
+	if suggestion := suggest(arg, visibleSubcommands()); suggestion != "" && arg == strings.ToLower(arg) {
+		return 0, usageErrorf("unknown command %q, did you mean %q? (use 'weather-cli -- %s' for a location of that name)",
+			arg, suggestion, arg)
+	}
+
+	p.positional = append(p.positional, arg)
+	return 0, nil
+}
+
+// passthroughFlags returns the flags given before a passthrough subcommand
+// as "--name=value" arguments for it, rejecting any it does not accept
+// rather than silently dropping them.
+func (p *parser) passthroughFlags() ([]string, error) {
+	var args []string
+	for _, f := range p.leading {
+		if !slices.Contains(p.sub.flags, f.def.name) {
+			return nil, usageErrorf("flag --%s cannot be used with the %s command", f.def.name, p.sub.name)
+		}
+		args = append(args, "--"+f.def.name+"="+f.value)
+	}
+	return args, nil
+}
+
+func (p *parser) finishPassthrough() error {
+	p.cmd.Type = p.sub.typ
+	if p.sub.name != "key" {
+		return nil
+	}
+
+	// "key" resolves to the existing setup and delete commands.
+	action := ""
+	if len(p.cmd.Args) == 1 {
+		action = p.cmd.Args[0]
+	}
+	switch action {
+	case "set":
+		p.cmd.Type = CommandSetup
+	case "delete":
+		p.cmd.Type = CommandDeleteKey
+	default:
+		return usageErrorf("usage: weather-cli key <set|delete>")
+	}
+	p.cmd.Args = nil
+	return nil
+}
+
+// finish joins the positional words into the location, so unquoted
+// multi-word names such as New York work. Subcommands taking several
+// locations get each word as its own location instead.
+func (p *parser) finish() error {
+	if p.sub == nil {
+		p.cmd.Location = strings.Join(p.positional, " ")
+		return nil
+	}
+
+	p.cmd.Type = p.sub.typ
+	if p.cmd.Type == CommandHistory && p.cmd.Date.IsZero() {
+		return usageErrorf("history needs --date YYYY-MM-DD")
+	}
+	if p.cmd.Type == CommandCompare && p.cmd.Template != "" {
+		return usageErrorf("flag --template cannot be used with the %s command", p.sub.name)
+	}
+	if p.sub.multiLocation {
+		if len(p.positional) < 2 {
+			return usageErrorf("%s needs at least two locations", p.sub.name)
+		}
+		p.cmd.Locations = p.positional
+	} else {
+		p.cmd.Location = strings.Join(p.positional, " ")
+	}
+
+	if p.cmd.Sections == "" {
+		p.cmd.Sections = p.sub.sections
+	}
+	return nil
+}
+
+func lookupFlag(name string) *flagDef {
+	for i := range flags {
+		if flags[i].name == name {
+			return &flags[i]
+		}
+	}
+	return nil
+}
+
+func lookupShort(c byte) *flagDef {
+	for i := range flags {
+		if flags[i].short == c {
+			return &flags[i]
+		}
+	}
+	return nil
+}
+
+func lookupSubcommand(name string) *subcommand {
+	for i := range subcommands {
+		if subcommands[i].name == name {
+			return &subcommands[i]
+		}
+	}
+	return nil
+}
+
+func unknownFlag(name string) error {
+	if strings.HasPrefix(name, "--") {
+		names := make([]string, len(flags))
+		for i, f := range flags {
+			names[i] = f.name
+		}
+		if suggestion := suggest(strings.TrimPrefix(name, "--"), names); suggestion != "" {
+			return usageErrorf("unknown flag %s, did you mean --%s?", name, suggestion)
+		}
+	}
+	return usageErrorf("unknown flag %s", name)
+}
+
+// isNumeric reports whether arg looks like a negative number or coordinate
+// pair, such as "-33.9,151.2", rather than a flag.
+func isNumeric(arg string) bool {
+	c := arg[1]
+	return c == '.' || (c >= '0' && c <= '9')
+}
+
+func boolPtr(value string) *bool {
+	b := value == "true"
+	return &b
+}
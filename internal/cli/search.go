@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jtotty/weather-cli/internal/api/geocode"
+	"github.com/jtotty/weather-cli/internal/api/weather"
+	"github.com/jtotty/weather-cli/internal/config"
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/output"
+)
+
+// Searcher lists the locations matching a free-text query.
+type Searcher interface {
+	Search(ctx context.Context, query string) ([]weather.SearchResult, error)
+}
+
+// RunSearch handles "weather-cli search QUERY", listing the matching
+// locations as a table or, with --format json, as a JSON array. No matches
+// is reported as forecast.ErrLocationNotFound.
+func RunSearch(ctx context.Context, searcher Searcher, query string, format output.Format, w io.Writer) error {
+	if format != output.FormatText && format != output.FormatJSON {
+		return fmt.Errorf("search supports text and json output, not %s", format)
+	}
+
+	results, err := searcher.Search(ctx, query)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("%w: %s", forecast.ErrLocationNotFound, query)
+	}
+
+	if format == output.FormatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tNAME\tREGION\tCOUNTRY\tCOORDINATES")
+	for i, r := range results {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", i+1, r.Name, r.Region, r.Country, r.Query())
+	}
+	return tw.Flush()
+}
+
+// NeedsSearch reports whether location is free text that could match
+// several places. Coordinates, @aliases, IP lookup and prefixed queries
+// such as "iata:LHR" already name a single place.
+func NeedsSearch(location string) bool {
+	location = strings.TrimSpace(location)
+	if location == "" || strings.HasPrefix(location, config.AliasPrefix) || strings.Contains(location, ":") {
+		return false
+	}
+	_, _, isCoords := geocode.ParseCoordinates(location)
+	return !isCoords
+}
+
+// Places remembers the place each query was resolved to. The places cache
+// namespace implements it.
+type Places interface {
+	Get(location string) *forecast.Response
+	Set(location string, data *forecast.Response) error
+}
+
+// Picker chooses among the search results for an ambiguous location.
+type Picker struct {
+	In  io.Reader
+	Out io.Writer
+	// Interactive asks the user to choose. Otherwise the best match is used
+	// for this run only and a note names the alternatives' count.
+	Interactive bool
+	// Places, if set, remembers each match the user picked, or the only
+	// one, so later runs neither search nor ask again.
+	Places Places
+}
+
+// Remembered returns the place query was resolved to before, if any.
+func (p Picker) Remembered(query string) (weather.SearchResult, bool) {
+	if p.Places == nil {
+		return weather.SearchResult{}, false
+	}
+	data := p.Places.Get(query)
+	if data == nil {
+		return weather.SearchResult{}, false
+	}
+	l := data.Location
+	return weather.SearchResult{Name: l.Name, Region: l.Region, Country: l.Country, Lat: l.Lat, Lon: l.Lon}, true
+}
+
+// Choose searches for query and returns the place to forecast, remembering
+// it in Places unless it was guessed among several without asking. It
+// reports false when the search finds nothing or fails,
+// leaving the provider to interpret query itself; only cancellation and an
+// unanswered prompt are returned as errors.
+func (p Picker) Choose(ctx context.Context, searcher Searcher, query string) (weather.SearchResult, bool, error) {
+	results, err := searcher.Search(ctx, query)
+	if errors.Is(err, context.Canceled) {
+		return weather.SearchResult{}, false, err
+	}
+	if err != nil || len(results) == 0 {
+		return weather.SearchResult{}, false, nil
+	}
+
+	choice := 0
+	switch {
+	case len(results) == 1:
+	case !p.Interactive:
+		// Nobody chose, so a guess is not pinned for later runs.
+		fmt.Fprintf(p.Out, "%q matches %d locations; using %s. Run 'weather-cli search %s' to see them all.\n",
+			query, len(results), results[0].Label(), query)
+		return results[0], true, nil
+	default:
+		if choice, err = p.prompt(query, results); err != nil {
+			return weather.SearchResult{}, false, err
+		}
+	}
+
+	p.remember(query, results[choice])
+	return results[choice], true, nil
+}
+
+// remember records place as the answer to query. Failing to save it only
+// means the next run asks again.
+func (p Picker) remember(query string, place weather.SearchResult) {
+	if p.Places == nil {
+		return
+	}
+	_ = p.Places.Set(query, &forecast.Response{Location: forecast.Location{
+		Name:    place.Name,
+		Region:  place.Region,
+		Country: place.Country,
+		Lat:     place.Lat,
+		Lon:     place.Lon,
+	}})
+}
+
+// prompt lists results and reads the number of the chosen one. An empty
+// answer chooses the first.
+func (p Picker) prompt(query string, results []weather.SearchResult) (int, error) {
+	fmt.Fprintf(p.Out, "%q matches %d locations:\n", query, len(results))
+	for i, r := range results {
+		fmt.Fprintf(p.Out, "  %d) %s (%s)\n", i+1, r.Label(), r.Query())
+	}
+
+	reader := bufio.NewReader(p.In)
+	for {
+		fmt.Fprintf(p.Out, "Choose a location [1-%d] (Enter for 1): ", len(results))
+		answer, err := reader.ReadString('\n')
+		answer = strings.TrimSpace(answer)
+
+		if answer == "" && err == nil {
+			return 0, nil
+		}
+		if n, convErr := strconv.Atoi(answer); convErr == nil && n >= 1 && n <= len(results) {
+			return n - 1, nil
+		}
+		if err != nil {
+			fmt.Fprintln(p.Out)
+			return 0, fmt.Errorf("no location chosen for %q", query)
+		}
+		fmt.Fprintf(p.Out, "Enter a number between 1 and %d.\n", len(results))
+	}
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jtotty/weather-cli/internal/api/weather"
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/output"
+)
+
+type stubSearcher struct {
+	results []weather.SearchResult
+	err     error
+}
+
+func (s stubSearcher) Search(context.Context, string) ([]weather.SearchResult, error) {
+	return s.results, s.err
+}
+
+var parisResults = []weather.SearchResult{
+	{Name: "Paris", Region: "Ile-de-France", Country: "France", Lat: 48.87, Lon: 2.33},
+	{Name: "Paris", Region: "Texas", Country: "United States of America", Lat: 33.66, Lon: -95.56},
+	{Name: "Paris", Region: "Tennessee", Country: "United States of America", Lat: 36.3, Lon: -88.33},
+}
+
+func TestRunSearch(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RunSearch(context.Background(), stubSearcher{results: parisResults}, "Paris", output.FormatText, &buf); err != nil {
+		t.Fatalf("RunSearch() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "#") {
+		t.Fatalf("output = %q, want header and three rows", buf.String())
+	}
+	for _, want := range []string{"2", "Texas", "United States of America", "33.6600,-95.5600"} {
+		if !strings.Contains(lines[2], want) {
+			t.Errorf("row = %q, want it to contain %q", lines[2], want)
+		}
+	}
+}
+
+func TestRunSearch_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RunSearch(context.Background(), stubSearcher{results: parisResults}, "Paris", output.FormatJSON, &buf); err != nil {
+		t.Fatalf("RunSearch() error = %v", err)
+	}
+
+	var got []weather.SearchResult
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil || len(got) != 3 || got[1].Region != "Texas" {
+		t.Errorf("output = %s (%v), want the results as JSON", buf.String(), err)
+	}
+}
+
+func TestRunSearch_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	err := RunSearch(ctx, stubSearcher{}, "Atlantis", output.FormatText, &bytes.Buffer{})
+	if !errors.Is(err, forecast.ErrLocationNotFound) {
+		t.Errorf("no matches error = %v, want ErrLocationNotFound", err)
+	}
+
+	err = RunSearch(ctx, stubSearcher{results: parisResults}, "Paris", output.FormatCSV, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "text and json") {
+		t.Errorf("csv error = %v, want unsupported format", err)
+	}
+}
+
+func TestNeedsSearch(t *testing.T) {
+	tests := []struct {
+		location string
+		want     bool
+	}{
+		{"Paris", true},
+		{"New York", true},
+		{"SW1A 1AA", true},
+		{"51.5,-0.1", false},
+		{"@home", false},
+		{"auto:ip", false},
+		{"iata:LHR", false},
+		{" ", false},
+	}
+
+	for _, tt := range tests {
+		if got := NeedsSearch(tt.location); got != tt.want {
+			t.Errorf("NeedsSearch(%q) = %v, want %v", tt.location, got, tt.want)
+		}
+	}
+}
+
+func TestPicker_Choose(t *testing.T) {
+	tests := []struct {
+		name        string
+		searcher    stubSearcher
+		interactive bool
+		input       string
+		wantOK      bool
+		wantRegion  string
+		wantErr     bool
+		wantOut     string
+	}{
+		{"single match", stubSearcher{results: parisResults[:1]}, true, "", true, "Ile-de-France", false, ""},
+		{"no matches", stubSearcher{}, true, "", false, "", false, ""},
+		{"search fails", stubSearcher{err: errors.New("timeout")}, true, "", false, "", false, ""},
+		{"canceled", stubSearcher{err: context.Canceled}, true, "", false, "", true, ""},
+		{"non-interactive uses best match", stubSearcher{results: parisResults}, false, "", true, "Ile-de-France", false, "matches 3 locations; using Paris, Ile-de-France, France"},
+		{"chooses by number", stubSearcher{results: parisResults}, true, "2\n", true, "Texas", false, "2) Paris, Texas"},
+		{"enter chooses first", stubSearcher{results: parisResults}, true, "\n", true, "Ile-de-France", false, "[1-3]"},
+		{"retries invalid answers", stubSearcher{results: parisResults}, true, "9\nx\n3\n", true, "Tennessee", false, "between 1 and 3"},
+		{"no answer", stubSearcher{results: parisResults}, true, "", false, "", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			picker := Picker{In: strings.NewReader(tt.input), Out: &out, Interactive: tt.interactive}
+
+			got, ok, err := picker.Choose(context.Background(), tt.searcher, "Paris")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Choose() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ok != tt.wantOK || got.Region != tt.wantRegion {
+				t.Errorf("Choose() = %+v, %v; want region %q, %v", got, ok, tt.wantRegion, tt.wantOK)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output = %q, want it to contain %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+// memPlaces is an in-memory Places.
+type memPlaces map[string]*forecast.Response
+
+func (m memPlaces) Get(location string) *forecast.Response {
+	return m[location]
+}
+
+func (m memPlaces) Set(location string, data *forecast.Response) error {
+	m[location] = data
+	return nil
+}
+
+func TestPicker_RemembersChoice(t *testing.T) {
+	places := memPlaces{}
+	var out bytes.Buffer
+	picker := Picker{In: strings.NewReader("2\n"), Out: &out, Interactive: true, Places: places}
+
+	if _, ok := picker.Remembered("Paris"); ok {
+		t.Fatal("Remembered() before any choice = true, want false")
+	}
+	if _, _, err := picker.Choose(context.Background(), stubSearcher{results: parisResults}, "Paris"); err != nil {
+		t.Fatalf("Choose() error = %v", err)
+	}
+
+	got, ok := picker.Remembered("Paris")
+	if !ok || got.Region != "Texas" || got.Query() != parisResults[1].Query() {
+		t.Errorf("Remembered() = %+v, %v; want Paris, Texas", got, ok)
+	}
+}
+
+func TestPicker_ForgetsGuess(t *testing.T) {
+	places := memPlaces{}
+	picker := Picker{Out: &bytes.Buffer{}, Places: places}
+
+	got, ok, err := picker.Choose(context.Background(), stubSearcher{results: parisResults}, "Paris")
+	if err != nil || !ok || got.Query() != parisResults[0].Query() {
+		t.Fatalf("Choose() = %+v, %v, %v; want the best match", got, ok, err)
+	}
+	if _, ok := picker.Remembered("Paris"); ok {
+		t.Error("Remembered() = true after a non-interactive guess, want false")
+	}
+}
@@ -1,9 +1,13 @@
 package cli
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/jtotty/weather-cli/internal/credentials"
+	"golang.org/x/term"
 )
 
 func RunSetup() error {
@@ -34,3 +38,26 @@ func RunDeleteKey() error {
 	fmt.Println("API key deleted from keyring.")
 	return nil
 }
+
+// OfferSetup asks whether to enter a new API key after the current one was
+// rejected, and runs setup if so. It reports whether a new key was stored.
+// Nothing is asked when stdin is not a terminal or the key comes from the
+// environment, where setup would not help.
+func OfferSetup() bool {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || credentials.KeyFromEnv() {
+		return false
+	}
+
+	fmt.Fprint(os.Stderr, "Your weatherapi.com API key was rejected. Enter a new key now? [y/N] ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+		return false
+	}
+
+	if err := RunSetup(); err != nil {
+		fmt.Fprintf(os.Stderr, "setup failed: %v\n", err)
+		return false
+	}
+	fmt.Println()
+	return true
+}
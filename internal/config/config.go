@@ -1,27 +1,121 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/api/httpjson"
+	"github.com/jtotty/weather-cli/internal/cache"
 	"github.com/jtotty/weather-cli/internal/credentials"
+	"github.com/jtotty/weather-cli/internal/provider"
+	"github.com/jtotty/weather-cli/internal/units"
 )
 
+// MaxDays is the longest forecast any provider returns.
+const MaxDays = 16
+
 // Config holds the application configuration.
 type Config struct {
 	APIKey     string
+	Providers  []string
 	Location   string
 	Days       int
 	IncludeAQI bool
 	Alerts     bool
 	IsLocal    bool
+
+	// Offline serves only cached data, however old, and never contacts a
+	// provider. Refresh skips the cache lookup and always fetches.
+	Offline bool
+	Refresh bool
+
+	// Template is the path of a text/template file used instead of the
+	// built-in display.
+	Template string
+
+	// UnitSystem names the base unit system; UnitOverrides replaces single
+	// quantities in it, e.g. {"wind": "kph"}.
+	UnitSystem    string
+	UnitOverrides map[string]string
+
+	// LocationName is the display name of a saved location, and Locations
+	// the saved locations that "@alias" arguments resolve against.
+	LocationName string
+	Locations    map[string]SavedLocation
+
+	// LocationQuery is the text the user typed when Location has been
+	// pinned to the coordinates of a search result. The forecast is cached
+	// under it, so listings and completion show what was typed.
+	LocationQuery string
+
+	// Sections limits the text display to the named sections; empty shows
+	// all. Colors is "auto", "always" or "never".
+	Sections []string
+	Colors   string
+
+	// A provider is skipped once it fails BreakerThreshold times within
+	// BreakerWindow. Zero values use the breaker package defaults.
+	BreakerThreshold int
+	BreakerWindow    time.Duration
+
+	// RetryAttempts, RetryDelay and RetryMaxDelay tune retries of transient
+	// failures. Zero values use the httpjson defaults.
+	RetryAttempts int
+	RetryDelay    time.Duration
+	RetryMaxDelay time.Duration
+
+	// CurrentTTL, ForecastTTL and AstroTTL set how long each section of a
+	// cached forecast stays fresh. Zero values use the cache defaults.
+	CurrentTTL  time.Duration
+	ForecastTTL time.Duration
+	AstroTTL    time.Duration
 }
 
-func New() (*Config, error) {
-	cfg := &Config{
+// Default returns the default configuration without an API key.
+func Default() *Config {
+	return &Config{
 		Location:   "auto:ip",
 		Days:       7,
 		IncludeAQI: true,
 		Alerts:     true,
 		IsLocal:    true,
 	}
+}
+
+// Load builds the configuration from the defaults, then the config file,
+// then WEATHER_* environment variables. Command-line flags are applied on
+// top by the caller. The API key is not loaded.
+func Load() (*Config, error) {
+	cfg := Default()
+
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.ApplyFile(f)
+
+	if err := cfg.ApplyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// New loads the configuration and the API key.
+func New() (*Config, error) {
+	cfg, err := Load()
+	if err != nil {
+		return nil, err
+	}
 
 	apiKey, err := credentials.GetAPIKey()
 	if err != nil {
@@ -32,7 +126,207 @@ func New() (*Config, error) {
 	return cfg, nil
 }
 
+// ApplyFile overrides the configuration with every field set in f.
+func (c *Config) ApplyFile(f *File) {
+	c.Locations = f.Locations
+	if f.Location != "" {
+		c.SetLocation(f.Location)
+	}
+	if f.Days != 0 {
+		c.Days = f.Days
+	}
+	if f.Units.System != "" {
+		c.UnitSystem = f.Units.System
+	}
+	for quantity, unit := range map[string]string{
+		"temp":     f.Units.Temp,
+		"wind":     f.Units.Wind,
+		"pressure": f.Units.Pressure,
+		"precip":   f.Units.Precip,
+		"distance": f.Units.Distance,
+	} {
+		if unit != "" {
+			c.SetUnits(quantity + "=" + unit)
+		}
+	}
+	if len(f.Sections) > 0 {
+		c.Sections = f.Sections
+	}
+	if f.Provider != "" {
+		c.SetProviders(f.Provider)
+	}
+	if f.Colors != "" {
+		c.Colors = f.Colors
+	}
+	if f.AQI != nil {
+		c.IncludeAQI = *f.AQI
+	}
+	if f.Alerts != nil {
+		c.Alerts = *f.Alerts
+	}
+	if f.Retry.Attempts != 0 {
+		c.RetryAttempts = f.Retry.Attempts
+	}
+	if f.Retry.Delay != 0 {
+		c.RetryDelay = f.Retry.Delay
+	}
+	if f.Retry.MaxDelay != 0 {
+		c.RetryMaxDelay = f.Retry.MaxDelay
+	}
+	if f.Cache.CurrentTTL != 0 {
+		c.CurrentTTL = f.Cache.CurrentTTL
+	}
+	if f.Cache.ForecastTTL != 0 {
+		c.ForecastTTL = f.Cache.ForecastTTL
+	}
+	if f.Cache.AstroTTL != 0 {
+		c.AstroTTL = f.Cache.AstroTTL
+	}
+}
+
+// RetryPolicy returns the retry policy for provider requests.
+func (c *Config) RetryPolicy() httpjson.RetryPolicy {
+	policy := httpjson.DefaultRetryPolicy()
+	if c.RetryAttempts != 0 {
+		policy.MaxAttempts = c.RetryAttempts
+	}
+	if c.RetryDelay != 0 {
+		policy.BaseDelay = c.RetryDelay
+	}
+	if c.RetryMaxDelay != 0 {
+		policy.MaxDelay = c.RetryMaxDelay
+	}
+	return policy
+}
+
+// CacheTTLs returns the lifetime of each section of a cached forecast.
+func (c *Config) CacheTTLs() cache.SectionTTLs {
+	return cache.SectionTTLs{
+		Current:  c.CurrentTTL,
+		Forecast: c.ForecastTTL,
+		Astro:    c.AstroTTL,
+	}
+}
+
+// ApplyEnv overrides the configuration from WEATHER_* environment variables
+// read through lookup: WEATHER_LOCATION, WEATHER_DAYS, WEATHER_UNITS,
+// WEATHER_SECTIONS, WEATHER_PROVIDER, WEATHER_COLORS, WEATHER_AQI and
+// WEATHER_ALERTS.
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	env := func(name string) string {
+		value, _ := lookup("WEATHER_" + name)
+		return strings.TrimSpace(value)
+	}
+
+	if v := env("LOCATION"); v != "" {
+		c.SetLocation(v)
+	}
+	if v := env("DAYS"); v != "" {
+		days, err := parseDays(v)
+		if err != nil {
+			return fmt.Errorf("WEATHER_DAYS: %w", err)
+		}
+		c.Days = days
+	}
+	if v := env("UNITS"); v != "" {
+		c.SetUnits(v)
+	}
+	if v := env("SECTIONS"); v != "" {
+		if err := c.SetSections(v); err != nil {
+			return fmt.Errorf("WEATHER_SECTIONS: %w", err)
+		}
+	}
+	if v := env("PROVIDER"); v != "" {
+		c.SetProviders(v)
+	}
+	if v := env("COLORS"); v != "" {
+		c.Colors = v
+	}
+
+	for name, field := range map[string]*bool{"AQI": &c.IncludeAQI, "ALERTS": &c.Alerts} {
+		if v := env(name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("WEATHER_%s: invalid boolean %q", name, v)
+			}
+			*field = b
+		}
+	}
+
+	return nil
+}
+
+// SetDays sets the forecast length.
+func (c *Config) SetDays(days int) error {
+	if err := validateDays(days); err != nil {
+		return err
+	}
+	c.Days = days
+	return nil
+}
+
+// SetSections sets the displayed sections from a comma-separated list.
+func (c *Config) SetSections(list string) error {
+	sections, err := parseSections(list)
+	if err != nil {
+		return err
+	}
+	c.Sections = sections
+	return nil
+}
+
+// SetProviders sets the provider chain from a comma-separated list.
+func (c *Config) SetProviders(list string) {
+	c.Providers = nil
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			c.Providers = append(c.Providers, name)
+		}
+	}
+}
+
+// ProviderChain returns the providers to try, in order.
+func (c *Config) ProviderChain() []string {
+	if len(c.Providers) == 0 {
+		return []string{provider.Default}
+	}
+	return c.Providers
+}
+
+// RequiresAPIKey reports whether every provider in the chain needs an API key.
+func (c *Config) RequiresAPIKey() bool {
+	for _, name := range c.ProviderChain() {
+		if !provider.RequiresAPIKey(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// SetUnits sets the unit system from a spec such as "imperial" or
+// "uk,wind=kph,pressure=inhg". Overrides may be given without a system.
+func (c *Config) SetUnits(spec string) {
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if quantity, unit, found := strings.Cut(part, "="); found {
+			if c.UnitOverrides == nil {
+				c.UnitOverrides = make(map[string]string)
+			}
+			c.UnitOverrides[strings.TrimSpace(quantity)] = strings.TrimSpace(unit)
+		} else if part != "" {
+			c.UnitSystem = part
+		}
+	}
+}
+
+// Units resolves the configured unit system and overrides.
+func (c *Config) Units() (units.Units, error) {
+	return units.Parse(c.UnitSystem, c.UnitOverrides)
+}
+
 func (c *Config) SetLocation(location string) {
 	c.Location = location
+	c.LocationName = ""
+	c.LocationQuery = ""
 	c.IsLocal = false
 }
@@ -2,6 +2,10 @@ package config
 
 import (
 	"testing"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/api/httpjson"
+	"github.com/jtotty/weather-cli/internal/cache"
 )
 
 func TestNew_WithEnvAPIKey(t *testing.T) {
@@ -65,3 +69,126 @@ func TestSetLocation_SetsIsLocalFalse(t *testing.T) {
 		t.Error("IsLocal should be false after SetLocation")
 	}
 }
+
+func TestDefault_HasNoAPIKey(t *testing.T) {
+	cfg := Default()
+
+	if cfg.APIKey != "" {
+		t.Errorf("APIKey = %q, want empty", cfg.APIKey)
+	}
+	if len(cfg.Providers) != 0 {
+		t.Errorf("Providers = %v, want empty (default provider)", cfg.Providers)
+	}
+	if cfg.Location != "auto:ip" || cfg.Days != 7 {
+		t.Errorf("Default() = %+v, want auto:ip and 7 days", cfg)
+	}
+}
+
+func TestProviderChain(t *testing.T) {
+	cfg := Default()
+
+	if got := cfg.ProviderChain(); len(got) != 1 || got[0] != "weatherapi" {
+		t.Errorf("ProviderChain() = %v, want [weatherapi]", got)
+	}
+
+	cfg.SetProviders("weatherapi, open-meteo,,nws ")
+
+	want := []string{"weatherapi", "open-meteo", "nws"}
+	got := cfg.ProviderChain()
+	if len(got) != len(want) {
+		t.Fatalf("ProviderChain() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ProviderChain()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestRequiresAPIKey(t *testing.T) {
+	tests := []struct {
+		providers string
+		want      bool
+	}{
+		{"", true},
+		{"weatherapi", true},
+		{"open-meteo", false},
+		{"weatherapi,open-meteo", false},
+	}
+
+	for _, tt := range tests {
+		cfg := Default()
+		cfg.SetProviders(tt.providers)
+
+		if got := cfg.RequiresAPIKey(); got != tt.want {
+			t.Errorf("RequiresAPIKey() with %q = %v, want %v", tt.providers, got, tt.want)
+		}
+	}
+}
+
+func TestSetUnits(t *testing.T) {
+	tests := []struct {
+		name       string
+		spec       string
+		wantSystem string
+		wantWind   string
+		wantErr    bool
+	}{
+		{"system only", "imperial", "imperial", "", false},
+		{"system with override", "uk, wind=kph", "uk", "kph", false},
+		{"override only", "wind=kn", "", "kn", false},
+		{"invalid override", "metric,wind=furlongs", "metric", "furlongs", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.SetUnits(tt.spec)
+
+			if cfg.UnitSystem != tt.wantSystem {
+				t.Errorf("UnitSystem = %q, want %q", cfg.UnitSystem, tt.wantSystem)
+			}
+			if got := cfg.UnitOverrides["wind"]; got != tt.wantWind {
+				t.Errorf("UnitOverrides[wind] = %q, want %q", got, tt.wantWind)
+			}
+
+			if _, err := cfg.Units(); (err != nil) != tt.wantErr {
+				t.Errorf("Units() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	cfg := Default()
+	if got := cfg.RetryPolicy(); got.MaxAttempts != httpjson.DefaultMaxAttempts || got.BaseDelay != httpjson.DefaultBaseDelay {
+		t.Errorf("default RetryPolicy() = %+v, want httpjson defaults", got)
+	}
+
+	f := &File{}
+	_ = f.Set("retry.attempts", "1")
+	_ = f.Set("retry.max_delay", "2s")
+	cfg.ApplyFile(f)
+
+	got := cfg.RetryPolicy()
+	if got.MaxAttempts != 1 || got.MaxDelay != 2*time.Second || got.BaseDelay != httpjson.DefaultBaseDelay {
+		t.Errorf("RetryPolicy() = %+v, want 1 attempt, 2s max delay, default base delay", got)
+	}
+}
+
+func TestCacheTTLs(t *testing.T) {
+	cfg := Default()
+	if got := cfg.CacheTTLs(); got != (cache.SectionTTLs{}) {
+		t.Errorf("default CacheTTLs() = %+v, want zero values for the cache defaults", got)
+	}
+
+	f := &File{}
+	_ = f.Set("cache.current_ttl", "5m")
+	_ = f.Set("cache.forecast_ttl", "6h")
+	cfg.ApplyFile(f)
+
+	want := cache.SectionTTLs{Current: 5 * time.Minute, Forecast: 6 * time.Hour}
+	if got := cfg.CacheTTLs(); got != want {
+		t.Errorf("CacheTTLs() = %+v, want %+v", got, want)
+	}
+}
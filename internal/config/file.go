@@ -0,0 +1,412 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jtotty/weather-cli/internal/provider"
+	"github.com/jtotty/weather-cli/internal/ui"
+	"github.com/jtotty/weather-cli/internal/units"
+	"github.com/jtotty/weather-cli/internal/weather"
+)
+
+const (
+	configSubDir   = "weather-cli"
+	configFileName = "config.yaml"
+
+	// pathEnvVar overrides the config file location.
+	pathEnvVar = "WEATHER_CONFIG"
+)
+
+// File is the persistent configuration file. Empty fields fall back to the
+// built-in defaults.
+type File struct {
+	Location string    `yaml:"location,omitempty"`
+	Days     int       `yaml:"days,omitempty"`
+	Units    FileUnits `yaml:"units,omitempty"`
+	Sections []string  `yaml:"sections,omitempty"`
+	Provider string    `yaml:"provider,omitempty"`
+	Colors   string    `yaml:"colors,omitempty"`
+	AQI      *bool     `yaml:"aqi,omitempty"`
+	Alerts   *bool     `yaml:"alerts,omitempty"`
+	Retry    FileRetry `yaml:"retry,omitempty"`
+	Cache    FileCache `yaml:"cache,omitempty"`
+
+	Locations map[string]SavedLocation `yaml:"locations,omitempty"`
+}
+
+// FileUnits holds the unit system and per-quantity overrides.
+type FileUnits struct {
+	System   string `yaml:"system,omitempty"`
+	Temp     string `yaml:"temp,omitempty"`
+	Wind     string `yaml:"wind,omitempty"`
+	Pressure string `yaml:"pressure,omitempty"`
+	Precip   string `yaml:"precip,omitempty"`
+	Distance string `yaml:"distance,omitempty"`
+}
+
+// FileRetry tunes retries of transient weatherapi.com failures.
+type FileRetry struct {
+	Attempts int           `yaml:"attempts,omitempty"`
+	Delay    time.Duration `yaml:"delay,omitempty"`
+	MaxDelay time.Duration `yaml:"max_delay,omitempty"`
+}
+
+// FileCache sets how long each section of a cached forecast stays fresh.
+type FileCache struct {
+	CurrentTTL  time.Duration `yaml:"current_ttl,omitempty"`
+	ForecastTTL time.Duration `yaml:"forecast_ttl,omitempty"`
+	AstroTTL    time.Duration `yaml:"astro_ttl,omitempty"`
+}
+
+// Path returns the config file location: $WEATHER_CONFIG if set, otherwise
+// config.yaml in the user config directory ($XDG_CONFIG_HOME on Linux).
+func Path() (string, error) {
+	if path := os.Getenv(pathEnvVar); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, configSubDir, configFileName), nil
+}
+
+// LoadFile reads the config file at path. A missing file is not an error.
+func LoadFile(path string) (*File, error) {
+	f := &File{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	if err := f.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return f, nil
+}
+
+// Save writes the config file atomically, creating its directory.
+func (f *File) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(f); err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	if err := os.Rename(tmpFile, path); err != nil {
+		_ = os.Remove(tmpFile)
+		return fmt.Errorf("failed to rename config file: %w", err)
+	}
+
+	return nil
+}
+
+// Validate checks every set field by round-tripping it through its setter,
+// and every saved location.
+func (f *File) Validate() error {
+	for alias, loc := range f.Locations {
+		if err := (&File{}).AddLocation(alias, loc); err != nil {
+			return fmt.Errorf("location @%s: %w", alias, err)
+		}
+	}
+
+	for _, key := range Keys() {
+		value := fileKeys[key].get(f)
+		if value == "" {
+			continue
+		}
+		if err := fileKeys[key].set(&File{}, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type fileKey struct {
+	get func(f *File) string
+	// set parses and stores value; an empty value clears the key.
+	set func(f *File, value string) error
+}
+
+var fileKeys = map[string]fileKey{
+	"location": {
+		get: func(f *File) string { return f.Location },
+		set: func(f *File, v string) error { f.Location = v; return nil },
+	},
+	"days": {
+		get: func(f *File) string {
+			if f.Days == 0 {
+				return ""
+			}
+			return strconv.Itoa(f.Days)
+		},
+		set: func(f *File, v string) error {
+			if v == "" {
+				f.Days = 0
+				return nil
+			}
+			days, err := parseDays(v)
+			f.Days = days
+			return err
+		},
+	},
+	"units":          unitKey(func(u *FileUnits) *string { return &u.System }, "system"),
+	"units.temp":     unitKey(func(u *FileUnits) *string { return &u.Temp }, "temp"),
+	"units.wind":     unitKey(func(u *FileUnits) *string { return &u.Wind }, "wind"),
+	"units.pressure": unitKey(func(u *FileUnits) *string { return &u.Pressure }, "pressure"),
+	"units.precip":   unitKey(func(u *FileUnits) *string { return &u.Precip }, "precip"),
+	"units.distance": unitKey(func(u *FileUnits) *string { return &u.Distance }, "distance"),
+	"sections": {
+		get: func(f *File) string { return strings.Join(f.Sections, ",") },
+		set: func(f *File, v string) error {
+			sections, err := parseSections(v)
+			f.Sections = sections
+			return err
+		},
+	},
+	"provider": {
+		get: func(f *File) string { return f.Provider },
+		set: func(f *File, v string) error {
+			f.Provider = v
+			return validateProviders(v)
+		},
+	},
+	"colors": {
+		get: func(f *File) string { return f.Colors },
+		set: func(f *File, v string) error {
+			f.Colors = strings.ToLower(v)
+			if v == "" {
+				return nil
+			}
+			_, err := ui.ParseColorMode(v)
+			return err
+		},
+	},
+	"aqi":    boolKey(func(f *File) **bool { return &f.AQI }),
+	"alerts": boolKey(func(f *File) **bool { return &f.Alerts }),
+	"retry.attempts": {
+		get: func(f *File) string {
+			if f.Retry.Attempts == 0 {
+				return ""
+			}
+			return strconv.Itoa(f.Retry.Attempts)
+		},
+		set: func(f *File, v string) error {
+			if v == "" {
+				f.Retry.Attempts = 0
+				return nil
+			}
+			attempts, err := strconv.Atoi(v)
+			if err != nil || attempts < 1 || attempts > maxRetryAttempts {
+				return fmt.Errorf("invalid retry attempts %q: must be between 1 and %d", v, maxRetryAttempts)
+			}
+			f.Retry.Attempts = attempts
+			return nil
+		},
+	},
+	"retry.delay":     durationKey(func(f *File) *time.Duration { return &f.Retry.Delay }),
+	"retry.max_delay": durationKey(func(f *File) *time.Duration { return &f.Retry.MaxDelay }),
+
+	"cache.current_ttl":  durationKey(func(f *File) *time.Duration { return &f.Cache.CurrentTTL }),
+	"cache.forecast_ttl": durationKey(func(f *File) *time.Duration { return &f.Cache.ForecastTTL }),
+	"cache.astro_ttl":    durationKey(func(f *File) *time.Duration { return &f.Cache.AstroTTL }),
+}
+
+// maxRetryAttempts bounds retry.attempts so a misconfiguration cannot keep
+// the CLI retrying for minutes.
+const maxRetryAttempts = 10
+
+func unitKey(field func(u *FileUnits) *string, quantity string) fileKey {
+	return fileKey{
+		get: func(f *File) string { return *field(&f.Units) },
+		set: func(f *File, v string) error {
+			v = strings.ToLower(v)
+			*field(&f.Units) = v
+			if v == "" {
+				return nil
+			}
+			if quantity == "system" {
+				_, err := units.Parse(v, nil)
+				return err
+			}
+			_, err := units.Parse("", map[string]string{quantity: v})
+			return err
+		},
+	}
+}
+
+func boolKey(field func(f *File) **bool) fileKey {
+	return fileKey{
+		get: func(f *File) string {
+			if b := *field(f); b != nil {
+				return strconv.FormatBool(*b)
+			}
+			return ""
+		},
+		set: func(f *File, v string) error {
+			if v == "" {
+				*field(f) = nil
+				return nil
+			}
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("invalid boolean %q", v)
+			}
+			*field(f) = &b
+			return nil
+		},
+	}
+}
+
+func durationKey(field func(f *File) *time.Duration) fileKey {
+	return fileKey{
+		get: func(f *File) string {
+			if d := *field(f); d != 0 {
+				return d.String()
+			}
+			return ""
+		},
+		set: func(f *File, v string) error {
+			if v == "" {
+				*field(f) = 0
+				return nil
+			}
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				return fmt.Errorf("invalid duration %q: use a positive value such as 500ms or 2s", v)
+			}
+			*field(f) = d
+			return nil
+		},
+	}
+}
+
+// Keys returns the settable config keys, sorted.
+func Keys() []string {
+	keys := make([]string, 0, len(fileKeys))
+	for key := range fileKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func lookupKey(key string) (fileKey, error) {
+	k, ok := fileKeys[key]
+	if !ok {
+		return fileKey{}, fmt.Errorf("unknown config key %q (available: %s)", key, strings.Join(Keys(), ", "))
+	}
+	return k, nil
+}
+
+// Get returns the value of key, or "" if it is unset.
+func (f *File) Get(key string) (string, error) {
+	k, err := lookupKey(key)
+	if err != nil {
+		return "", err
+	}
+	return k.get(f), nil
+}
+
+// Set validates and stores value under key.
+func (f *File) Set(key, value string) error {
+	k, err := lookupKey(key)
+	if err != nil {
+		return err
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fmt.Errorf("empty value for %q (use unset to clear it)", key)
+	}
+
+	updated := *f
+	if err := k.set(&updated, value); err != nil {
+		return fmt.Errorf("invalid value for %q: %w", key, err)
+	}
+
+	*f = updated
+	return nil
+}
+
+// Unset clears key so the default applies again.
+func (f *File) Unset(key string) error {
+	k, err := lookupKey(key)
+	if err != nil {
+		return err
+	}
+	return k.set(f, "")
+}
+
+func parseDays(v string) (int, error) {
+	days, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, validateDays(0)
+	}
+	return days, validateDays(days)
+}
+
+func validateDays(days int) error {
+	if days < 1 || days > MaxDays {
+		return fmt.Errorf("days must be a number between 1 and %d", MaxDays)
+	}
+	return nil
+}
+
+func parseSections(v string) ([]string, error) {
+	var sections []string
+	for _, name := range strings.Split(v, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !weather.IsSection(name) {
+			return nil, fmt.Errorf("unknown section %q (available: %s)", name, strings.Join(weather.Sections, ", "))
+		}
+		sections = append(sections, name)
+	}
+	return sections, nil
+}
+
+func validateProviders(list string) error {
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if err := provider.Validate(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
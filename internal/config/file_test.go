@@ -0,0 +1,233 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Keep tests away from the real user config file.
+	dir, err := os.MkdirTemp("", "weather-cli-config")
+	if err != nil {
+		panic(err)
+	}
+	if err := os.Setenv(pathEnvVar, filepath.Join(dir, "config.yaml")); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestFile_SetGetUnset(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+		want  string
+	}{
+		{"location", "New York", "New York"},
+		{"days", "3", "3"},
+		{"units", "Imperial", "imperial"},
+		{"units.wind", "kph", "kph"},
+		{"sections", "current, daily", "current,daily"},
+		{"provider", "open-meteo,nws", "open-meteo,nws"},
+		{"colors", "never", "never"},
+		{"aqi", "false", "false"},
+		{"retry.attempts", "5", "5"},
+		{"retry.delay", "250ms", "250ms"},
+		{"retry.max_delay", "30s", "30s"},
+		{"cache.current_ttl", "10m", "10m0s"},
+		{"cache.astro_ttl", "12h", "12h0m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			f := &File{}
+			if err := f.Set(tt.key, tt.value); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+
+			got, err := f.Get(tt.key)
+			if err != nil || got != tt.want {
+				t.Errorf("Get() = %q, %v; want %q", got, err, tt.want)
+			}
+
+			if err := f.Unset(tt.key); err != nil {
+				t.Fatalf("Unset() error = %v", err)
+			}
+			if got, _ := f.Get(tt.key); got != "" {
+				t.Errorf("Get() after Unset = %q, want empty", got)
+			}
+		})
+	}
+}
+
+func TestFile_SetInvalid(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		wantErr string
+	}{
+		{"color", "never", "unknown config key"},
+		{"days", "0", "between 1"},
+		{"days", "many", "between 1"},
+		{"units", "furlongs", "unknown unit system"},
+		{"units.wind", "furlongs", "unknown wind unit"},
+		{"sections", "current,radar", "unknown section"},
+		{"provider", "accuweather", "unknown provider"},
+		{"colors", "sometimes", "unknown color mode"},
+		{"alerts", "maybe", "invalid boolean"},
+		{"location", " ", "empty value"},
+		{"retry.attempts", "0", "between 1"},
+		{"retry.attempts", "50", "between 1"},
+		{"retry.delay", "soon", "invalid duration"},
+		{"retry.max_delay", "-1s", "invalid duration"},
+		{"cache.forecast_ttl", "0s", "invalid duration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			f := &File{Days: 5}
+			err := f.Set(tt.key, tt.value)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Set() error = %v, want error containing %q", err, tt.wantErr)
+			}
+			if f.Days != 5 {
+				t.Errorf("failed Set() modified the file: Days = %d", f.Days)
+			}
+		})
+	}
+}
+
+func TestFile_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.yaml")
+
+	f := &File{}
+	_ = f.Set("location", "Oslo")
+	_ = f.Set("units.temp", "f")
+	_ = f.Set("alerts", "false")
+
+	if err := f.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, f) {
+		t.Errorf("LoadFile() = %+v, want %+v", got, f)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("missing file", func(t *testing.T) {
+		f, err := LoadFile(filepath.Join(dir, "missing.yaml"))
+		if err != nil || !reflect.DeepEqual(f, &File{}) {
+			t.Errorf("LoadFile() = %+v, %v; want empty file", f, err)
+		}
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		path := filepath.Join(dir, "bad.yaml")
+		if err := os.WriteFile(path, []byte("days: 99\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil {
+			t.Error("LoadFile() should reject out-of-range days")
+		}
+	})
+
+	t.Run("invalid yaml", func(t *testing.T) {
+		path := filepath.Join(dir, "broken.yaml")
+		if err := os.WriteFile(path, []byte("days: [\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil {
+			t.Error("LoadFile() should reject malformed YAML")
+		}
+	})
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv(pathEnvVar, path)
+
+	f := &File{}
+	_ = f.Set("location", "Oslo")
+	_ = f.Set("days", "3")
+	_ = f.Set("units", "imperial")
+	if err := f.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("WEATHER_DAYS", "5")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Location != "Oslo" || cfg.IsLocal {
+		t.Errorf("Location = %q, IsLocal = %v; want file location", cfg.Location, cfg.IsLocal)
+	}
+	if cfg.Days != 5 {
+		t.Errorf("Days = %d, want environment value 5", cfg.Days)
+	}
+	if cfg.UnitSystem != "imperial" {
+		t.Errorf("UnitSystem = %q, want imperial", cfg.UnitSystem)
+	}
+	if !cfg.IncludeAQI {
+		t.Error("IncludeAQI should keep its default")
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"WEATHER_LOCATION": "Paris",
+		"WEATHER_UNITS":    "metric,wind=kn",
+		"WEATHER_SECTIONS": "current",
+		"WEATHER_PROVIDER": "nws",
+		"WEATHER_COLORS":   "always",
+		"WEATHER_ALERTS":   "false",
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	cfg := Default()
+	if err := cfg.ApplyEnv(lookup); err != nil {
+		t.Fatalf("ApplyEnv() error = %v", err)
+	}
+
+	if cfg.Location != "Paris" || cfg.UnitSystem != "metric" || cfg.UnitOverrides["wind"] != "kn" {
+		t.Errorf("ApplyEnv() location/units = %q/%q/%v", cfg.Location, cfg.UnitSystem, cfg.UnitOverrides)
+	}
+	if !reflect.DeepEqual(cfg.Sections, []string{"current"}) || cfg.Colors != "always" || cfg.Alerts {
+		t.Errorf("ApplyEnv() sections/colors/alerts = %v/%q/%v", cfg.Sections, cfg.Colors, cfg.Alerts)
+	}
+	if !reflect.DeepEqual(cfg.ProviderChain(), []string{"nws"}) {
+		t.Errorf("ProviderChain() = %v, want [nws]", cfg.ProviderChain())
+	}
+
+	env = map[string]string{"WEATHER_DAYS": "zero"}
+	if err := Default().ApplyEnv(lookup); err == nil || !strings.Contains(err.Error(), "WEATHER_DAYS") {
+		t.Errorf("ApplyEnv() error = %v, want WEATHER_DAYS error", err)
+	}
+}
+
+func TestPath_EnvOverride(t *testing.T) {
+	t.Setenv(pathEnvVar, "/tmp/custom.yaml")
+
+	path, err := Path()
+	if err != nil || path != "/tmp/custom.yaml" {
+		t.Errorf("Path() = %q, %v; want override", path, err)
+	}
+}
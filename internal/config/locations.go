@@ -0,0 +1,175 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jtotty/weather-cli/internal/api/geocode"
+)
+
+// AliasPrefix marks a location argument as a saved location, as in "@home".
+const AliasPrefix = "@"
+
+var aliasPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// SavedLocation is a named location stored in the config file. Units and
+// Days, when set, override the global settings for this location.
+type SavedLocation struct {
+	Name  string  `yaml:"name"`
+	Lat   float64 `yaml:"lat"`
+	Lon   float64 `yaml:"lon"`
+	Units string  `yaml:"units,omitempty"`
+	Days  int     `yaml:"days,omitempty"`
+}
+
+// Coordinates returns the location as a "lat,lon" query.
+func (l SavedLocation) Coordinates() string {
+	return geocode.FormatCoordinates(l.Lat, l.Lon)
+}
+
+func (l SavedLocation) validate() error {
+	if l.Lat < -90 || l.Lat > 90 || l.Lon < -180 || l.Lon > 180 {
+		return fmt.Errorf("coordinates %s are out of range", l.Coordinates())
+	}
+	if l.Days != 0 {
+		if err := validateDays(l.Days); err != nil {
+			return err
+		}
+	}
+	if l.Units != "" {
+		cfg := &Config{}
+		cfg.SetUnits(l.Units)
+		if _, err := cfg.Units(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddLocation saves loc under alias, replacing any existing entry.
+func (f *File) AddLocation(alias string, loc SavedLocation) error {
+	alias = strings.TrimPrefix(alias, AliasPrefix)
+	if !aliasPattern.MatchString(alias) {
+		return fmt.Errorf("invalid alias %q: use letters, digits, - and _", alias)
+	}
+	if err := loc.validate(); err != nil {
+		return err
+	}
+	if loc.Name == "" {
+		loc.Name = alias
+	}
+
+	if f.Locations == nil {
+		f.Locations = make(map[string]SavedLocation)
+	}
+	f.Locations[alias] = loc
+	return nil
+}
+
+// RemoveLocation deletes the saved location alias.
+func (f *File) RemoveLocation(alias string) error {
+	alias = strings.TrimPrefix(alias, AliasPrefix)
+	if _, err := lookupLocation(f.Locations, alias); err != nil {
+		return err
+	}
+
+	delete(f.Locations, alias)
+	if len(f.Locations) == 0 {
+		f.Locations = nil
+	}
+	return nil
+}
+
+// RenameLocation moves a saved location to a new alias.
+func (f *File) RenameLocation(from, to string) error {
+	from = strings.TrimPrefix(from, AliasPrefix)
+	to = strings.TrimPrefix(to, AliasPrefix)
+
+	loc, err := lookupLocation(f.Locations, from)
+	if err != nil {
+		return err
+	}
+	if _, exists := f.Locations[to]; exists {
+		return fmt.Errorf("location @%s already exists", to)
+	}
+
+	if err := f.AddLocation(to, loc); err != nil {
+		return err
+	}
+	delete(f.Locations, from)
+	return nil
+}
+
+// LocationAliases returns the saved aliases, sorted.
+func (f *File) LocationAliases() []string {
+	return sortedAliases(f.Locations)
+}
+
+// LocationAliases returns the saved aliases, sorted.
+func (c *Config) LocationAliases() []string {
+	return sortedAliases(c.Locations)
+}
+
+// ResolveAlias replaces an "@alias" location with the saved location's
+// coordinates and applies its preferences. It runs after the location is
+// final and before command-line flags, so flags still win. Other locations
+// are left unchanged.
+func (c *Config) ResolveAlias() error {
+	alias, ok := strings.CutPrefix(c.Location, AliasPrefix)
+	if !ok {
+		return nil
+	}
+
+	loc, err := lookupLocation(c.Locations, alias)
+	if err != nil {
+		return err
+	}
+
+	c.SetLocation(loc.Coordinates())
+	c.LocationName = loc.Name
+	if loc.Units != "" {
+		c.SetUnits(loc.Units)
+	}
+	if loc.Days != 0 {
+		c.Days = loc.Days
+	}
+	return nil
+}
+
+// ExpandAlias returns the coordinates and display name for an "@alias"
+// query. Other queries are returned unchanged with an empty name. Unlike
+// ResolveAlias, the saved location's preferences are not applied.
+func (c *Config) ExpandAlias(query string) (location, name string, err error) {
+	alias, ok := strings.CutPrefix(query, AliasPrefix)
+	if !ok {
+		return query, "", nil
+	}
+
+	loc, err := lookupLocation(c.Locations, alias)
+	if err != nil {
+		return "", "", err
+	}
+	return loc.Coordinates(), loc.Name, nil
+}
+
+func lookupLocation(locations map[string]SavedLocation, alias string) (SavedLocation, error) {
+	if loc, ok := locations[alias]; ok {
+		return loc, nil
+	}
+
+	if len(locations) == 0 {
+		return SavedLocation{}, fmt.Errorf("unknown location @%s (no saved locations; add one with 'weather-cli locations add')", alias)
+	}
+	return SavedLocation{}, fmt.Errorf("unknown location @%s (saved: @%s)", alias, strings.Join(sortedAliases(locations), ", @"))
+}
+
+func sortedAliases(locations map[string]SavedLocation) []string {
+	aliases := make([]string, 0, len(locations))
+	for alias := range locations {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFile_Locations(t *testing.T) {
+	f := &File{}
+
+	if err := f.AddLocation("@home", SavedLocation{Lat: 51.5, Lon: -0.1, Units: "metric"}); err != nil {
+		t.Fatalf("AddLocation() error = %v", err)
+	}
+	if got := f.Locations["home"].Name; got != "home" {
+		t.Errorf("Name = %q, want alias as default name", got)
+	}
+
+	if err := f.AddLocation("work", SavedLocation{Name: "Office", Lat: 53.8, Lon: -1.55}); err != nil {
+		t.Fatalf("AddLocation() error = %v", err)
+	}
+	if err := f.RenameLocation("work", "@office"); err != nil {
+		t.Fatalf("RenameLocation() error = %v", err)
+	}
+	if got := strings.Join(f.LocationAliases(), ","); got != "home,office" {
+		t.Errorf("LocationAliases() = %q, want home,office", got)
+	}
+
+	if err := f.RenameLocation("home", "office"); err == nil {
+		t.Error("RenameLocation() onto an existing alias should fail")
+	}
+
+	if err := f.RemoveLocation("home"); err != nil {
+		t.Fatalf("RemoveLocation() error = %v", err)
+	}
+	if err := f.RemoveLocation("home"); err == nil || !strings.Contains(err.Error(), "saved: @office") {
+		t.Errorf("RemoveLocation() error = %v, want unknown location listing saved aliases", err)
+	}
+}
+
+func TestFile_AddLocationInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		alias   string
+		loc     SavedLocation
+		wantErr string
+	}{
+		{"bad alias", "my home", SavedLocation{}, "invalid alias"},
+		{"bad coordinates", "pole", SavedLocation{Lat: 91}, "out of range"},
+		{"bad days", "home", SavedLocation{Days: 40}, "days must be"},
+		{"bad units", "home", SavedLocation{Units: "metric,wind=furlongs"}, "unknown wind unit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&File{}).AddLocation(tt.alias, tt.loc)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("AddLocation() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveAlias(t *testing.T) {
+	cfg := Default()
+	cfg.Locations = map[string]SavedLocation{
+		"home": {Name: "Home", Lat: 51.5, Lon: -0.1, Units: "imperial", Days: 3},
+	}
+
+	cfg.SetLocation("@home")
+	if err := cfg.ResolveAlias(); err != nil {
+		t.Fatalf("ResolveAlias() error = %v", err)
+	}
+
+	if cfg.Location != "51.5000,-0.1000" || cfg.LocationName != "Home" || cfg.IsLocal {
+		t.Errorf("location = %q (%q), IsLocal = %v", cfg.Location, cfg.LocationName, cfg.IsLocal)
+	}
+	if cfg.UnitSystem != "imperial" || cfg.Days != 3 {
+		t.Errorf("preferences not applied: units = %q, days = %d", cfg.UnitSystem, cfg.Days)
+	}
+
+	cfg.SetLocation("Paris")
+	if err := cfg.ResolveAlias(); err != nil || cfg.Location != "Paris" || cfg.LocationName != "" {
+		t.Errorf("ResolveAlias() changed a plain location: %q (%q), %v", cfg.Location, cfg.LocationName, err)
+	}
+
+	cfg.SetLocation("@cottage")
+	if err := cfg.ResolveAlias(); err == nil || !strings.Contains(err.Error(), "unknown location @cottage") {
+		t.Errorf("ResolveAlias() error = %v, want unknown location", err)
+	}
+}
+
+func TestExpandAlias(t *testing.T) {
+	cfg := Default()
+	cfg.Locations = map[string]SavedLocation{"home": {Name: "Home", Lat: 51.5, Lon: -0.1, Units: "imperial"}}
+
+	location, name, err := cfg.ExpandAlias("@home")
+	if err != nil || location != "51.5000,-0.1000" || name != "Home" {
+		t.Errorf("ExpandAlias(@home) = %q, %q, %v", location, name, err)
+	}
+	if cfg.UnitSystem != "" {
+		t.Error("ExpandAlias() should not apply preferences")
+	}
+
+	if location, name, err := cfg.ExpandAlias("Paris"); err != nil || location != "Paris" || name != "" {
+		t.Errorf("ExpandAlias(Paris) = %q, %q, %v", location, name, err)
+	}
+
+	if _, _, err := cfg.ExpandAlias("@cottage"); err == nil {
+		t.Error("ExpandAlias() should fail for an unknown alias")
+	}
+}
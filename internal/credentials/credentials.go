@@ -47,6 +47,12 @@ func GetAPIKey() (string, error) {
 	return "", ErrNoAPIKey
 }
 
+// KeyFromEnv reports whether the API key comes from WEATHER_API_KEY rather
+// than the keyring.
+func KeyFromEnv() bool {
+	return os.Getenv(envVarName) != ""
+}
+
 func SetAPIKey(key string) error {
 	key = strings.TrimSpace(key)
 	if key == "" {
@@ -0,0 +1,196 @@
+// Package forecast defines the provider-neutral weather model every
+// provider's response is mapped into, and the options for requesting it.
+package forecast
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrLocationNotFound reports that a provider knows no place matching the
+// requested location. Match it with errors.Is.
+var ErrLocationNotFound = errors.New("no matching location found")
+
+// Options describes a forecast request.
+type Options struct {
+	Location   string
+	Days       int
+	IncludeAQI bool
+	Alerts     bool
+}
+
+// Response is a forecast for one place. Its JSON form is the cache format,
+// not any provider's wire format.
+//
+// Temperatures, the condition and whether it is day are reported by every
+// provider and are plain values. Every other numeric or true/false reading
+// is a pointer, nil when the provider did not report it, in Current, Summary
+// and Hour alike; text readings are empty instead.
+type Response struct {
+	Location Location `json:"location"`
+	Current  Current  `json:"current"`
+	Days     []Day    `json:"days"`
+	Alerts   []Alert  `json:"alerts,omitempty"`
+
+	// Provider records which backend produced the data. It is set by the
+	// service, not decoded from any API.
+	Provider string `json:"provider,omitempty"`
+
+	// Stale marks data served from an expired cache entry because no
+	// provider could be reached, and FetchedAt records when it was
+	// fetched. Both are set by the service and never cached.
+	Stale     bool      `json:"-"`
+	FetchedAt time.Time `json:"-"`
+}
+
+type Location struct {
+	Name    string  `json:"name"`
+	Region  string  `json:"region"`
+	Country string  `json:"country"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+
+	// TimeZone is the IANA time zone, empty when the provider gave none.
+	TimeZone string `json:"time_zone"`
+	// LocalTime is the wall clock at the location when the forecast was
+	// fetched, as "2006-01-02 15:04".
+	LocalTime string `json:"local_time"`
+}
+
+type Current struct {
+	TempC     float32   `json:"temp_c"`
+	TempF     float32   `json:"temp_f"`
+	IsDay     bool      `json:"is_day"`
+	Condition Condition `json:"condition"`
+
+	// Optional readings.
+	FeelsLikeC    *float32    `json:"feels_like_c,omitempty"`
+	FeelsLikeF    *float32    `json:"feels_like_f,omitempty"`
+	WindChillC    *float32    `json:"wind_chill_c,omitempty"`
+	WindChillF    *float32    `json:"wind_chill_f,omitempty"`
+	HeatIndexC    *float32    `json:"heat_index_c,omitempty"`
+	HeatIndexF    *float32    `json:"heat_index_f,omitempty"`
+	DewPointC     *float32    `json:"dew_point_c,omitempty"`
+	DewPointF     *float32    `json:"dew_point_f,omitempty"`
+	Humidity      *float32    `json:"humidity,omitempty"`
+	Cloud         *float32    `json:"cloud,omitempty"`
+	WindMph       *float32    `json:"wind_mph,omitempty"`
+	WindKph       *float32    `json:"wind_kph,omitempty"`
+	WindDegree    *int        `json:"wind_degree,omitempty"`
+	WindDirection string      `json:"wind_direction,omitempty"`
+	GustMph       *float32    `json:"gust_mph,omitempty"`
+	GustKph       *float32    `json:"gust_kph,omitempty"`
+	PressureMb    *float32    `json:"pressure_mb,omitempty"`
+	PressureIn    *float32    `json:"pressure_in,omitempty"`
+	PrecipMm      *float32    `json:"precip_mm,omitempty"`
+	PrecipIn      *float32    `json:"precip_in,omitempty"`
+	VisKm         *float32    `json:"vis_km,omitempty"`
+	VisMiles      *float32    `json:"vis_miles,omitempty"`
+	UV            *float32    `json:"uv,omitempty"`
+	AirQuality    *AirQuality `json:"air_quality,omitempty"`
+}
+
+type Condition struct {
+	Text string `json:"text"`
+	// Code is the provider's own code for the condition, 0 when it has none.
+	Code int `json:"code,omitempty"`
+}
+
+type AirQuality struct {
+	PM25 float32 `json:"pm2_5"`
+	PM10 float32 `json:"pm10"`
+}
+
+// Day is one forecast day: its summary, its hours and its sun and moon.
+type Day struct {
+	Date       string      `json:"date"`
+	Summary    Summary     `json:"summary"`
+	Hours      []Hour      `json:"hours"`
+	AirQuality *AirQuality `json:"air_quality,omitempty"`
+	Astro      Astro       `json:"astro"`
+}
+
+// Summary holds the readings for a whole day.
+type Summary struct {
+	MaxTempC  float32   `json:"max_temp_c"`
+	MaxTempF  float32   `json:"max_temp_f"`
+	MinTempC  float32   `json:"min_temp_c"`
+	MinTempF  float32   `json:"min_temp_f"`
+	AvgTempC  float32   `json:"avg_temp_c"`
+	AvgTempF  float32   `json:"avg_temp_f"`
+	Condition Condition `json:"condition"`
+
+	// Optional readings.
+	MaxWindMph    *float32 `json:"max_wind_mph,omitempty"`
+	MaxWindKph    *float32 `json:"max_wind_kph,omitempty"`
+	ChanceOfRain  *float32 `json:"chance_of_rain,omitempty"`
+	ChanceOfSnow  *float32 `json:"chance_of_snow,omitempty"`
+	TotalPrecipMm *float32 `json:"total_precip_mm,omitempty"`
+	TotalPrecipIn *float32 `json:"total_precip_in,omitempty"`
+	TotalSnowCm   *float32 `json:"total_snow_cm,omitempty"`
+	AvgVisKm      *float32 `json:"avg_vis_km,omitempty"`
+	AvgVisMiles   *float32 `json:"avg_vis_miles,omitempty"`
+	AvgHumidity   *float32 `json:"avg_humidity,omitempty"`
+	UV            *float32 `json:"uv,omitempty"`
+}
+
+type Hour struct {
+	// TimeUnix is the start of the hour in seconds since the Unix epoch.
+	TimeUnix  int64     `json:"time_unix"`
+	TempC     float32   `json:"temp_c"`
+	TempF     float32   `json:"temp_f"`
+	IsDay     bool      `json:"is_day"`
+	Condition Condition `json:"condition"`
+
+	// Optional readings.
+	FeelsLikeC    *float32 `json:"feels_like_c,omitempty"`
+	FeelsLikeF    *float32 `json:"feels_like_f,omitempty"`
+	WindChillC    *float32 `json:"wind_chill_c,omitempty"`
+	WindChillF    *float32 `json:"wind_chill_f,omitempty"`
+	HeatIndexC    *float32 `json:"heat_index_c,omitempty"`
+	HeatIndexF    *float32 `json:"heat_index_f,omitempty"`
+	DewPointC     *float32 `json:"dew_point_c,omitempty"`
+	DewPointF     *float32 `json:"dew_point_f,omitempty"`
+	Humidity      *float32 `json:"humidity,omitempty"`
+	Cloud         *float32 `json:"cloud,omitempty"`
+	WindMph       *float32 `json:"wind_mph,omitempty"`
+	WindKph       *float32 `json:"wind_kph,omitempty"`
+	WindDegree    *int     `json:"wind_degree,omitempty"`
+	WindDirection string   `json:"wind_direction,omitempty"`
+	GustMph       *float32 `json:"gust_mph,omitempty"`
+	GustKph       *float32 `json:"gust_kph,omitempty"`
+	PressureMb    *float32 `json:"pressure_mb,omitempty"`
+	PressureIn    *float32 `json:"pressure_in,omitempty"`
+	PrecipMm      *float32 `json:"precip_mm,omitempty"`
+	PrecipIn      *float32 `json:"precip_in,omitempty"`
+	SnowCm        *float32 `json:"snow_cm,omitempty"`
+	ChanceOfRain  *float32 `json:"chance_of_rain,omitempty"`
+	ChanceOfSnow  *float32 `json:"chance_of_snow,omitempty"`
+	VisKm         *float32 `json:"vis_km,omitempty"`
+	VisMiles      *float32 `json:"vis_miles,omitempty"`
+	UV            *float32 `json:"uv,omitempty"`
+}
+
+// Astro holds a day's sun and moon times, as "03:04 PM" wall-clock times.
+type Astro struct {
+	Sunrise   string `json:"sunrise,omitempty"`
+	Sunset    string `json:"sunset,omitempty"`
+	Moonrise  string `json:"moonrise,omitempty"`
+	Moonset   string `json:"moonset,omitempty"`
+	MoonPhase string `json:"moon_phase,omitempty"`
+
+	// Optional readings.
+	MoonIllumination *int  `json:"moon_illumination,omitempty"`
+	IsSunUp          *bool `json:"is_sun_up,omitempty"`
+	IsMoonUp         *bool `json:"is_moon_up,omitempty"`
+}
+
+type Alert struct {
+	Event       string `json:"event"`
+	Description string `json:"description"`
+}
+
+// Ptr returns a pointer to v, for filling optional readings.
+func Ptr[T any](v T) *T {
+	return &v
+}
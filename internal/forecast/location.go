@@ -0,0 +1,16 @@
+package forecast
+
+import "time"
+
+// Zone loads the location's IANA time zone, reporting false when the
+// provider gave none or it is unknown to this system.
+func (l Location) Zone() (*time.Location, bool) {
+	if l.TimeZone == "" {
+		return nil, false
+	}
+	zone, err := time.LoadLocation(l.TimeZone)
+	if err != nil {
+		return nil, false
+	}
+	return zone, true
+}
@@ -0,0 +1,24 @@
+package forecast
+
+import "testing"
+
+func TestLocationZone(t *testing.T) {
+	tests := []struct {
+		tzID   string
+		wantOK bool
+	}{
+		{"Asia/Tokyo", true},
+		{"", false},
+		{"Not/A_Zone", false},
+	}
+
+	for _, tt := range tests {
+		zone, ok := Location{TimeZone: tt.tzID}.Zone()
+		if ok != tt.wantOK {
+			t.Errorf("Zone(%q) ok = %v, want %v", tt.tzID, ok, tt.wantOK)
+		}
+		if ok && zone.String() != tt.tzID {
+			t.Errorf("Zone(%q) = %v", tt.tzID, zone)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package forecast
+
+import (
+	"math"
+
+	"github.com/jtotty/weather-cli/internal/units"
+)
+
+var compassPoints = []string{
+	"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+	"S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW",
+}
+
+// CompassDirection converts a wind bearing in degrees to a 16-point
+// compass abbreviation such as "NNE".
+func CompassDirection(degrees float64) string {
+	idx := int(math.Round(math.Mod(degrees, 360)/22.5)) % len(compassPoints)
+	if idx < 0 {
+		idx += len(compassPoints)
+	}
+	return compassPoints[idx]
+}
+
+// FillUnitVariants derives the imperial fields (°F, km/h, inches, miles)
+// from their metric counterparts. Adapters for providers that report a
+// single system call this once the metric fields and mph wind speeds are
+// populated. Unreported readings stay nil.
+func (r *Response) FillUnitVariants() {
+	c := &r.Current
+	c.TempF = units.CelsiusToFahrenheit(c.TempC)
+	c.FeelsLikeF = convert(c.FeelsLikeC, units.CelsiusToFahrenheit)
+	c.WindChillF = convert(c.WindChillC, units.CelsiusToFahrenheit)
+	c.HeatIndexF = convert(c.HeatIndexC, units.CelsiusToFahrenheit)
+	c.DewPointF = convert(c.DewPointC, units.CelsiusToFahrenheit)
+	c.WindKph = convert(c.WindMph, units.MphToKph)
+	c.GustKph = convert(c.GustMph, units.MphToKph)
+	c.PressureIn = convert(c.PressureMb, units.MillibarsToInches)
+	c.PrecipIn = convert(c.PrecipMm, units.MillimetersToInches)
+	c.VisMiles = convert(c.VisKm, units.KilometersToMiles)
+
+	for i := range r.Days {
+		day := &r.Days[i]
+
+		d := &day.Summary
+		d.MaxTempF = units.CelsiusToFahrenheit(d.MaxTempC)
+		d.MinTempF = units.CelsiusToFahrenheit(d.MinTempC)
+		d.AvgTempF = units.CelsiusToFahrenheit(d.AvgTempC)
+		d.MaxWindKph = convert(d.MaxWindMph, units.MphToKph)
+		d.TotalPrecipIn = convert(d.TotalPrecipMm, units.MillimetersToInches)
+		d.AvgVisMiles = convert(d.AvgVisKm, units.KilometersToMiles)
+
+		for j := range day.Hours {
+			day.Hours[j].fillUnitVariants()
+		}
+	}
+}
+
+func (h *Hour) fillUnitVariants() {
+	h.TempF = units.CelsiusToFahrenheit(h.TempC)
+	h.FeelsLikeF = convert(h.FeelsLikeC, units.CelsiusToFahrenheit)
+	h.WindChillF = convert(h.WindChillC, units.CelsiusToFahrenheit)
+	h.HeatIndexF = convert(h.HeatIndexC, units.CelsiusToFahrenheit)
+	h.DewPointF = convert(h.DewPointC, units.CelsiusToFahrenheit)
+	h.WindKph = convert(h.WindMph, units.MphToKph)
+	h.GustKph = convert(h.GustMph, units.MphToKph)
+	h.PressureIn = convert(h.PressureMb, units.MillibarsToInches)
+	h.PrecipIn = convert(h.PrecipMm, units.MillimetersToInches)
+	h.VisMiles = convert(h.VisKm, units.KilometersToMiles)
+}
+
+// convert applies f to an optional reading, leaving an unreported one nil.
+func convert(v *float32, f func(float32) float32) *float32 {
+	if v == nil {
+		return nil
+	}
+	return Ptr(f(*v))
+}
@@ -0,0 +1,69 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompassDirection(t *testing.T) {
+	tests := []struct {
+		degrees float64
+		want    string
+	}{
+		{0, "N"},
+		{10, "N"},
+		{12, "NNE"},
+		{90, "E"},
+		{225, "SW"},
+		{350, "N"},
+		{360, "N"},
+		{-90, "W"},
+	}
+
+	for _, tt := range tests {
+		if got := CompassDirection(tt.degrees); got != tt.want {
+			t.Errorf("CompassDirection(%v) = %q, want %q", tt.degrees, got, tt.want)
+		}
+	}
+}
+
+func TestFillUnitVariants(t *testing.T) {
+	r := &Response{
+		Current: Current{TempC: 100, FeelsLikeC: Ptr[float32](0), WindMph: Ptr[float32](10), PrecipMm: Ptr[float32](25.4), VisKm: Ptr[float32](10)},
+		Days: []Day{{
+			Summary: Summary{MaxTempC: 20, MinTempC: -40, MaxWindMph: Ptr[float32](5), TotalPrecipMm: Ptr[float32](2.54)},
+			Hours:   []Hour{{TempC: 37, DewPointC: Ptr[float32](10), GustMph: Ptr[float32](10), PressureMb: Ptr[float32](1013.25)}},
+		}},
+	}
+
+	r.FillUnitVariants()
+
+	near := func(got *float32, want float32) bool {
+		return got != nil && math.Abs(float64(*got-want)) < 0.01
+	}
+
+	c := r.Current
+	if !near(&c.TempF, 212) || !near(c.FeelsLikeF, 32) {
+		t.Errorf("Current temps °F = %v/%v, want 212/32", c.TempF, c.FeelsLikeF)
+	}
+	if !near(c.WindKph, 16.09) || !near(c.PrecipIn, 1) || !near(c.VisMiles, 6.21) {
+		t.Errorf("Current = %+v", c)
+	}
+	if c.DewPointF != nil || c.HeatIndexF != nil || c.WindChillF != nil {
+		t.Error("unreported dew point, heat index and wind chill should stay nil, not become 32°F")
+	}
+	if c.GustKph != nil || c.PressureIn != nil {
+		t.Error("unreported gusts and pressure should stay nil, not become 0")
+	}
+
+	day := r.Days[0]
+	if s := day.Summary; !near(&s.MaxTempF, 68) || !near(&s.MinTempF, -40) || !near(s.MaxWindKph, 8.05) || !near(s.TotalPrecipIn, 0.1) {
+		t.Errorf("Summary = %+v", s)
+	}
+	if day.Summary.AvgVisMiles != nil {
+		t.Error("unreported visibility should stay nil")
+	}
+	if h := day.Hours[0]; !near(&h.TempF, 98.6) || !near(h.DewPointF, 50) || !near(h.GustKph, 16.09) || !near(h.PressureIn, 29.92) || h.WindKph != nil {
+		t.Errorf("Hour = %+v", h)
+	}
+}
@@ -0,0 +1,70 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+// Comparison is the structured output of "weather-cli compare". Each entry
+// carries either a report or the error that location failed with.
+type Comparison struct {
+	SchemaVersion int                `json:"schema_version" yaml:"schema_version"`
+	Locations     []ComparedLocation `json:"locations" yaml:"locations"`
+}
+
+type ComparedLocation struct {
+	Query  string  `json:"query" yaml:"query"`
+	Report *Report `json:"report,omitempty" yaml:"report,omitempty"`
+	Error  string  `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Add appends the result for one location.
+func (c *Comparison) Add(query string, data *forecast.Response, err error) {
+	entry := ComparedLocation{Query: query}
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Report = NewReport(data)
+	}
+	c.Locations = append(c.Locations, entry)
+}
+
+// NewComparison returns an empty comparison.
+func NewComparison() *Comparison {
+	return &Comparison{SchemaVersion: SchemaVersion, Locations: []ComparedLocation{}}
+}
+
+// RenderComparison writes a comparison as JSON, YAML or NDJSON. NDJSON
+// emits each report's records in turn and an "error" record per failure.
+func RenderComparison(w io.Writer, format Format, c *Comparison) error {
+	switch format {
+	case FormatJSON:
+		return JSON(w, c)
+	case FormatYAML:
+		return YAML(w, c)
+	case FormatNDJSON:
+		for _, loc := range c.Locations {
+			if loc.Report != nil {
+				if err := NDJSON(w, loc.Report); err != nil {
+					return err
+				}
+				continue
+			}
+			err := json.NewEncoder(w).Encode(record{
+				SchemaVersion: c.SchemaVersion,
+				Type:          "error",
+				Location:      loc.Query,
+				Data:          loc.Error,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("format %q is not supported for compare (use json, yaml or ndjson)", format)
+	}
+}
@@ -0,0 +1,68 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+func TestRenderComparison_JSON(t *testing.T) {
+	c := NewComparison()
+	c.Add("London", &forecast.Response{Location: forecast.Location{Name: "London"}}, nil)
+	c.Add("Atlantis", nil, errors.New("no matching location found"))
+
+	var buf bytes.Buffer
+	if err := RenderComparison(&buf, FormatJSON, c); err != nil {
+		t.Fatalf("RenderComparison() error = %v", err)
+	}
+
+	var got Comparison
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	if got.SchemaVersion != SchemaVersion || len(got.Locations) != 2 {
+		t.Fatalf("comparison = %+v", got)
+	}
+	if loc := got.Locations[0]; loc.Report == nil || loc.Report.Location.Name != "London" || loc.Error != "" {
+		t.Errorf("first location = %+v, want London report", loc)
+	}
+	if loc := got.Locations[1]; loc.Report != nil || loc.Error != "no matching location found" {
+		t.Errorf("second location = %+v, want error only", loc)
+	}
+}
+
+func TestRenderComparison_NDJSON(t *testing.T) {
+	c := NewComparison()
+	c.Add("Atlantis", nil, errors.New("no matching location found"))
+
+	var buf bytes.Buffer
+	if err := RenderComparison(&buf, FormatNDJSON, c); err != nil {
+		t.Fatalf("RenderComparison() error = %v", err)
+	}
+
+	var rec struct {
+		Type     string `json:"type"`
+		Location string `json:"location"`
+		Data     string `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("line is not valid JSON: %q", buf.String())
+	}
+	if rec.Type != "error" || rec.Location != "Atlantis" || rec.Data != "no matching location found" {
+		t.Errorf("record = %+v", rec)
+	}
+}
+
+func TestRenderComparison_UnsupportedFormat(t *testing.T) {
+	for _, format := range []Format{FormatText, FormatCSV} {
+		err := RenderComparison(&bytes.Buffer{}, format, NewComparison())
+		if err == nil || !strings.Contains(err.Error(), "not supported") {
+			t.Errorf("RenderComparison(%s) error = %v, want not supported", format, err)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	FormatText   Format = "text"
+	FormatJSON   Format = "json"
+	FormatYAML   Format = "yaml"
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+var formats = []Format{FormatText, FormatJSON, FormatYAML, FormatCSV, FormatNDJSON}
+
+// Table selects which forecast table CSV output contains.
+type Table string
+
+const (
+	TableHourly Table = "hourly"
+	TableDaily  Table = "daily"
+)
+
+// ParseFormat validates a --format value. An empty value selects text.
+func ParseFormat(s string) (Format, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return FormatText, nil
+	}
+
+	for _, f := range formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown format %q (available: %s)", s, strings.Join(FormatNames(), ", "))
+}
+
+// FormatNames returns the supported format names.
+func FormatNames() []string {
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = string(f)
+	}
+	return names
+}
+
+// Render writes the report in a structured format. Text output is handled by
+// the weather display and is rejected here.
+func Render(w io.Writer, format Format, r *Report) error {
+	switch format {
+	case FormatJSON:
+		return JSON(w, r)
+	case FormatYAML:
+		return YAML(w, r)
+	case FormatCSV:
+		return CSV(w, r, TableHourly, TableDaily)
+	case FormatNDJSON:
+		return NDJSON(w, r)
+	default:
+		return fmt.Errorf("format %q is not a structured format", format)
+	}
+}
+
+func JSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func YAML(w io.Writer, v any) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// record is a single NDJSON line. Each line is self-describing so streams
+// from several invocations can be concatenated and filtered by type.
+type record struct {
+	SchemaVersion int    `json:"schema_version"`
+	Type          string `json:"type"`
+	Provider      string `json:"provider,omitempty"`
+	Location      string `json:"location"`
+	Data          any    `json:"data"`
+}
+
+func NDJSON(w io.Writer, r *Report) error {
+	enc := json.NewEncoder(w)
+
+	write := func(kind string, data any) error {
+		return enc.Encode(record{
+			SchemaVersion: r.SchemaVersion,
+			Type:          kind,
+			Provider:      r.Provider,
+			Location:      r.Location.Name,
+			Data:          data,
+		})
+	}
+
+	if err := write("location", r.Location); err != nil {
+		return err
+	}
+	if err := write("current", r.Current); err != nil {
+		return err
+	}
+	for _, h := range r.Hourly {
+		if err := write("hour", h); err != nil {
+			return err
+		}
+	}
+	for _, d := range r.Daily {
+		if err := write("day", d); err != nil {
+			return err
+		}
+	}
+	for _, a := range r.Alerts {
+		if err := write("alert", a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var (
+	hourlyHeader = []string{
+		"time", "condition", "temp_c", "chance_of_rain_pct", "temp_f", "condition_code",
+		"is_day", "feels_like_c", "dew_point_c", "humidity_pct", "cloud_pct", "wind_mph",
+		"wind_degree", "wind_direction", "gust_mph", "pressure_mb", "precip_mm", "snow_cm",
+		"chance_of_snow_pct", "vis_km", "uv",
+	}
+	dailyHeader = []string{
+		"date", "condition", "max_temp_c", "min_temp_c", "avg_temp_c", "max_wind_mph",
+		"total_precip_mm", "avg_humidity_pct", "chance_of_rain_pct", "chance_of_snow_pct",
+		"uv", "sunrise", "sunset", "max_temp_f", "min_temp_f", "avg_temp_f", "max_wind_kph",
+		"total_precip_in", "condition_code", "total_snow_cm", "avg_vis_km", "moonrise",
+		"moonset", "moon_phase", "moon_illumination_pct",
+	}
+)
+
+func hourlyRecord(h *Hour) []string {
+	return []string{
+		h.Time, h.Condition, formatFloat(h.TempC), formatFloat(h.ChanceOfRainPct), formatFloat(h.TempF),
+		strconv.Itoa(h.ConditionCode), strconv.FormatBool(h.IsDay), formatOptional(h.FeelsLikeC),
+		formatOptional(h.DewPointC), formatOptional(h.HumidityPct), formatOptional(h.CloudPct),
+		formatOptional(h.WindMph), formatOptionalInt(h.WindDegree), h.WindDirection, formatOptional(h.GustMph),
+		formatOptional(h.PressureMb), formatOptional(h.PrecipMm), formatOptional(h.SnowCm),
+		formatOptional(h.ChanceOfSnowPct), formatOptional(h.VisKm), formatOptional(h.UV),
+	}
+}
+
+// CSV writes the requested tables, each with its own header row. Multiple
+// tables are separated by a blank line.
+func CSV(w io.Writer, r *Report, tables ...Table) error {
+	for i, table := range tables {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+
+		cw := csv.NewWriter(w)
+
+		switch table {
+		case TableHourly:
+			_ = cw.Write(hourlyHeader)
+			for i := range r.Hourly {
+				_ = cw.Write(hourlyRecord(&r.Hourly[i]))
+			}
+		case TableDaily:
+			_ = cw.Write(dailyHeader)
+			for i := range r.Daily {
+				d := &r.Daily[i]
+				_ = cw.Write([]string{
+					d.Date, d.Condition, formatFloat(d.MaxTempC), formatFloat(d.MinTempC),
+					formatFloat(d.AvgTempC), formatFloat(d.MaxWindMph), formatOptional(d.TotalPrecipMm),
+					formatOptional(d.AvgHumidityPct), strconv.Itoa(d.ChanceOfRainPct),
+					formatOptionalInt(d.ChanceOfSnowPct), formatOptional(d.UV), d.Sunrise, d.Sunset,
+					formatFloat(d.MaxTempF), formatFloat(d.MinTempF), formatFloat(d.AvgTempF),
+					formatFloat(d.MaxWindKph), formatOptional(d.TotalPrecipIn),
+					strconv.Itoa(d.ConditionCode), formatOptional(d.TotalSnowCm), formatOptional(d.AvgVisKm),
+					d.Moonrise, d.Moonset, d.MoonPhase, strconv.Itoa(d.MoonIllumPct),
+				})
+			}
+		default:
+			return fmt.Errorf("unknown CSV table %q", table)
+		}
+
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func formatFloat(f float32) string {
+	return strconv.FormatFloat(float64(f), 'f', -1, 32)
+}
+
+// formatOptional formats an optional reading, leaving the cell empty when
+// it was not reported.
+func formatOptional(f *float32) string {
+	if f == nil {
+		return ""
+	}
+	return formatFloat(*f)
+}
+
+// formatOptionalInt is formatOptional for whole-number readings.
+func formatOptionalInt(i *int) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.Itoa(*i)
+}
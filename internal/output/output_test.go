@@ -0,0 +1,242 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+// loadFixture decodes the recorded weatherapi.com response at the repo
+// root, as mapped into the forecast model.
+func loadFixture(t *testing.T) *Report {
+	t.Helper()
+
+	body, err := os.ReadFile("testdata/forecast.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	var data forecast.Response
+	if err := json.Unmarshal(body, &data); err != nil {
+		t.Fatalf("failed to decode fixture: %v", err)
+	}
+
+	return NewReport(&data)
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Format
+		wantErr bool
+	}{
+		{"", FormatText, false},
+		{"json", FormatJSON, false},
+		{"YAML", FormatYAML, false},
+		{" csv ", FormatCSV, false},
+		{"ndjson", FormatNDJSON, false},
+		{"xml", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	r := loadFixture(t)
+
+	if r.SchemaVersion != SchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", r.SchemaVersion, SchemaVersion)
+	}
+	if r.Location.Name != "Pak Kret" {
+		t.Errorf("Location.Name = %q, want %q", r.Location.Name, "Pak Kret")
+	}
+	if r.Current.TempC != 32 {
+		t.Errorf("Current.TempC = %v, want 32", r.Current.TempC)
+	}
+	if r.Current.TempF != 89.6 {
+		t.Errorf("Current.TempF = %v, want 89.6", r.Current.TempF)
+	}
+	if len(r.Hourly) != 24 {
+		t.Errorf("len(Hourly) = %d, want 24", len(r.Hourly))
+	}
+	if r.Hourly[0].Time != "2024-01-17T17:00:00Z" {
+		t.Errorf("Hourly[0].Time = %q, want RFC 3339 UTC", r.Hourly[0].Time)
+	}
+	if len(r.Daily) != 1 || r.Daily[0].Sunrise != "06:46 AM" {
+		t.Errorf("Daily = %+v", r.Daily)
+	}
+	if d := r.Daily[0]; d.MoonPhase != "First Quarter" || d.MoonIllumPct != 48 || d.Moonset != "No moonset" {
+		t.Errorf("Daily[0] moon = %q, %d%%, moonset %q", d.MoonPhase, d.MoonIllumPct, d.Moonset)
+	}
+	if l := r.Location; l.Lat != 13.92 || l.Lon != 100.5 || l.TzID != "Asia/Bangkok" {
+		t.Errorf("Location = %+v, want coordinates and time zone", l)
+	}
+	if c := r.Current; c.ConditionCode != 1003 || !c.IsDay || *c.UV != 8 || *c.GustMph != 12.5 || *c.CloudPct != 25 || *c.WindDegree != 10 {
+		t.Errorf("Current details = %+v", c)
+	}
+	if h := r.Hourly[0]; h.IsDay || h.DewPointC == nil || *h.DewPointC != 14.7 || h.HeatIndexC == nil || *h.HeatIndexC != 28.5 ||
+		h.WindChillC == nil || *h.WindChillC != 28.4 || *h.VisKm != 10 || *h.UV != 1 {
+		t.Errorf("Hourly[0] details = %+v", h)
+	}
+	if r.Alerts == nil {
+		t.Error("Alerts should be an empty list, not nil, so JSON emits []")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, FormatJSON, loadFixture(t)); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	for _, key := range []string{"schema_version", "provider", "location", "current", "hourly", "daily", "alerts"} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("JSON output missing key %q", key)
+		}
+	}
+	if got["provider"] != "weatherapi" {
+		t.Errorf("provider = %v, want weatherapi", got["provider"])
+	}
+}
+
+func TestJSON_OmitsUnreportedReadings(t *testing.T) {
+	// Providers other than weatherapi.com report no dew point, heat index,
+	// wind chill, UV or air quality.
+	data := &forecast.Response{
+		Current: forecast.Current{TempC: 12},
+		Days:    []forecast.Day{{Hours: []forecast.Hour{{TempC: 11}}}},
+	}
+	data.FillUnitVariants()
+
+	var buf bytes.Buffer
+	if err := Render(&buf, FormatJSON, NewReport(data)); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, key := range []string{"dew_point", "heat_index", "wind_chill", `"uv"`, "pm2_5", "gust"} {
+		if strings.Contains(buf.String(), key) {
+			t.Errorf("JSON output reports %s, which the provider did not supply", key)
+		}
+	}
+}
+
+func TestYAML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, FormatYAML, loadFixture(t)); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	var got Report
+	if err := yaml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid YAML: %v", err)
+	}
+
+	if got.SchemaVersion != SchemaVersion || got.Location.Name != "Pak Kret" {
+		t.Errorf("round-tripped report = %+v", got)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, FormatCSV, loadFixture(t)); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	tables := strings.Split(buf.String(), "\n\n")
+	if len(tables) != 2 {
+		t.Fatalf("got %d tables, want 2", len(tables))
+	}
+
+	hourly, err := csv.NewReader(strings.NewReader(tables[0])).ReadAll()
+	if err != nil {
+		t.Fatalf("hourly table is not valid CSV: %v", err)
+	}
+	if len(hourly) != 25 || hourly[0][0] != "time" {
+		t.Errorf("hourly table has %d rows (header %v), want 25", len(hourly), hourly[0])
+	}
+	if col := slices.Index(hourly[0], "wind_degree"); col < 0 || hourly[1][col] != "99" {
+		t.Errorf("hourly wind_degree column = %d in %v", col, hourly[1])
+	}
+
+	daily, err := csv.NewReader(strings.NewReader(tables[1])).ReadAll()
+	if err != nil {
+		t.Fatalf("daily table is not valid CSV: %v", err)
+	}
+	if len(daily) != 2 || daily[1][0] != "2024-01-18" || daily[1][2] != "35.4" {
+		t.Errorf("daily table = %v", daily)
+	}
+}
+
+func TestCSV_SingleTable(t *testing.T) {
+	var buf bytes.Buffer
+	if err := CSV(&buf, loadFixture(t), TableDaily); err != nil {
+		t.Fatalf("CSV() error = %v", err)
+	}
+
+	if strings.Contains(buf.String(), "\n\n") {
+		t.Error("single table output should not contain a table separator")
+	}
+	if !strings.HasPrefix(buf.String(), "date,") {
+		t.Errorf("output = %q, want daily header first", buf.String())
+	}
+}
+
+func TestNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, FormatNDJSON, loadFixture(t)); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	// location + current + 24 hours + 1 day
+	if len(lines) != 27 {
+		t.Fatalf("got %d lines, want 27", len(lines))
+	}
+
+	counts := map[string]int{}
+	for _, line := range lines {
+		var rec struct {
+			SchemaVersion int    `json:"schema_version"`
+			Type          string `json:"type"`
+			Location      string `json:"location"`
+		}
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("line is not valid JSON: %q", line)
+		}
+		if rec.SchemaVersion != SchemaVersion || rec.Location != "Pak Kret" {
+			t.Errorf("record = %+v", rec)
+		}
+		counts[rec.Type]++
+	}
+
+	if counts["hour"] != 24 || counts["day"] != 1 || counts["current"] != 1 {
+		t.Errorf("record counts = %v", counts)
+	}
+}
+
+func TestRender_RejectsText(t *testing.T) {
+	if err := Render(&bytes.Buffer{}, FormatText, &Report{}); err == nil {
+		t.Error("Render() with text format should return an error")
+	}
+}
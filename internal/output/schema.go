@@ -0,0 +1,316 @@
+// Package output renders forecasts in machine-readable formats. Every format
+// is driven from the versioned Report schema rather than a provider's raw
+// response, so downstream tooling is insulated from upstream API changes.
+package output
+
+import (
+	"math"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+// SchemaVersion is bumped whenever a field is renamed, removed or changes
+// meaning. Adding fields does not require a bump.
+const SchemaVersion = 1
+
+type Report struct {
+	SchemaVersion int      `json:"schema_version" yaml:"schema_version"`
+	Provider      string   `json:"provider,omitempty" yaml:"provider,omitempty"`
+	Location      Location `json:"location" yaml:"location"`
+	Current       Current  `json:"current" yaml:"current"`
+	Hourly        []Hour   `json:"hourly" yaml:"hourly"`
+	Daily         []Day    `json:"daily" yaml:"daily"`
+	Alerts        []Alert  `json:"alerts" yaml:"alerts"`
+
+	// Stale is set when the data came from an expired cache entry because
+	// no provider could be reached; FetchedAt is then when it was fetched.
+	Stale     bool   `json:"stale,omitempty" yaml:"stale,omitempty"`
+	FetchedAt string `json:"fetched_at,omitempty" yaml:"fetched_at,omitempty"`
+}
+
+type Location struct {
+	Name      string  `json:"name" yaml:"name"`
+	Region    string  `json:"region" yaml:"region"`
+	Country   string  `json:"country" yaml:"country"`
+	Lat       float64 `json:"lat" yaml:"lat"`
+	Lon       float64 `json:"lon" yaml:"lon"`
+	TzID      string  `json:"tz_id" yaml:"tz_id"`
+	LocalTime string  `json:"local_time" yaml:"local_time"`
+}
+
+// Current holds the conditions now. Readings the provider does not report
+// are omitted.
+type Current struct {
+	Condition     string   `json:"condition" yaml:"condition"`
+	ConditionCode int      `json:"condition_code" yaml:"condition_code"`
+	IsDay         bool     `json:"is_day" yaml:"is_day"`
+	TempC         float32  `json:"temp_c" yaml:"temp_c"`
+	TempF         float32  `json:"temp_f" yaml:"temp_f"`
+	FeelsLikeC    float32  `json:"feels_like_c" yaml:"feels_like_c"`
+	FeelsLikeF    float32  `json:"feels_like_f" yaml:"feels_like_f"`
+	WindChillC    *float32 `json:"wind_chill_c,omitempty" yaml:"wind_chill_c,omitempty"`
+	WindChillF    *float32 `json:"wind_chill_f,omitempty" yaml:"wind_chill_f,omitempty"`
+	HeatIndexC    *float32 `json:"heat_index_c,omitempty" yaml:"heat_index_c,omitempty"`
+	HeatIndexF    *float32 `json:"heat_index_f,omitempty" yaml:"heat_index_f,omitempty"`
+	DewPointC     *float32 `json:"dew_point_c,omitempty" yaml:"dew_point_c,omitempty"`
+	DewPointF     *float32 `json:"dew_point_f,omitempty" yaml:"dew_point_f,omitempty"`
+	HumidityPct   float32  `json:"humidity_pct" yaml:"humidity_pct"`
+	CloudPct      *float32 `json:"cloud_pct,omitempty" yaml:"cloud_pct,omitempty"`
+	WindMph       float32  `json:"wind_mph" yaml:"wind_mph"`
+	WindKph       float32  `json:"wind_kph" yaml:"wind_kph"`
+	WindDegree    *int     `json:"wind_degree,omitempty" yaml:"wind_degree,omitempty"`
+	WindDirection string   `json:"wind_direction" yaml:"wind_direction"`
+	GustMph       *float32 `json:"gust_mph,omitempty" yaml:"gust_mph,omitempty"`
+	GustKph       *float32 `json:"gust_kph,omitempty" yaml:"gust_kph,omitempty"`
+	PressureMb    *float32 `json:"pressure_mb,omitempty" yaml:"pressure_mb,omitempty"`
+	PressureIn    *float32 `json:"pressure_in,omitempty" yaml:"pressure_in,omitempty"`
+	PrecipMm      *float32 `json:"precip_mm,omitempty" yaml:"precip_mm,omitempty"`
+	PrecipIn      *float32 `json:"precip_in,omitempty" yaml:"precip_in,omitempty"`
+	VisKm         *float32 `json:"vis_km,omitempty" yaml:"vis_km,omitempty"`
+	VisMiles      *float32 `json:"vis_miles,omitempty" yaml:"vis_miles,omitempty"`
+	UV            *float32 `json:"uv,omitempty" yaml:"uv,omitempty"`
+	PM25          *float32 `json:"pm2_5,omitempty" yaml:"pm2_5,omitempty"`
+	PM10          *float32 `json:"pm10,omitempty" yaml:"pm10,omitempty"`
+}
+
+// Hour holds one hour of the forecast. Readings the provider does not
+// report are omitted.
+type Hour struct {
+	Time            string   `json:"time" yaml:"time"`
+	Condition       string   `json:"condition" yaml:"condition"`
+	ConditionCode   int      `json:"condition_code" yaml:"condition_code"`
+	IsDay           bool     `json:"is_day" yaml:"is_day"`
+	TempC           float32  `json:"temp_c" yaml:"temp_c"`
+	TempF           float32  `json:"temp_f" yaml:"temp_f"`
+	FeelsLikeC      *float32 `json:"feels_like_c,omitempty" yaml:"feels_like_c,omitempty"`
+	FeelsLikeF      *float32 `json:"feels_like_f,omitempty" yaml:"feels_like_f,omitempty"`
+	WindChillC      *float32 `json:"wind_chill_c,omitempty" yaml:"wind_chill_c,omitempty"`
+	WindChillF      *float32 `json:"wind_chill_f,omitempty" yaml:"wind_chill_f,omitempty"`
+	HeatIndexC      *float32 `json:"heat_index_c,omitempty" yaml:"heat_index_c,omitempty"`
+	HeatIndexF      *float32 `json:"heat_index_f,omitempty" yaml:"heat_index_f,omitempty"`
+	DewPointC       *float32 `json:"dew_point_c,omitempty" yaml:"dew_point_c,omitempty"`
+	DewPointF       *float32 `json:"dew_point_f,omitempty" yaml:"dew_point_f,omitempty"`
+	HumidityPct     *float32 `json:"humidity_pct,omitempty" yaml:"humidity_pct,omitempty"`
+	CloudPct        *float32 `json:"cloud_pct,omitempty" yaml:"cloud_pct,omitempty"`
+	WindMph         *float32 `json:"wind_mph,omitempty" yaml:"wind_mph,omitempty"`
+	WindKph         *float32 `json:"wind_kph,omitempty" yaml:"wind_kph,omitempty"`
+	WindDegree      *int     `json:"wind_degree,omitempty" yaml:"wind_degree,omitempty"`
+	WindDirection   string   `json:"wind_direction,omitempty" yaml:"wind_direction,omitempty"`
+	GustMph         *float32 `json:"gust_mph,omitempty" yaml:"gust_mph,omitempty"`
+	GustKph         *float32 `json:"gust_kph,omitempty" yaml:"gust_kph,omitempty"`
+	PressureMb      *float32 `json:"pressure_mb,omitempty" yaml:"pressure_mb,omitempty"`
+	PressureIn      *float32 `json:"pressure_in,omitempty" yaml:"pressure_in,omitempty"`
+	PrecipMm        *float32 `json:"precip_mm,omitempty" yaml:"precip_mm,omitempty"`
+	PrecipIn        *float32 `json:"precip_in,omitempty" yaml:"precip_in,omitempty"`
+	SnowCm          *float32 `json:"snow_cm,omitempty" yaml:"snow_cm,omitempty"`
+	ChanceOfRainPct float32  `json:"chance_of_rain_pct" yaml:"chance_of_rain_pct"`
+	ChanceOfSnowPct *float32 `json:"chance_of_snow_pct,omitempty" yaml:"chance_of_snow_pct,omitempty"`
+	VisKm           *float32 `json:"vis_km,omitempty" yaml:"vis_km,omitempty"`
+	VisMiles        *float32 `json:"vis_miles,omitempty" yaml:"vis_miles,omitempty"`
+	UV              *float32 `json:"uv,omitempty" yaml:"uv,omitempty"`
+}
+
+// Day holds one day of the forecast. Readings the provider does not report
+// are omitted.
+type Day struct {
+	Date            string   `json:"date" yaml:"date"`
+	Condition       string   `json:"condition" yaml:"condition"`
+	ConditionCode   int      `json:"condition_code" yaml:"condition_code"`
+	MaxTempC        float32  `json:"max_temp_c" yaml:"max_temp_c"`
+	MinTempC        float32  `json:"min_temp_c" yaml:"min_temp_c"`
+	AvgTempC        float32  `json:"avg_temp_c" yaml:"avg_temp_c"`
+	MaxTempF        float32  `json:"max_temp_f" yaml:"max_temp_f"`
+	MinTempF        float32  `json:"min_temp_f" yaml:"min_temp_f"`
+	AvgTempF        float32  `json:"avg_temp_f" yaml:"avg_temp_f"`
+	MaxWindMph      float32  `json:"max_wind_mph" yaml:"max_wind_mph"`
+	MaxWindKph      float32  `json:"max_wind_kph" yaml:"max_wind_kph"`
+	TotalPrecipMm   *float32 `json:"total_precip_mm,omitempty" yaml:"total_precip_mm,omitempty"`
+	TotalPrecipIn   *float32 `json:"total_precip_in,omitempty" yaml:"total_precip_in,omitempty"`
+	TotalSnowCm     *float32 `json:"total_snow_cm,omitempty" yaml:"total_snow_cm,omitempty"`
+	AvgVisKm        *float32 `json:"avg_vis_km,omitempty" yaml:"avg_vis_km,omitempty"`
+	AvgVisMiles     *float32 `json:"avg_vis_miles,omitempty" yaml:"avg_vis_miles,omitempty"`
+	AvgHumidityPct  *float32 `json:"avg_humidity_pct,omitempty" yaml:"avg_humidity_pct,omitempty"`
+	ChanceOfRainPct int      `json:"chance_of_rain_pct" yaml:"chance_of_rain_pct"`
+	ChanceOfSnowPct *int     `json:"chance_of_snow_pct,omitempty" yaml:"chance_of_snow_pct,omitempty"`
+	UV              *float32 `json:"uv,omitempty" yaml:"uv,omitempty"`
+	Sunrise         string   `json:"sunrise" yaml:"sunrise"`
+	Sunset          string   `json:"sunset" yaml:"sunset"`
+	Moonrise        string   `json:"moonrise" yaml:"moonrise"`
+	Moonset         string   `json:"moonset" yaml:"moonset"`
+	MoonPhase       string   `json:"moon_phase" yaml:"moon_phase"`
+	MoonIllumPct    int      `json:"moon_illumination_pct" yaml:"moon_illumination_pct"`
+}
+
+type Alert struct {
+	Event       string `json:"event" yaml:"event"`
+	Description string `json:"description" yaml:"description"`
+}
+
+// NewReport converts a provider response into the versioned schema.
+func NewReport(data *forecast.Response) *Report {
+	l := data.Location
+
+	r := &Report{
+		SchemaVersion: SchemaVersion,
+		Provider:      data.Provider,
+		Location: Location{
+			Name:      l.Name,
+			Region:    l.Region,
+			Country:   l.Country,
+			Lat:       l.Lat,
+			Lon:       l.Lon,
+			TzID:      l.TimeZone,
+			LocalTime: l.LocalTime,
+		},
+		Current: newCurrent(&data.Current),
+		Hourly:  []Hour{},
+		Daily:   []Day{},
+		Alerts:  []Alert{},
+		Stale:   data.Stale,
+	}
+	if data.Stale {
+		r.FetchedAt = data.FetchedAt.UTC().Format(time.RFC3339)
+	}
+
+	for i := range data.Days {
+		fd := &data.Days[i]
+
+		for j := range fd.Hours {
+			r.Hourly = append(r.Hourly, newHour(&fd.Hours[j]))
+		}
+		r.Daily = append(r.Daily, newDay(fd))
+	}
+
+	for _, a := range data.Alerts {
+		r.Alerts = append(r.Alerts, Alert{Event: a.Event, Description: a.Description})
+	}
+
+	return r
+}
+
+func newCurrent(c *forecast.Current) Current {
+	cur := Current{
+		Condition:     c.Condition.Text,
+		ConditionCode: c.Condition.Code,
+		IsDay:         c.IsDay,
+		TempC:         c.TempC,
+		TempF:         c.TempF,
+		FeelsLikeC:    value(c.FeelsLikeC),
+		FeelsLikeF:    value(c.FeelsLikeF),
+		WindChillC:    c.WindChillC,
+		WindChillF:    c.WindChillF,
+		HeatIndexC:    c.HeatIndexC,
+		HeatIndexF:    c.HeatIndexF,
+		DewPointC:     c.DewPointC,
+		DewPointF:     c.DewPointF,
+		HumidityPct:   value(c.Humidity),
+		CloudPct:      c.Cloud,
+		WindMph:       value(c.WindMph),
+		WindKph:       value(c.WindKph),
+		WindDegree:    c.WindDegree,
+		WindDirection: c.WindDirection,
+		GustMph:       c.GustMph,
+		GustKph:       c.GustKph,
+		PressureMb:    c.PressureMb,
+		PressureIn:    c.PressureIn,
+		PrecipMm:      c.PrecipMm,
+		PrecipIn:      c.PrecipIn,
+		VisKm:         c.VisKm,
+		VisMiles:      c.VisMiles,
+		UV:            c.UV,
+	}
+	if c.AirQuality != nil {
+		cur.PM25 = &c.AirQuality.PM25
+		cur.PM10 = &c.AirQuality.PM10
+	}
+	return cur
+}
+
+func newHour(h *forecast.Hour) Hour {
+	return Hour{
+		Time:            time.Unix(h.TimeUnix, 0).UTC().Format(time.RFC3339),
+		Condition:       h.Condition.Text,
+		ConditionCode:   h.Condition.Code,
+		IsDay:           h.IsDay,
+		TempC:           h.TempC,
+		TempF:           h.TempF,
+		FeelsLikeC:      h.FeelsLikeC,
+		FeelsLikeF:      h.FeelsLikeF,
+		WindChillC:      h.WindChillC,
+		WindChillF:      h.WindChillF,
+		HeatIndexC:      h.HeatIndexC,
+		HeatIndexF:      h.HeatIndexF,
+		DewPointC:       h.DewPointC,
+		DewPointF:       h.DewPointF,
+		HumidityPct:     h.Humidity,
+		CloudPct:        h.Cloud,
+		WindMph:         h.WindMph,
+		WindKph:         h.WindKph,
+		WindDegree:      h.WindDegree,
+		WindDirection:   h.WindDirection,
+		GustMph:         h.GustMph,
+		GustKph:         h.GustKph,
+		PressureMb:      h.PressureMb,
+		PressureIn:      h.PressureIn,
+		PrecipMm:        h.PrecipMm,
+		PrecipIn:        h.PrecipIn,
+		SnowCm:          h.SnowCm,
+		ChanceOfRainPct: value(h.ChanceOfRain),
+		ChanceOfSnowPct: h.ChanceOfSnow,
+		VisKm:           h.VisKm,
+		VisMiles:        h.VisMiles,
+		UV:              h.UV,
+	}
+}
+
+func newDay(fd *forecast.Day) Day {
+	d, a := &fd.Summary, &fd.Astro
+	day := Day{
+		Date:            fd.Date,
+		Condition:       d.Condition.Text,
+		ConditionCode:   d.Condition.Code,
+		MaxTempC:        d.MaxTempC,
+		MinTempC:        d.MinTempC,
+		AvgTempC:        d.AvgTempC,
+		MaxTempF:        d.MaxTempF,
+		MinTempF:        d.MinTempF,
+		AvgTempF:        d.AvgTempF,
+		MaxWindMph:      value(d.MaxWindMph),
+		MaxWindKph:      value(d.MaxWindKph),
+		TotalPrecipMm:   d.TotalPrecipMm,
+		TotalPrecipIn:   d.TotalPrecipIn,
+		TotalSnowCm:     d.TotalSnowCm,
+		AvgVisKm:        d.AvgVisKm,
+		AvgVisMiles:     d.AvgVisMiles,
+		AvgHumidityPct:  d.AvgHumidity,
+		ChanceOfRainPct: percent(value(d.ChanceOfRain)),
+		UV:              d.UV,
+		Sunrise:         a.Sunrise,
+		Sunset:          a.Sunset,
+		Moonrise:        a.Moonrise,
+		Moonset:         a.Moonset,
+		MoonPhase:       a.MoonPhase,
+	}
+	if d.ChanceOfSnow != nil {
+		day.ChanceOfSnowPct = forecast.Ptr(percent(*d.ChanceOfSnow))
+	}
+	if a.MoonIllumination != nil {
+		day.MoonIllumPct = *a.MoonIllumination
+	}
+	return day
+}
+
+// value returns an optional reading, or zero for a field the schema
+// always includes.
+func value(v *float32) float32 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// percent rounds a percentage to a whole number.
+func percent(v float32) int {
+	return int(math.Round(float64(v)))
+}
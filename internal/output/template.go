@@ -0,0 +1,107 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jtotty/weather-cli/internal/ui"
+	"github.com/jtotty/weather-cli/internal/units"
+)
+
+// templateFuncs are available to user templates. They mirror the helpers the
+// text display uses so custom layouts can look the same.
+var templateFuncs = template.FuncMap{
+	"colorTemp": ui.ColorizeTemp,
+	"icon":      ui.GetWeatherIcon,
+	"aqiIcon":   ui.GetAqiIcon,
+	"namedIcon": ui.GetIcon,
+
+	"fahrenheit": units.CelsiusToFahrenheit,
+	"kph":        units.MphToKph,
+	"inches":     units.MillimetersToInches,
+	"round": func(places int, v float32) float64 {
+		scale := math.Pow(10, float64(places))
+		return math.Round(float64(v)*scale) / scale
+	},
+
+	"formatTime": formatTime,
+	"formatDate": formatDate,
+
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"title":    title,
+	"join":     strings.Join,
+	"truncate": truncate,
+	"first": func(n int, hours []Hour) []Hour {
+		return hours[:min(n, len(hours))]
+	},
+}
+
+// ParseTemplate parses a user template with the helper functions available.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// Template renders the report through the text/template file at path.
+func Template(w io.Writer, path string, r *Report) error {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+
+	tmpl, err := ParseTemplate(filepath.Base(path), string(text))
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, r)
+}
+
+// formatTime reformats an RFC 3339 timestamp from the report in the local zone.
+func formatTime(layout, value string) (string, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", err
+	}
+	return t.Local().Format(layout), nil
+}
+
+// formatDate reformats a report date (YYYY-MM-DD).
+func formatDate(layout, value string) (string, error) {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(layout), nil
+}
+
+// title capitalizes the first letter of each word, which may be multibyte,
+// as in "Évora".
+func title(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		r, size := utf8.DecodeRuneInString(w)
+		words[i] = string(unicode.ToUpper(r)) + strings.ToLower(w[size:])
+	}
+	return strings.Join(words, " ")
+}
+
+func truncate(n int, s string) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,91 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTemplate_Functions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"fields", `{{.Location.Name}}: {{.Current.Condition}}`, "Pak Kret: Partly cloudy"},
+		{"fahrenheit", `{{fahrenheit .Current.TempC | round 1}}`, "89.6"},
+		{"kph", `{{kph .Current.WindMph | round 0}}`, "13"},
+		{"inches", `{{inches 25.4}}`, "1"},
+		{"icon", `{{icon .Current.Condition}}`, "⛅"},
+		{"color", `{{colorTemp .Current.TempC}}`, " 32°C"},
+		{"date", `{{(index .Daily 0).Date | formatDate "Mon 02 Jan"}}`, "Thu 18 Jan"},
+		{"first", `{{len (first 3 .Hourly)}}`, "3"},
+		{"strings", `{{upper "abc"}} {{title "light rain"}} {{truncate 3 "Sunny"}}`, "ABC Light Rain Sun"},
+		{"title multibyte", `{{title "évora"}}, {{title "łódź"}}, {{title "ÖREBRO län"}}`, "Évora, Łódź, Örebro Län"},
+	}
+
+	r := loadFixture(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate(tt.name, tt.text)
+			if err != nil {
+				t.Fatalf("ParseTemplate() error = %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, r); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("output = %q, want string containing %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	got, err := formatTime("2006-01-02 15:04", "2024-01-18T05:00:00Z")
+	if err != nil {
+		t.Fatalf("formatTime() error = %v", err)
+	}
+
+	want := time.Date(2024, 1, 18, 5, 0, 0, 0, time.UTC).Local().Format("2006-01-02 15:04")
+	if got != want {
+		t.Errorf("formatTime() = %q, want %q", got, want)
+	}
+
+	if _, err := formatTime("15:04", "not a time"); err == nil {
+		t.Error("formatTime() expected error for invalid input")
+	}
+}
+
+func TestTemplate_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status.tmpl")
+	if err := os.WriteFile(path, []byte(`{{.Location.Name}} {{.Current.TempC}}°C`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Template(&buf, path, loadFixture(t)); err != nil {
+		t.Fatalf("Template() error = %v", err)
+	}
+
+	if buf.String() != "Pak Kret 32°C" {
+		t.Errorf("Template() = %q, want %q", buf.String(), "Pak Kret 32°C")
+	}
+}
+
+func TestTemplate_Errors(t *testing.T) {
+	if err := Template(&bytes.Buffer{}, filepath.Join(t.TempDir(), "missing.tmpl"), &Report{}); err == nil {
+		t.Error("Template() expected error for missing file")
+	}
+
+	if _, err := ParseTemplate("bad", "{{.Location.Name"); err == nil {
+		t.Error("ParseTemplate() expected error for invalid syntax")
+	}
+}
@@ -0,0 +1,109 @@
+// Package provider selects the weather backend used to fetch forecasts.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jtotty/weather-cli/internal/api/metno"
+	"github.com/jtotty/weather-cli/internal/api/nws"
+	"github.com/jtotty/weather-cli/internal/api/openmeteo"
+	"github.com/jtotty/weather-cli/internal/api/weather"
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+// Default is used when no provider is configured.
+const Default = weather.ProviderName
+
+// Provider fetches a forecast and normalizes it into the forecast.Response
+// model shared by the cache and display.
+type Provider interface {
+	Name() string
+	Fetch(ctx context.Context, opts forecast.Options) (*forecast.Response, error)
+}
+
+type registration struct {
+	name           string
+	description    string
+	requiresAPIKey bool
+	new            func(apiKey string) Provider
+}
+
+var registry = []registration{
+	{
+		name:           weather.ProviderName,
+		description:    "weatherapi.com (requires API key)",
+		requiresAPIKey: true,
+		new:            func(apiKey string) Provider { return weather.NewClient(apiKey) },
+	},
+	{
+		name:        openmeteo.ProviderName,
+		description: "Open-Meteo (worldwide, no key)",
+		new:         func(string) Provider { return openmeteo.NewClient() },
+	},
+	{
+		name:        metno.ProviderName,
+		description: "MET Norway api.met.no (worldwide, no key)",
+		new:         func(string) Provider { return metno.NewClient() },
+	},
+	{
+		name:        nws.ProviderName,
+		description: "US National Weather Service (US only, no key)",
+		new:         func(string) Provider { return nws.NewClient() },
+	},
+}
+
+// New returns the named provider. An empty name selects Default.
+func New(name, apiKey string) (Provider, error) {
+	reg, err := lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	return reg.new(apiKey), nil
+}
+
+// Validate returns an error if name is not a registered provider.
+func Validate(name string) error {
+	_, err := lookup(name)
+	return err
+}
+
+// RequiresAPIKey reports whether the named provider needs a weatherapi.com key.
+func RequiresAPIKey(name string) bool {
+	reg, err := lookup(name)
+	return err == nil && reg.requiresAPIKey
+}
+
+// Names returns the registered provider names in display order.
+func Names() []string {
+	names := make([]string, len(registry))
+	for i, reg := range registry {
+		names[i] = reg.name
+	}
+	return names
+}
+
+// Description returns a one-line summary of the named provider.
+func Description(name string) string {
+	reg, err := lookup(name)
+	if err != nil {
+		return ""
+	}
+	return reg.description
+}
+
+func lookup(name string) (registration, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = Default
+	}
+
+	for _, reg := range registry {
+		if reg.name == name {
+			return reg, nil
+		}
+	}
+
+	return registration{}, fmt.Errorf("unknown provider %q (available: %s)", name, strings.Join(Names(), ", "))
+}
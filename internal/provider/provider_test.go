@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		wantName string
+		wantErr  bool
+	}{
+		{"", "weatherapi", false},
+		{"weatherapi", "weatherapi", false},
+		{"open-meteo", "open-meteo", false},
+		{"MET-Norway", "met-norway", false},
+		{" nws ", "nws", false},
+		{"accuweather", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(tt.name, "test-key")
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), "open-meteo") {
+					t.Errorf("error = %q, want list of available providers", err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if p.Name() != tt.wantName {
+				t.Errorf("Name() = %q, want %q", p.Name(), tt.wantName)
+			}
+		})
+	}
+}
+
+func TestRequiresAPIKey(t *testing.T) {
+	if !RequiresAPIKey("") {
+		t.Error("default provider should require an API key")
+	}
+	if !RequiresAPIKey("weatherapi") {
+		t.Error("weatherapi should require an API key")
+	}
+	for _, name := range []string{"open-meteo", "met-norway", "nws"} {
+		if RequiresAPIKey(name) {
+			t.Errorf("%s should not require an API key", name)
+		}
+	}
+	if RequiresAPIKey("unknown") {
+		t.Error("unknown provider should not report requiring an API key")
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := Names()
+	if len(names) != 4 {
+		t.Fatalf("len(Names()) = %d, want 4", len(names))
+	}
+	if names[0] != Default {
+		t.Errorf("Names()[0] = %q, want default %q", names[0], Default)
+	}
+}
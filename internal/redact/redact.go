@@ -0,0 +1,87 @@
+// Package redact scrubs secrets such as API keys from text before it is
+// printed, so error messages can be shared safely.
+package redact
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Placeholder replaces every redacted secret.
+const Placeholder = "REDACTED"
+
+// minSecretLength stops short values from being registered by mistake and
+// then scrubbed from unrelated text.
+const minSecretLength = 6
+
+var (
+	mu      sync.RWMutex
+	secrets = make(map[string]struct{})
+)
+
+// keyParam matches credential query parameters in URLs, so keys that were
+// never registered are still scrubbed from request errors.
+var keyParam = regexp.MustCompile(`(?i)([?&](?:key|api_?key|appid|token)=)[^&\s"']+`)
+
+// Register adds secret to the values scrubbed by String and Error.
+func Register(secret string) {
+	if len(secret) < minSecretLength {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	secrets[secret] = struct{}{}
+}
+
+// String returns s with registered secrets, the extra secrets given and
+// credential query parameters replaced by Placeholder.
+func String(s string, extra ...string) string {
+	mu.RLock()
+	all := make([]string, 0, len(secrets)+len(extra))
+	for secret := range secrets {
+		all = append(all, secret)
+	}
+	mu.RUnlock()
+
+	for _, secret := range extra {
+		if len(secret) >= minSecretLength {
+			all = append(all, secret)
+		}
+	}
+
+	for _, secret := range all {
+		s = strings.ReplaceAll(s, secret, Placeholder)
+		if escaped := url.QueryEscape(secret); escaped != secret {
+			s = strings.ReplaceAll(s, escaped, Placeholder)
+		}
+	}
+	return keyParam.ReplaceAllString(s, "${1}"+Placeholder)
+}
+
+// Error returns err with its message scrubbed as by String. The result
+// still unwraps to err, so errors.Is and errors.As keep working; callers
+// must print the returned error, not the errors it wraps. Errors with
+// nothing to scrub are returned unchanged.
+func Error(err error, extra ...string) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := err.Error()
+	if scrubbed := String(msg, extra...); scrubbed != msg {
+		return &redactedError{msg: scrubbed, err: err}
+	}
+	return err
+}
+
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+
+func (e *redactedError) Unwrap() error { return e.err }
@@ -0,0 +1,63 @@
+package redact
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	Register("registered-secret")
+
+	tests := []struct {
+		name  string
+		in    string
+		extra []string
+		want  string
+	}{
+		{
+			"url error",
+			`Get "https://api.weatherapi.com/v1/forecast.json?days=3&key=abc123def&q=London": dial tcp: i/o timeout`,
+			nil,
+			`Get "https://api.weatherapi.com/v1/forecast.json?days=3&key=REDACTED&q=London": dial tcp: i/o timeout`,
+		},
+		{"first query parameter", "https://example.com/?apikey=abc123def", nil, "https://example.com/?apikey=REDACTED"},
+		{"registered secret", "token registered-secret rejected", nil, "token REDACTED rejected"},
+		{"extra secret", "bad key s3cr3t-key", []string{"s3cr3t-key"}, "bad key REDACTED"},
+		{"escaped secret", "q=" + url.QueryEscape("a key/with+chars"), []string{"a key/with+chars"}, "q=REDACTED"},
+		{"short extra ignored", "London is abc", []string{"abc"}, "London is abc"},
+		{"nothing to scrub", "no matching location found", nil, "no matching location found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := String(tt.in, tt.extra...); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	sentinel := errors.New("dial tcp: connection refused")
+	err := fmt.Errorf("API request failed: Get \"https://x/?key=hunter22\": %w", sentinel)
+
+	got := Error(err, "hunter22")
+
+	if strings.Contains(got.Error(), "hunter22") {
+		t.Errorf("Error() = %q, leaks the secret", got)
+	}
+	if !errors.Is(got, sentinel) {
+		t.Error("redacted error no longer matches the wrapped error")
+	}
+
+	plain := errors.New("no secrets here")
+	if Error(plain) != plain {
+		t.Error("Error() wrapped an error with nothing to scrub")
+	}
+	if Error(nil) != nil {
+		t.Error("Error(nil) != nil")
+	}
+}
@@ -0,0 +1,34 @@
+//go:build !windows
+
+package screen
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// NotifyResize returns a channel that receives a value whenever the
+// terminal is resized, until ctx is done.
+func NotifyResize(ctx context.Context) <-chan struct{} {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGWINCH)
+
+	resized := make(chan struct{}, 1)
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				select {
+				case resized <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return resized
+}
@@ -0,0 +1,45 @@
+//go:build windows
+
+package screen
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// resizePollInterval is how often the console size is checked, as Windows
+// has no resize signal.
+const resizePollInterval = 250 * time.Millisecond
+
+// NotifyResize returns a channel that receives a value whenever the
+// terminal is resized, until ctx is done.
+func NotifyResize(ctx context.Context) <-chan struct{} {
+	resized := make(chan struct{}, 1)
+	go func() {
+		fd := int(os.Stdout.Fd())
+		width, height, _ := term.GetSize(fd)
+
+		ticker := time.NewTicker(resizePollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w, h, err := term.GetSize(fd)
+				if err != nil || (w == width && h == height) {
+					continue
+				}
+				width, height = w, h
+				select {
+				case resized <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return resized
+}
@@ -0,0 +1,124 @@
+// Package screen provides the ANSI escape sequences and text measuring
+// helpers used by the full-screen commands.
+package screen
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Escape sequences for full-screen drawing.
+const (
+	EnterAlt   = "\033[?1049h"
+	ExitAlt    = "\033[?1049l"
+	HideCursor = "\033[?25l"
+	ShowCursor = "\033[?25h"
+	Home       = "\033[H"
+	ClearLine  = "\033[K"
+	ClearDown  = "\033[J"
+)
+
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+
+// Strip removes ANSI escape sequences from s.
+func Strip(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+// Width approximates the terminal width of s: escape sequences take no
+// space, emoji take two columns and variation selectors none.
+func Width(s string) int {
+	width := 0
+	for _, r := range Strip(s) {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+func runeWidth(r rune) int {
+	switch {
+	case r == '\uFE0F' || r == '\u200D':
+		return 0
+	case r >= 0x1F000:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Truncate cuts s to at most width columns, keeping escape sequences intact
+// and resetting attributes if anything was cut.
+func Truncate(s string, width int) string {
+	if Width(s) <= width {
+		return s
+	}
+
+	var b strings.Builder
+	used := 0
+	for len(s) > 0 {
+		if loc := ansiPattern.FindStringIndex(s); loc != nil && loc[0] == 0 {
+			b.WriteString(s[:loc[1]])
+			s = s[loc[1]:]
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s)
+		if used+runeWidth(r) > width {
+			break
+		}
+		used += runeWidth(r)
+		b.WriteString(s[:size])
+		s = s[size:]
+	}
+	b.WriteString("\033[0m")
+	return b.String()
+}
+
+// Cut splits s after its first n bytes of visible text, as measured on
+// Strip(s). Escape sequences at the split stay with the text they precede.
+func Cut(s string, n int) (before, after string) {
+	i := 0
+	for i < len(s) && n > 0 {
+		if loc := ansiPattern.FindStringIndex(s[i:]); loc != nil && loc[0] == 0 {
+			i += loc[1]
+			continue
+		}
+		i++
+		n--
+	}
+	return s[:i], s[i:]
+}
+
+// Fit truncates every line of text to width and keeps at most height lines.
+// Non-positive dimensions leave that direction unlimited.
+func Fit(text string, width, height int) []string {
+	lines := strings.Split(text, "\n")
+	if height > 0 && len(lines) > height {
+		lines = lines[:height]
+	}
+	if width > 0 {
+		for i, line := range lines {
+			lines[i] = Truncate(line, width)
+		}
+	}
+	return lines
+}
+
+// Frame returns the output that repaints the screen with lines, overwriting
+// the previous frame in place rather than clearing it first, which avoids
+// flicker. Lines are separated by "\r\n" so frames also draw correctly in
+// raw mode.
+func Frame(lines []string) string {
+	var b strings.Builder
+	b.WriteString(Home)
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString(ClearLine)
+	}
+	b.WriteString(ClearDown)
+	return b.String()
+}
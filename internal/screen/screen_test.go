@@ -0,0 +1,85 @@
+package screen
+
+import (
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"", 0},
+		{"London", 6},
+		{"\033[38;2;68;128;144m 14°C\033[0m", 5},
+		{"☁️ Cloudy", 8},
+		{"🌧 Rain", 7},
+	}
+
+	for _, tt := range tests {
+		if got := Width(tt.input); got != tt.want {
+			t.Errorf("Width(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		width int
+		want  string
+	}{
+		{"fits", "London", 10, "London"},
+		{"cut", "Weather Forecast", 7, "Weather\033[0m"},
+		{"keeps escapes", "\033[1mBold text\033[0m", 4, "\033[1mBold\033[0m"},
+		{"wide rune at edge", "ab🌧c", 3, "ab\033[0m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Truncate(tt.input, tt.width); got != tt.want {
+				t.Errorf("Truncate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCut(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		n      int
+		before string
+		after  string
+	}{
+		{"plain", "Wind: SW", 4, "Wind", ": SW"},
+		{"escape after split", "Temp\033[31m 12\033[0m", 4, "Temp", "\033[31m 12\033[0m"},
+		{"escape before split", "\033[1mHi\033[0m there", 2, "\033[1mHi", "\033[0m there"},
+		{"longer than s", "ab", 5, "ab", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, after := Cut(tt.input, tt.n)
+			if before != tt.before || after != tt.after {
+				t.Errorf("Cut() = %q, %q, want %q, %q", before, after, tt.before, tt.after)
+			}
+		})
+	}
+}
+
+func TestFit(t *testing.T) {
+	lines := Fit("first line\nsecond\nthird", 5, 2)
+
+	if len(lines) != 2 {
+		t.Fatalf("Fit() returned %d lines, want 2", len(lines))
+	}
+	if Strip(lines[0]) != "first" || lines[1] != "secon\033[0m" {
+		t.Errorf("Fit() = %q", lines)
+	}
+
+	if got := Fit("a\nb", 0, 0); len(got) != 2 {
+		t.Errorf("Fit() with no limits = %q, want both lines", got)
+	}
+}
@@ -0,0 +1,183 @@
+package screen
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Virtual is an in-memory terminal that interprets the subset of escape
+// sequences the full-screen commands write, so their output can be checked
+// cell by cell. Colors and other attributes are ignored.
+type Virtual struct {
+	width, height int
+	cells         [][]rune
+	row, col      int
+
+	// saved holds the main screen while the alternate screen is active.
+	saved [][]rune
+
+	// AltScreen and CursorVisible track the terminal modes.
+	AltScreen     bool
+	CursorVisible bool
+}
+
+// wideFiller marks the second cell of a double-width rune.
+const wideFiller = -1
+
+// NewVirtual creates a blank terminal of the given size.
+func NewVirtual(width, height int) *Virtual {
+	v := &Virtual{width: width, height: height, CursorVisible: true}
+	v.cells = v.blank()
+	return v
+}
+
+// Size reports the terminal size, matching term.GetSize.
+func (v *Virtual) Size() (width, height int, err error) {
+	return v.width, v.height, nil
+}
+
+// Resize changes the terminal size, keeping what still fits.
+func (v *Virtual) Resize(width, height int) {
+	v.width, v.height = width, height
+	v.cells = v.refit(v.cells)
+	if v.saved != nil {
+		v.saved = v.refit(v.saved)
+	}
+	v.row, v.col = min(v.row, height-1), min(v.col, width-1)
+}
+
+func (v *Virtual) refit(old [][]rune) [][]rune {
+	cells := v.blank()
+	for r := 0; r < min(len(old), v.height); r++ {
+		copy(cells[r], old[r])
+	}
+	return cells
+}
+
+// Write interprets p as terminal output.
+func (v *Virtual) Write(p []byte) (int, error) {
+	s := string(p)
+	for len(s) > 0 {
+		if loc := ansiPattern.FindStringIndex(s); loc != nil && loc[0] == 0 {
+			v.escape(s[2:loc[1]])
+			s = s[loc[1]:]
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+		switch r {
+		case '\r':
+			v.col = 0
+		case '\n':
+			v.newline()
+		default:
+			v.put(r)
+		}
+	}
+	return len(p), nil
+}
+
+// Lines returns the text on screen, one string per row, without trailing
+// spaces.
+func (v *Virtual) Lines() []string {
+	lines := make([]string, v.height)
+	for i, row := range v.cells {
+		var b strings.Builder
+		for _, r := range row {
+			if r != wideFiller {
+				b.WriteRune(r)
+			}
+		}
+		lines[i] = strings.TrimRight(b.String(), " ")
+	}
+	return lines
+}
+
+// String returns the screen contents with trailing blank lines removed.
+func (v *Virtual) String() string {
+	return strings.TrimRight(strings.Join(v.Lines(), "\n"), "\n")
+}
+
+func (v *Virtual) blank() [][]rune {
+	cells := make([][]rune, v.height)
+	for i := range cells {
+		cells[i] = []rune(strings.Repeat(" ", v.width))
+	}
+	return cells
+}
+
+func (v *Virtual) put(r rune) {
+	w := runeWidth(r)
+	if w == 0 {
+		return
+	}
+	if v.col+w > v.width {
+		v.col = 0
+		v.newline()
+	}
+
+	v.cells[v.row][v.col] = r
+	if w == 2 {
+		v.cells[v.row][v.col+1] = wideFiller
+	}
+	v.col += w
+}
+
+func (v *Virtual) newline() {
+	if v.row < v.height-1 {
+		v.row++
+		return
+	}
+	v.cells = append(v.cells[1:], []rune(strings.Repeat(" ", v.width)))
+}
+
+// escape applies the CSI sequence seq, given without its "\x1b[" prefix.
+func (v *Virtual) escape(seq string) {
+	final, params := seq[len(seq)-1], seq[:len(seq)-1]
+
+	switch {
+	case final == 'H':
+		row, col := 1, 1
+		if r, c, ok := strings.Cut(params, ";"); ok {
+			row, col = atoi(r, 1), atoi(c, 1)
+		} else if params != "" {
+			row = atoi(params, 1)
+		}
+		v.row, v.col = min(row, v.height)-1, min(col, v.width)-1
+	case final == 'K':
+		v.clear(v.row, v.col, v.width)
+	case final == 'J' && params == "2":
+		for r := range v.cells {
+			v.clear(r, 0, v.width)
+		}
+	case final == 'J':
+		v.clear(v.row, v.col, v.width)
+		for r := v.row + 1; r < v.height; r++ {
+			v.clear(r, 0, v.width)
+		}
+	case params == "?1049" && final == 'h' && !v.AltScreen:
+		v.AltScreen = true
+		v.saved, v.cells = v.cells, v.blank()
+	case params == "?1049" && final == 'l' && v.AltScreen:
+		v.AltScreen = false
+		v.cells, v.saved = v.saved, nil
+	case params == "?25":
+		v.CursorVisible = final == 'h'
+	}
+}
+
+func (v *Virtual) clear(row, from, to int) {
+	for c := from; c < to; c++ {
+		v.cells[row][c] = ' '
+	}
+}
+
+func atoi(s string, def int) int {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return def
+	}
+	return n
+}
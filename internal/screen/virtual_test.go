@@ -0,0 +1,57 @@
+package screen
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestVirtual_Frame(t *testing.T) {
+	v := NewVirtual(10, 4)
+
+	fmt.Fprint(v, EnterAlt+HideCursor)
+	fmt.Fprint(v, Frame([]string{"first line", "\033[1msecond\033[0m", "third"}))
+	fmt.Fprint(v, Frame([]string{"short"}))
+
+	want := []string{"short", "", "", ""}
+	for i, line := range v.Lines() {
+		if line != want[i] {
+			t.Errorf("line %d = %q, want %q", i, line, want[i])
+		}
+	}
+	if !v.AltScreen || v.CursorVisible {
+		t.Errorf("AltScreen = %v, CursorVisible = %v; want alternate screen with hidden cursor", v.AltScreen, v.CursorVisible)
+	}
+}
+
+func TestVirtual_RestoresMainScreen(t *testing.T) {
+	v := NewVirtual(20, 3)
+	fmt.Fprint(v, "shell prompt")
+	fmt.Fprint(v, EnterAlt+Frame([]string{"dashboard"})+ShowCursor+ExitAlt)
+
+	if got := v.String(); got != "shell prompt" {
+		t.Errorf("String() = %q, want main screen restored", got)
+	}
+}
+
+func TestVirtual_WideRunesAndPositioning(t *testing.T) {
+	v := NewVirtual(10, 3)
+	fmt.Fprint(v, "🌧 Rain\033[3;4HX")
+
+	lines := v.Lines()
+	if lines[0] != "🌧 Rain" || lines[2] != "   X" {
+		t.Errorf("Lines() = %q", lines)
+	}
+}
+
+func TestVirtual_Resize(t *testing.T) {
+	v := NewVirtual(10, 3)
+	fmt.Fprint(v, "abcdefghij\r\nsecond")
+	v.Resize(4, 1)
+
+	if w, h, _ := v.Size(); w != 4 || h != 1 {
+		t.Errorf("Size() = %d, %d; want 4, 1", w, h)
+	}
+	if got := v.String(); got != "abcd" {
+		t.Errorf("String() = %q, want %q", got, "abcd")
+	}
+}
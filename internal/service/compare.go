@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+// maxConcurrentFetches bounds the requests GetWeatherMany has in flight.
+const maxConcurrentFetches = 4
+
+// LocationResult is the outcome of fetching a single location.
+type LocationResult struct {
+	Location string
+	Data     *forecast.Response
+	Err      error
+}
+
+// GetWeatherMany fetches several locations concurrently, each through the
+// cache. Results are returned in the order given and a failure only affects
+// its own entry.
+func (w *Weather) GetWeatherMany(ctx context.Context, locations []string) []LocationResult {
+	results := make([]LocationResult, len(locations))
+	sem := make(chan struct{}, maxConcurrentFetches)
+
+	var wg sync.WaitGroup
+	for i, location := range locations {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			data, err := w.GetWeatherFor(ctx, location)
+			results[i] = LocationResult{Location: location, Data: data, Err: err}
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
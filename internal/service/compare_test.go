@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/jtotty/weather-cli/internal/config"
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+// locationFetcher answers per location and is safe for concurrent use.
+type locationFetcher struct {
+	mu      sync.Mutex
+	failing map[string]error
+	calls   []string
+}
+
+func (f *locationFetcher) Name() string { return "mock" }
+
+func (f *locationFetcher) Fetch(_ context.Context, opts forecast.Options) (*forecast.Response, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, opts.Location)
+	f.mu.Unlock()
+
+	if err := f.failing[opts.Location]; err != nil {
+		return nil, err
+	}
+	return &forecast.Response{Location: forecast.Location{Name: opts.Location}}, nil
+}
+
+func TestGetWeatherMany(t *testing.T) {
+	cache := newMockCache()
+	cache.data["london"] = &forecast.Response{Location: forecast.Location{Name: "London (cached)"}}
+
+	errNotFound := errors.New("location not found")
+	fetcher := &locationFetcher{failing: map[string]error{"Atlantis": errNotFound}}
+
+	svc := NewWeatherWithDeps(&config.Config{Days: 3}, cache, fetcher)
+	results := svc.GetWeatherMany(context.Background(), []string{"London", "Atlantis", "Paris", "Oslo"})
+
+	if len(results) != 4 {
+		t.Fatalf("len(results) = %d, want 4", len(results))
+	}
+
+	if results[0].Data == nil || results[0].Data.Location.Name != "London (cached)" {
+		t.Errorf("results[0] = %+v, want cached London", results[0])
+	}
+	if !errors.Is(results[1].Err, errNotFound) || results[1].Data != nil {
+		t.Errorf("results[1] = %+v, want not found error", results[1])
+	}
+	for _, r := range results[2:] {
+		if r.Err != nil || r.Data == nil || r.Data.Location.Name != r.Location {
+			t.Errorf("result for %s = %+v, want fetched data", r.Location, r)
+		}
+	}
+
+	if len(fetcher.calls) != 3 {
+		t.Errorf("fetch calls = %v, want the three uncached locations", fetcher.calls)
+	}
+	if _, ok := cache.data["paris"]; !ok {
+		t.Error("fetched locations should be cached")
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/api/geocode"
+	"github.com/jtotty/weather-cli/internal/api/weather"
+	"github.com/jtotty/weather-cli/internal/cache"
+	"github.com/jtotty/weather-cli/internal/config"
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/redact"
+)
+
+// MaxHistoryDays bounds a history range, as each day is a separate request.
+const MaxHistoryDays = 31
+
+// earliestHistory is the first date weatherapi.com has observations for.
+var earliestHistory = time.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// HistoryFetcher looks up the observed weather for a single day.
+type HistoryFetcher interface {
+	History(ctx context.Context, location string, date time.Time) (*forecast.Response, error)
+}
+
+// History looks up past weather one day at a time. Observations never
+// change, so each finished day is cached indefinitely in its own cache
+// namespace instead of expiring with the forecasts. Days are cached under
+// the coordinates of the place they were observed at, so every spelling of
+// a place shares them; a query is cached under the place it resolved to.
+type History struct {
+	fetcher HistoryFetcher
+	cache   WeatherCache
+	now     func() time.Time
+
+	// offline and refresh mirror the Config fields of the same names.
+	offline bool
+	refresh bool
+}
+
+// NewHistory creates a History service using weatherapi.com, the only
+// provider with historical data.
+func NewHistory(cfg *config.Config) *History {
+	client := weather.NewClient(cfg.APIKey).WithRetry(cfg.RetryPolicy())
+
+	var cacheImpl WeatherCache
+	historyCache, err := cache.NewNamespace(cache.HistoryNamespace, cache.NoExpiry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cache unavailable: %v\n", err)
+	} else {
+		cacheImpl = historyCache
+	}
+
+	h := NewHistoryWithDeps(client, cacheImpl)
+	h.offline, h.refresh = cfg.Offline, cfg.Refresh
+	return h
+}
+
+// NewHistoryWithDeps creates a History service with injected dependencies (for testing).
+func NewHistoryWithDeps(f HistoryFetcher, c WeatherCache) *History {
+	return &History{fetcher: f, cache: c, now: time.Now}
+}
+
+// GetHistory returns the weather at location for each day from from to to
+// inclusive, as one ForecastDay per date. Both are dates at midnight UTC.
+func (h *History) GetHistory(ctx context.Context, location string, from, to time.Time) (*forecast.Response, error) {
+	if err := h.validate(from, to); err != nil {
+		return nil, err
+	}
+
+	place := h.place(location)
+	result := &forecast.Response{Provider: weather.ProviderName}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		day, err := h.day(ctx, location, place, date)
+		if err != nil {
+			return nil, err
+		}
+		if len(result.Days) == 0 {
+			result.Location = day.Location
+			place = h.resolved(location, place, day.Location)
+		}
+		result.Days = append(result.Days, day.Days...)
+	}
+	return result, nil
+}
+
+// cacheable reports whether lookups of location can be cached. An IP
+// lookup is not: it names wherever the network is at the time.
+func (h *History) cacheable(location string) bool {
+	return h.cache != nil && !strings.HasPrefix(location, "auto:")
+}
+
+// place returns the coordinates location resolved to before, or "" if it
+// has not been looked up.
+func (h *History) place(location string) string {
+	if !h.cacheable(location) || h.refresh {
+		return ""
+	}
+	if data := h.cache.Get(location); data != nil {
+		return coordinates(data.Location)
+	}
+	return ""
+}
+
+// resolved returns the coordinates of loc, where location was found,
+// remembering them for later lookups if they differ from place.
+func (h *History) resolved(location, place string, loc forecast.Location) string {
+	coords := coordinates(loc)
+	if coords != place && h.cacheable(location) {
+		if err := h.cache.Set(location, &forecast.Response{Location: loc}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to cache data: %v\n", err)
+		}
+	}
+	return coords
+}
+
+func (h *History) validate(from, to time.Time) error {
+	today := h.today()
+	switch {
+	case to.Before(from):
+		return errors.New("the end date must not be before the start date")
+	case from.Before(earliestHistory):
+		return fmt.Errorf("history is only available from %s", earliestHistory.Format("2006-01-02"))
+	case to.After(today):
+		return errors.New("history is only available up to today; use the forecast for later dates")
+	case to.Sub(from) >= MaxHistoryDays*24*time.Hour:
+		return fmt.Errorf("history covers at most %d days at a time", MaxHistoryDays)
+	}
+	return nil
+}
+
+// day returns one day's observations at location, from the cache when the
+// coordinates of the place it resolves to are known.
+func (h *History) day(ctx context.Context, location, place string, date time.Time) (*forecast.Response, error) {
+	if place != "" && h.cacheable(location) && !h.refresh {
+		if data := h.cache.Get(dayKey(place, date)); data != nil {
+			return data, nil
+		}
+	}
+	if h.offline {
+		return nil, fmt.Errorf("no history for %s on %s: %w", location, date.Format("2006-01-02"), cache.ErrNotCached)
+	}
+
+	data, err := h.fetcher.History(ctx, location, date)
+	if err != nil {
+		return nil, redact.Error(err)
+	}
+	if len(data.Days) == 0 {
+		return nil, fmt.Errorf("no history for %s on %s", location, date.Format("2006-01-02"))
+	}
+
+	if h.cacheable(location) && h.final(date) {
+		if err := h.cache.Set(dayKey(coordinates(data.Location), date), data); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to cache data: %v\n", err)
+		}
+	}
+	return data, nil
+}
+
+// dayKey returns the cache key of the observations on date at coords.
+func dayKey(coords string, date time.Time) string {
+	return coords + "|" + date.Format("2006-01-02")
+}
+
+// coordinates returns the cache form of loc's coordinates.
+func coordinates(loc forecast.Location) string {
+	return geocode.FormatCoordinates(loc.Lat, loc.Lon)
+}
+
+// final reports whether date has ended everywhere, so its observations
+// will not change. That is up to a day after it ends locally.
+func (h *History) final(date time.Time) bool {
+	return !date.AddDate(0, 0, 2).After(h.today())
+}
+
+// today returns the current local date at midnight UTC, matching the dates
+// parsed from the command line.
+func (h *History) today() time.Time {
+	year, month, day := h.now().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
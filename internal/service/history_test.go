@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/api/weather"
+	"github.com/jtotty/weather-cli/internal/cache"
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+// mockHistory implements HistoryFetcher, returning one day per call.
+type mockHistory struct {
+	calls []string
+	err   error
+}
+
+func (m *mockHistory) History(_ context.Context, location string, date time.Time) (*forecast.Response, error) {
+	day := date.Format("2006-01-02")
+	m.calls = append(m.calls, day)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &forecast.Response{
+		Location: forecast.Location{Name: location, Lat: 51.5171, Lon: -0.1062},
+		Days:     []forecast.Day{{Date: day}},
+	}, nil
+}
+
+func date(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func newTestHistory(fetcher HistoryFetcher, c WeatherCache) *History {
+	h := NewHistoryWithDeps(fetcher, c)
+	h.now = func() time.Time { return time.Date(2026, 9, 10, 15, 0, 0, 0, time.Local) }
+	return h
+}
+
+func TestGetHistory_Range(t *testing.T) {
+	fetcher := &mockHistory{}
+	c := newMockCache()
+	h := newTestHistory(fetcher, c)
+
+	got, err := h.GetHistory(context.Background(), "London", date("2026-09-06"), date("2026-09-10"))
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+
+	var dates []string
+	for _, fd := range got.Days {
+		dates = append(dates, fd.Date)
+	}
+	if strings.Join(dates, " ") != "2026-09-06 2026-09-07 2026-09-08 2026-09-09 2026-09-10" {
+		t.Errorf("days = %v, want 6th to 10th", dates)
+	}
+	if got.Location.Name != "London" || got.Provider != weather.ProviderName {
+		t.Errorf("location = %q, provider = %q", got.Location.Name, got.Provider)
+	}
+
+	// The last two days may still change, so only the first three are
+	// cached, under the place's coordinates, along with where London is.
+	var cached []string
+	for _, call := range c.setCalls {
+		cached = append(cached, call.location)
+	}
+	want := "51.5171,-0.1062|2026-09-06 London 51.5171,-0.1062|2026-09-07 51.5171,-0.1062|2026-09-08"
+	if strings.Join(cached, " ") != want {
+		t.Errorf("cached %v, want %v", cached, want)
+	}
+
+	fetcher.calls = nil
+	if _, err := h.GetHistory(context.Background(), "London", date("2026-09-06"), date("2026-09-10")); err != nil {
+		t.Fatalf("second GetHistory() error = %v", err)
+	}
+	if strings.Join(fetcher.calls, " ") != "2026-09-09 2026-09-10" {
+		t.Errorf("second lookup fetched %v, want only the unfinished days", fetcher.calls)
+	}
+}
+
+func TestGetHistory_SharedByPlace(t *testing.T) {
+	fetcher := &mockHistory{}
+	c := newMockCache()
+	h := newTestHistory(fetcher, c)
+
+	if _, err := h.GetHistory(context.Background(), "London", date("2026-09-01"), date("2026-09-03")); err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+
+	// Another spelling of the same place is only looked up until it is
+	// known to resolve there.
+	fetcher.calls = nil
+	if _, err := h.GetHistory(context.Background(), "@work", date("2026-09-01"), date("2026-09-03")); err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if strings.Join(fetcher.calls, " ") != "2026-09-01" {
+		t.Errorf("second spelling fetched %v, want only the first day", fetcher.calls)
+	}
+}
+
+func TestGetHistory_IPLocationNotCached(t *testing.T) {
+	c := newMockCache()
+
+	if _, err := newTestHistory(&mockHistory{}, c).GetHistory(context.Background(), "auto:ip", date("2026-09-01"), date("2026-09-02")); err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(c.setCalls) != 0 || len(c.getCalls) != 0 {
+		t.Errorf("cache gets %v, sets %d; want the cache left alone for an IP lookup", c.getCalls, len(c.setCalls))
+	}
+}
+
+func TestGetHistory_InvalidRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		wantErr  string
+	}{
+		{"reversed", "2026-09-05", "2026-09-01", "must not be before"},
+		{"future", "2026-09-10", "2026-09-11", "up to today"},
+		{"too early", "2009-12-31", "2010-01-01", "only available from 2010-01-01"},
+		{"too long", "2026-08-01", "2026-09-01", "at most 31 days"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := &mockHistory{}
+			_, err := newTestHistory(fetcher, nil).GetHistory(context.Background(), "London", date(tt.from), date(tt.to))
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("GetHistory() error = %v, want %q", err, tt.wantErr)
+			}
+			if len(fetcher.calls) != 0 {
+				t.Errorf("fetched %v for an invalid range", fetcher.calls)
+			}
+		})
+	}
+}
+
+func TestGetHistory_FetchError(t *testing.T) {
+	fetcher := &mockHistory{err: weather.ErrNotInPlan}
+	c := newMockCache()
+
+	_, err := newTestHistory(fetcher, c).GetHistory(context.Background(), "London", date("2026-01-01"), date("2026-01-03"))
+
+	if !errors.Is(err, weather.ErrNotInPlan) {
+		t.Errorf("GetHistory() error = %v, want ErrNotInPlan", err)
+	}
+	if len(fetcher.calls) != 1 || len(c.setCalls) != 0 {
+		t.Errorf("calls = %v, cached = %d; want to stop at the first failure", fetcher.calls, len(c.setCalls))
+	}
+}
+
+func TestGetHistory_Offline(t *testing.T) {
+	fetcher := &mockHistory{}
+	c := newMockCache()
+	london := forecast.Location{Name: "London", Lat: 51.5171, Lon: -0.1062}
+	c.data["London"] = &forecast.Response{Location: london}
+	c.data["51.5171,-0.1062|2026-01-01"] = &forecast.Response{
+		Location: london,
+		Days:     []forecast.Day{{Date: "2026-01-01"}},
+	}
+	h := newTestHistory(fetcher, c)
+	h.offline = true
+
+	if _, err := h.GetHistory(context.Background(), "London", date("2026-01-01"), date("2026-01-01")); err != nil {
+		t.Errorf("GetHistory() of a cached day error = %v", err)
+	}
+	if len(c.setCalls) != 0 {
+		t.Errorf("cached %+v offline, want nothing", c.setCalls)
+	}
+
+	_, err := h.GetHistory(context.Background(), "London", date("2026-01-01"), date("2026-01-02"))
+	if !errors.Is(err, cache.ErrNotCached) || len(fetcher.calls) != 0 {
+		t.Errorf("GetHistory() error = %v, calls = %v; want ErrNotCached without fetching", err, fetcher.calls)
+	}
+}
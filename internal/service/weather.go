@@ -3,86 +3,402 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"sync"
+	"time"
 
+	"github.com/jtotty/weather-cli/internal/api/httpjson"
 	"github.com/jtotty/weather-cli/internal/api/weather"
+	"github.com/jtotty/weather-cli/internal/breaker"
 	"github.com/jtotty/weather-cli/internal/cache"
 	"github.com/jtotty/weather-cli/internal/config"
+	"github.com/jtotty/weather-cli/internal/credentials"
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/provider"
+	"github.com/jtotty/weather-cli/internal/redact"
 )
 
 // WeatherFetcher defines the interface for fetching weather data.
 type WeatherFetcher interface {
-	Fetch(ctx context.Context, opts weather.FetchOptions) (*weather.Response, error)
+	Name() string
+	Fetch(ctx context.Context, opts forecast.Options) (*forecast.Response, error)
 }
 
-// WeatherCache defines the interface for caching weather data.
+// CurrentFetcher is implemented by providers that can fetch the current
+// conditions alone, a smaller request than a full forecast.
+type CurrentFetcher interface {
+	FetchCurrent(ctx context.Context, opts forecast.Options) (*forecast.Response, error)
+}
+
+// ForecastCache defines the interface for caching forecasts by the options
+// they were fetched with. Lookup also reports whether the current
+// conditions are fresh, as they expire before the rest of the forecast;
+// LookupAstro finds a forecast whose astronomy alone is still fresh.
+type ForecastCache interface {
+	Lookup(key cache.Key) (data *forecast.Response, current bool)
+	LookupAstro(key cache.Key) *forecast.Response
+	Stale(key cache.Key) (*forecast.Response, time.Time)
+	Store(key cache.Key, location string, data *forecast.Response) error
+	StoreCurrent(key cache.Key, data *forecast.Response) error
+}
+
+// WeatherCache defines the interface for caching weather data by a plain key.
 type WeatherCache interface {
-	Get(location string) *weather.Response
-	Set(location string, data *weather.Response) error
+	Get(location string) *forecast.Response
+	Set(location string, data *forecast.Response) error
 }
 
-// Weather orchestrates fetching weather data with caching.
+// ProviderBreaker defines the interface for skipping unhealthy providers.
+type ProviderBreaker interface {
+	Allow(name string) bool
+	RecordFailure(name string) error
+	RecordSuccess(name string) error
+}
+
+// Weather orchestrates fetching weather data with caching, trying each
+// configured provider in order until one answers.
 type Weather struct {
-	cfg     *config.Config
-	cache   WeatherCache
-	fetcher WeatherFetcher
+	cfg      *config.Config
+	cache    ForecastCache
+	fetchers []WeatherFetcher
+	breaker  ProviderBreaker
+
+	// mu guards the cache and breaker, which are not safe for concurrent
+	// use, when several locations are fetched at once.
+	mu sync.Mutex
 }
 
-// NewWeather creates a new Weather service with default cache and API client.
-func NewWeather(cfg *config.Config) *Weather {
-	weatherCache, err := cache.New(cache.DefaultTTL)
+// NewWeather creates a new Weather service with the default cache and the
+// provider chain selected in cfg.
+func NewWeather(cfg *config.Config) (*Weather, error) {
+	fetchers, err := newFetchers(cfg)
+	if err != nil && !cfg.Offline {
+		return nil, err
+	}
+
+	weatherCache, err := cache.NewForecast(cfg.CacheTTLs())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: cache unavailable: %v\n", err)
 	}
 
-	var cacheImpl WeatherCache
+	var cacheImpl ForecastCache
 	if weatherCache != nil {
 		cacheImpl = weatherCache
 	}
 
-	return &Weather{
-		cfg:     cfg,
-		cache:   cacheImpl,
-		fetcher: weather.NewClient(cfg.APIKey),
+	var breakerImpl ProviderBreaker
+	if dir, err := cache.Dir(); err == nil {
+		breakerImpl = breaker.New(dir, cfg.BreakerThreshold, cfg.BreakerWindow)
 	}
+
+	return &Weather{
+		cfg:      cfg,
+		cache:    cacheImpl,
+		fetchers: fetchers,
+		breaker:  breakerImpl,
+	}, nil
 }
 
 // NewWeatherWithDeps creates a Weather service with injected dependencies (for testing).
-func NewWeatherWithDeps(cfg *config.Config, c WeatherCache, fetcher WeatherFetcher) *Weather {
+// Fetchers are tried in the order given.
+func NewWeatherWithDeps(cfg *config.Config, c ForecastCache, fetchers ...WeatherFetcher) *Weather {
 	return &Weather{
-		cfg:     cfg,
-		cache:   c,
-		fetcher: fetcher,
+		cfg:      cfg,
+		cache:    c,
+		fetchers: fetchers,
+	}
+}
+
+// WithBreaker sets the circuit breaker consulted before each provider.
+func (w *Weather) WithBreaker(b ProviderBreaker) *Weather {
+	w.breaker = b
+	return w
+}
+
+// newFetchers builds the provider chain, leaving out providers that need an
+// API key when none is configured.
+func newFetchers(cfg *config.Config) ([]WeatherFetcher, error) {
+	var fetchers []WeatherFetcher
+	for _, name := range cfg.ProviderChain() {
+		if cfg.APIKey == "" && provider.RequiresAPIKey(name) {
+			continue
+		}
+
+		p, err := provider.New(name, cfg.APIKey)
+		if err != nil {
+			return nil, err
+		}
+		if client, ok := p.(*weather.Client); ok {
+			client.WithRetry(cfg.RetryPolicy())
+		}
+		fetchers = append(fetchers, p)
+	}
+
+	if len(fetchers) == 0 {
+		return nil, credentials.ErrNoAPIKey
 	}
+
+	return fetchers, nil
 }
 
-func (w *Weather) GetWeather(ctx context.Context) (*weather.Response, error) {
-	if w.cache != nil {
-		if data := w.cache.Get(w.cfg.Location); data != nil {
+// GetWeather returns the forecast for the configured location.
+func (w *Weather) GetWeather(ctx context.Context) (*forecast.Response, error) {
+	return w.GetWeatherFor(ctx, w.cfg.Location)
+}
+
+// GetWeatherFor returns the forecast for location, using the other settings
+// from the configuration. A cached forecast whose current conditions alone
+// have expired is served after refreshing just those. When no provider can
+// be reached it falls back to an expired cache entry, marked Stale. It is
+// safe for concurrent use.
+func (w *Weather) GetWeatherFor(ctx context.Context, location string) (*forecast.Response, error) {
+	opts := w.fetchOptions(location)
+	key := cache.NewKey(opts, w.cfg.ProviderChain())
+
+	if !w.cfg.Refresh {
+		if data := w.fromCache(ctx, key, opts); data != nil {
 			return data, nil
 		}
 	}
 
-	data, err := w.fetchFromAPI(ctx)
-	if err != nil {
-		return nil, err
+	if w.cfg.Offline {
+		if data := w.stale(key); data != nil {
+			return data, nil
+		}
+		return nil, fmt.Errorf("no forecast for %s: %w", location, cache.ErrNotCached)
 	}
 
-	if w.cache != nil {
-		if cacheErr := w.cache.Set(w.cfg.Location, data); cacheErr != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to cache data: %v\n", cacheErr)
+	data, err := w.fetchFromAPI(ctx, opts)
+	if err != nil {
+		if stale := w.stale(key); stale != nil && isProviderFailure(err) {
+			return stale, nil
 		}
+		return nil, redact.Error(err, w.cfg.APIKey)
 	}
 
+	w.store(key, w.label(location), data)
 	return data, nil
 }
 
-func (w *Weather) fetchFromAPI(ctx context.Context) (*weather.Response, error) {
-	return w.fetcher.Fetch(ctx, weather.FetchOptions{
-		Location:   w.cfg.Location,
+// GetAstronomy returns the forecast for the configured location for showing
+// its sun and moon data alone. A cached forecast whose astronomy is still
+// fresh is served even if the rest of it has expired; otherwise it is
+// fetched as by GetWeather.
+func (w *Weather) GetAstronomy(ctx context.Context) (*forecast.Response, error) {
+	if w.cache != nil && !w.cfg.Refresh {
+		key := cache.NewKey(w.fetchOptions(w.cfg.Location), w.cfg.ProviderChain())
+
+		w.mu.Lock()
+		data := w.cache.LookupAstro(key)
+		w.mu.Unlock()
+		if data != nil {
+			return data, nil
+		}
+	}
+	return w.GetWeather(ctx)
+}
+
+// label names location's cache entry: the text the user typed when the
+// configured location was pinned to coordinates, or location itself.
+func (w *Weather) label(location string) string {
+	if location == w.cfg.Location && w.cfg.LocationQuery != "" {
+		return w.cfg.LocationQuery
+	}
+	return location
+}
+
+// fetchOptions returns the request for location built from the configuration.
+func (w *Weather) fetchOptions(location string) forecast.Options {
+	return forecast.Options{
+		Location:   location,
 		Days:       w.cfg.Days,
 		IncludeAQI: w.cfg.IncludeAQI,
 		Alerts:     w.cfg.Alerts,
-	})
+	}
+}
+
+// fromCache returns the cached forecast for key, first refreshing its
+// current conditions if they alone have expired. It returns nil when the
+// forecast has to be fetched in full.
+func (w *Weather) fromCache(ctx context.Context, key cache.Key, opts forecast.Options) *forecast.Response {
+	data, current := w.cached(key)
+	if data == nil || current {
+		return data
+	}
+	if w.cfg.Offline {
+		return nil
+	}
+	return w.refreshCurrent(ctx, key, opts, data)
+}
+
+func (w *Weather) cached(key cache.Key) (*forecast.Response, bool) {
+	if w.cache == nil {
+		return nil, false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.cache.Lookup(key)
+}
+
+// refreshCurrent fetches the current conditions for a cached forecast from
+// the provider that supplied it and returns the forecast with them swapped
+// in. It returns nil when that provider cannot fetch them alone or fails,
+// leaving a full fetch to try the whole chain.
+func (w *Weather) refreshCurrent(ctx context.Context, key cache.Key, opts forecast.Options, data *forecast.Response) *forecast.Response {
+	fetcher := w.currentFetcher(data.Provider)
+	if fetcher == nil {
+		return nil
+	}
+
+	fresh, err := fetcher.FetchCurrent(ctx, opts)
+	if err != nil {
+		if isProviderFailure(err) {
+			w.recordFailure(data.Provider)
+		}
+		return nil
+	}
+	w.recordSuccess(data.Provider)
+
+	refreshed := *data
+	refreshed.Current = fresh.Current
+	refreshed.Location.LocalTime = fresh.Location.LocalTime
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.cache.StoreCurrent(key, &refreshed); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache data: %v\n", err)
+	}
+	return &refreshed
+}
+
+// currentFetcher returns the available provider called name if it can
+// fetch current conditions alone, or nil.
+func (w *Weather) currentFetcher(name string) CurrentFetcher {
+	for _, fetcher := range w.available() {
+		if current, ok := fetcher.(CurrentFetcher); ok && fetcher.Name() == name {
+			return current
+		}
+	}
+	return nil
+}
+
+// stale returns the newest cached forecast for key whatever its age, marked
+// Stale, or nil when there is none.
+func (w *Weather) stale(key cache.Key) *forecast.Response {
+	if w.cache == nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	data, cachedAt := w.cache.Stale(key)
+	if data == nil {
+		return nil
+	}
+
+	stale := *data
+	stale.FetchedAt = cachedAt
+	stale.Stale = true
+	return &stale
+}
+
+func (w *Weather) store(key cache.Key, location string, data *forecast.Response) {
+	if w.cache == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.cache.Store(key, location, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache data: %v\n", err)
+	}
+}
+
+func (w *Weather) fetchFromAPI(ctx context.Context, opts forecast.Options) (*forecast.Response, error) {
+	var errs []error
+	var lastErr error
+	for _, fetcher := range w.available() {
+		name := fetcher.Name()
+
+		data, err := fetcher.Fetch(ctx, opts)
+		if err == nil {
+			w.recordSuccess(name)
+			data.Provider = name
+			return data, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		if isProviderFailure(err) {
+			w.recordFailure(name)
+		}
+
+		lastErr = err
+		errs = append(errs, fmt.Errorf("%s: %w", name, err))
+	}
+
+	if len(errs) == 1 {
+		return nil, lastErr
+	}
+
+	return nil, fmt.Errorf("all weather providers failed: %w", errors.Join(errs...))
+}
+
+// available returns the fetchers whose breaker is closed. If every breaker is
+// open the whole chain is returned, as a stale failure beats no attempt.
+func (w *Weather) available() []WeatherFetcher {
+	if w.breaker == nil {
+		return w.fetchers
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var allowed []WeatherFetcher
+	for _, fetcher := range w.fetchers {
+		if w.breaker.Allow(fetcher.Name()) {
+			allowed = append(allowed, fetcher)
+		}
+	}
+
+	if len(allowed) == 0 {
+		return w.fetchers
+	}
+	return allowed
+}
+
+func (w *Weather) recordSuccess(name string) {
+	if w.breaker == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.breaker.RecordSuccess(name); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save provider state: %v\n", err)
+	}
+}
+
+func (w *Weather) recordFailure(name string) {
+	if w.breaker == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.breaker.RecordFailure(name); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save provider state: %v\n", err)
+	}
+}
+
+// isProviderFailure reports whether err reflects the provider's health
+// (network errors, timeouts, rate limits, exhausted quotas, server errors)
+// rather than a bad request such as an unknown location.
+func isProviderFailure(err error) bool {
+	return errors.Is(err, weather.ErrQuotaExceeded) || httpjson.Retryable(err)
 }
@@ -3,38 +3,57 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/jtotty/weather-cli/internal/api/httpjson"
 	"github.com/jtotty/weather-cli/internal/api/weather"
+	"github.com/jtotty/weather-cli/internal/cache"
 	"github.com/jtotty/weather-cli/internal/config"
+	"github.com/jtotty/weather-cli/internal/credentials"
+	"github.com/jtotty/weather-cli/internal/forecast"
 )
 
-// mockCache implements WeatherCache for testing.
+// mockCache implements ForecastCache and WeatherCache for testing. Forecasts
+// are looked up by their key's location; expired ones are only returned by
+// Stale, and those in oldCurrent have expired current conditions.
 type mockCache struct {
-	data     map[string]*weather.Response
-	getCalls []string
-	setCalls []setCacheCall
-	setError error
+	data         map[string]*forecast.Response
+	expired      map[string]*forecast.Response
+	oldCurrent   map[string]bool
+	astro        map[string]*forecast.Response
+	getCalls     []string
+	lookups      []cache.Key
+	setCalls     []setCacheCall
+	currentCalls []setCacheCall
+	setError     error
 }
 
 type setCacheCall struct {
+	key      cache.Key
 	location string
-	data     *weather.Response
+	data     *forecast.Response
 }
 
 func newMockCache() *mockCache {
 	return &mockCache{
-		data: make(map[string]*weather.Response),
+		data:       make(map[string]*forecast.Response),
+		expired:    make(map[string]*forecast.Response),
+		oldCurrent: make(map[string]bool),
+		astro:      make(map[string]*forecast.Response),
 	}
 }
 
-func (m *mockCache) Get(location string) *weather.Response {
+func (m *mockCache) Get(location string) *forecast.Response {
 	m.getCalls = append(m.getCalls, location)
 	return m.data[location]
 }
 
-func (m *mockCache) Set(location string, data *weather.Response) error {
-	m.setCalls = append(m.setCalls, setCacheCall{location, data})
+func (m *mockCache) Set(location string, data *forecast.Response) error {
+	m.setCalls = append(m.setCalls, setCacheCall{location: location, data: data})
 	if m.setError != nil {
 		return m.setError
 	}
@@ -42,18 +61,74 @@ func (m *mockCache) Set(location string, data *weather.Response) error {
 	return nil
 }
 
-// mockFetcher implements WeatherFetcher for testing.
+func (m *mockCache) Lookup(key cache.Key) (*forecast.Response, bool) {
+	m.lookups = append(m.lookups, key)
+	return m.data[key.Location], !m.oldCurrent[key.Location]
+}
+
+func (m *mockCache) LookupAstro(key cache.Key) *forecast.Response {
+	if data, ok := m.data[key.Location]; ok {
+		return data
+	}
+	return m.astro[key.Location]
+}
+
+// expiredAt is when every expired mock cache entry was cached.
+var expiredAt = time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func (m *mockCache) Stale(key cache.Key) (*forecast.Response, time.Time) {
+	if data, ok := m.data[key.Location]; ok {
+		return data, time.Now()
+	}
+	if data, ok := m.expired[key.Location]; ok {
+		return data, expiredAt
+	}
+	return nil, time.Time{}
+}
+
+func (m *mockCache) Store(key cache.Key, location string, data *forecast.Response) error {
+	m.setCalls = append(m.setCalls, setCacheCall{key, location, data})
+	if m.setError != nil {
+		return m.setError
+	}
+	m.data[key.Location] = data
+	return nil
+}
+
+func (m *mockCache) StoreCurrent(key cache.Key, data *forecast.Response) error {
+	m.currentCalls = append(m.currentCalls, setCacheCall{key: key, data: data})
+	delete(m.oldCurrent, key.Location)
+	return m.setError
+}
+
+// mockFetcher implements WeatherFetcher and CurrentFetcher for testing.
 type mockFetcher struct {
-	response   *weather.Response
-	err        error
-	fetchCalls []weather.FetchOptions
+	name         string
+	response     *forecast.Response
+	err          error
+	fetchCalls   []forecast.Options
+	current      *forecast.Response
+	currentErr   error
+	currentCalls []forecast.Options
 }
 
-func (m *mockFetcher) Fetch(ctx context.Context, opts weather.FetchOptions) (*weather.Response, error) {
+func (m *mockFetcher) Name() string {
+	if m.name == "" {
+		return "mock"
+	}
+	return m.name
+}
+
+func (m *mockFetcher) Fetch(ctx context.Context, opts forecast.Options) (*forecast.Response, error) {
 	m.fetchCalls = append(m.fetchCalls, opts)
 	return m.response, m.err
 }
 
+func (m *mockFetcher) FetchCurrent(ctx context.Context, opts forecast.Options) (*forecast.Response, error) {
+	m.currentCalls = append(m.currentCalls, opts)
+	return m.current, m.currentErr
+}
+
 func TestNewWeather(t *testing.T) {
 	cfg := &config.Config{
 		APIKey:     "test-key",
@@ -63,7 +138,10 @@ func TestNewWeather(t *testing.T) {
 		Alerts:     true,
 	}
 
-	svc := NewWeather(cfg)
+	svc, err := NewWeather(cfg)
+	if err != nil {
+		t.Fatalf("NewWeather() error = %v", err)
+	}
 
 	if svc == nil {
 		t.Fatal("NewWeather() returned nil")
@@ -74,6 +152,18 @@ func TestNewWeather(t *testing.T) {
 	}
 }
 
+func TestNewWeather_UnknownProvider(t *testing.T) {
+	cfg := &config.Config{
+		Providers: []string{"accuweather"},
+		Location:  "London",
+		Days:      3,
+	}
+
+	if _, err := NewWeather(cfg); err == nil {
+		t.Error("NewWeather() expected error for unknown provider, got nil")
+	}
+}
+
 func TestGetWeather_CacheHit(t *testing.T) {
 	cfg := &config.Config{
 		APIKey:   "test-key",
@@ -81,13 +171,13 @@ func TestGetWeather_CacheHit(t *testing.T) {
 		Days:     3,
 	}
 
-	cachedResponse := &weather.Response{
-		Location: weather.Location{Name: "London", Country: "UK"},
-		Current:  weather.Current{TempC: 15},
+	cachedResponse := &forecast.Response{
+		Location: forecast.Location{Name: "London", Country: "UK"},
+		Current:  forecast.Current{TempC: 15},
 	}
 
 	mockCache := newMockCache()
-	mockCache.data["London"] = cachedResponse
+	mockCache.data["london"] = cachedResponse
 
 	mockFetcher := &mockFetcher{}
 
@@ -102,8 +192,8 @@ func TestGetWeather_CacheHit(t *testing.T) {
 		t.Error("GetWeather() did not return cached response")
 	}
 
-	if len(mockCache.getCalls) != 1 || mockCache.getCalls[0] != "London" {
-		t.Errorf("Expected cache.Get(\"London\"), got %v", mockCache.getCalls)
+	if len(mockCache.lookups) != 1 || mockCache.lookups[0].Location != "london" {
+		t.Errorf("Expected a cache lookup for london, got %v", mockCache.lookups)
 	}
 
 	if len(mockFetcher.fetchCalls) != 0 {
@@ -111,6 +201,35 @@ func TestGetWeather_CacheHit(t *testing.T) {
 	}
 }
 
+func TestGetAstronomy(t *testing.T) {
+	cfg := &config.Config{APIKey: "test-key", Location: "Tromso", Days: 1}
+	cached := &forecast.Response{Location: forecast.Location{Name: "Tromsø"}}
+	fetched := &forecast.Response{Location: forecast.Location{Name: "Tromsø"}}
+
+	mockCache := newMockCache()
+	mockCache.astro["tromso"] = cached
+	mockFetcher := &mockFetcher{response: fetched}
+	svc := NewWeatherWithDeps(cfg, mockCache, mockFetcher)
+
+	// The forecast has expired but its astronomy has not.
+	if got, err := svc.GetAstronomy(context.Background()); err != nil || got != cached {
+		t.Errorf("GetAstronomy() = %p, %v; want the cached astronomy", got, err)
+	}
+	if len(mockFetcher.fetchCalls) != 0 {
+		t.Errorf("fetched %d times, want none while the astronomy is fresh", len(mockFetcher.fetchCalls))
+	}
+
+	if got, err := svc.GetWeather(context.Background()); err != nil || got != fetched {
+		t.Errorf("GetWeather() = %p, %v; want a fresh fetch", got, err)
+	}
+
+	cfg.Refresh = true
+	mockFetcher.fetchCalls = nil
+	if _, err := svc.GetAstronomy(context.Background()); err != nil || len(mockFetcher.fetchCalls) != 1 {
+		t.Errorf("GetAstronomy() with Refresh: err = %v, fetches = %d; want one fetch", err, len(mockFetcher.fetchCalls))
+	}
+}
+
 func TestGetWeather_CacheMiss(t *testing.T) {
 	cfg := &config.Config{
 		APIKey:     "test-key",
@@ -120,9 +239,9 @@ func TestGetWeather_CacheMiss(t *testing.T) {
 		Alerts:     true,
 	}
 
-	apiResponse := &weather.Response{
-		Location: weather.Location{Name: "Paris", Country: "France"},
-		Current:  weather.Current{TempC: 20},
+	apiResponse := &forecast.Response{
+		Location: forecast.Location{Name: "Paris", Country: "France"},
+		Current:  forecast.Current{TempC: 20},
 	}
 
 	mockCache := newMockCache() // Empty cache
@@ -139,8 +258,8 @@ func TestGetWeather_CacheMiss(t *testing.T) {
 		t.Error("GetWeather() did not return API response")
 	}
 
-	if len(mockCache.getCalls) != 1 {
-		t.Errorf("Expected 1 cache.Get call, got %d", len(mockCache.getCalls))
+	if len(mockCache.lookups) != 1 {
+		t.Errorf("Expected 1 cache lookup, got %d", len(mockCache.lookups))
 	}
 
 	if len(mockFetcher.fetchCalls) != 1 {
@@ -170,6 +289,27 @@ func TestGetWeather_CacheMiss(t *testing.T) {
 	if mockCache.setCalls[0].data != apiResponse {
 		t.Error("cache.Set did not receive correct data")
 	}
+	want := cache.Key{Location: "paris", Provider: "weatherapi", Days: 5, AQI: true, Alerts: true}
+	if got := mockCache.setCalls[0].key; got != want {
+		t.Errorf("cache key = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetWeather_CachesUnderTypedLocation(t *testing.T) {
+	cfg := &config.Config{APIKey: "test-key", Location: "48.86,2.35", LocationQuery: "paris", Days: 1}
+	mockCache := newMockCache()
+	mockFetcher := &mockFetcher{response: &forecast.Response{Location: forecast.Location{Name: "Paris"}}}
+
+	if _, err := NewWeatherWithDeps(cfg, mockCache, mockFetcher).GetWeather(context.Background()); err != nil {
+		t.Fatalf("GetWeather() error = %v", err)
+	}
+
+	if got := mockFetcher.fetchCalls[0].Location; got != "48.86,2.35" {
+		t.Errorf("Fetch Location = %q, want the pinned coordinates", got)
+	}
+	if len(mockCache.setCalls) != 1 || mockCache.setCalls[0].location != "paris" {
+		t.Errorf("cache.Store calls = %+v, want the entry labelled with the typed location", mockCache.setCalls)
+	}
 }
 
 func TestGetWeather_APIError(t *testing.T) {
@@ -199,6 +339,21 @@ func TestGetWeather_APIError(t *testing.T) {
 	}
 }
 
+func TestGetWeather_RedactsAPIKey(t *testing.T) {
+	cfg := &config.Config{APIKey: "service-secret-key", Location: "London", Days: 1}
+	leaky := fmt.Errorf("mock: request for key=%s failed: %w", cfg.APIKey, context.DeadlineExceeded)
+	svc := NewWeatherWithDeps(cfg, nil, &mockFetcher{err: leaky})
+
+	_, err := svc.GetWeather(context.Background())
+
+	if err == nil || strings.Contains(err.Error(), cfg.APIKey) {
+		t.Errorf("GetWeather() error = %v, want the key redacted", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetWeather() error = %v, want it to still wrap the cause", err)
+	}
+}
+
 func TestGetWeather_CacheSetError(t *testing.T) {
 	cfg := &config.Config{
 		APIKey:   "test-key",
@@ -206,8 +361,8 @@ func TestGetWeather_CacheSetError(t *testing.T) {
 		Days:     1,
 	}
 
-	apiResponse := &weather.Response{
-		Location: weather.Location{Name: "London"},
+	apiResponse := &forecast.Response{
+		Location: forecast.Location{Name: "London"},
 	}
 
 	mockCache := newMockCache()
@@ -234,8 +389,8 @@ func TestGetWeather_NilCache(t *testing.T) {
 		Days:     1,
 	}
 
-	apiResponse := &weather.Response{
-		Location: weather.Location{Name: "Tokyo"},
+	apiResponse := &forecast.Response{
+		Location: forecast.Location{Name: "Tokyo"},
 	}
 
 	mockFetcher := &mockFetcher{response: apiResponse}
@@ -277,3 +432,290 @@ func TestGetWeather_ContextCancellation(t *testing.T) {
 		t.Errorf("GetWeather() error = %v, want context.Canceled", err)
 	}
 }
+
+// mockBreaker implements ProviderBreaker for testing.
+type mockBreaker struct {
+	open      map[string]bool
+	failures  []string
+	successes []string
+}
+
+func (m *mockBreaker) Allow(name string) bool {
+	return !m.open[name]
+}
+
+func (m *mockBreaker) RecordFailure(name string) error {
+	m.failures = append(m.failures, name)
+	return nil
+}
+
+func (m *mockBreaker) RecordSuccess(name string) error {
+	m.successes = append(m.successes, name)
+	return nil
+}
+
+func TestGetWeather_Failover(t *testing.T) {
+	cfg := &config.Config{Location: "London", Days: 3}
+
+	primary := &mockFetcher{
+		name: "weatherapi",
+		err:  &httpjson.StatusError{StatusCode: http.StatusServiceUnavailable},
+	}
+	secondary := &mockFetcher{
+		name:     "open-meteo",
+		response: &forecast.Response{Location: forecast.Location{Name: "London"}},
+	}
+	b := &mockBreaker{}
+
+	svc := NewWeatherWithDeps(cfg, newMockCache(), primary, secondary).WithBreaker(b)
+
+	result, err := svc.GetWeather(context.Background())
+	if err != nil {
+		t.Fatalf("GetWeather() error = %v", err)
+	}
+
+	if result.Provider != "open-meteo" {
+		t.Errorf("Provider = %q, want %q", result.Provider, "open-meteo")
+	}
+	if len(primary.fetchCalls) != 1 || len(secondary.fetchCalls) != 1 {
+		t.Errorf("fetch calls = %d, %d, want 1, 1", len(primary.fetchCalls), len(secondary.fetchCalls))
+	}
+	if len(b.failures) != 1 || b.failures[0] != "weatherapi" {
+		t.Errorf("breaker failures = %v, want [weatherapi]", b.failures)
+	}
+	if len(b.successes) != 1 || b.successes[0] != "open-meteo" {
+		t.Errorf("breaker successes = %v, want [open-meteo]", b.successes)
+	}
+}
+
+func TestGetWeather_SkipsOpenBreaker(t *testing.T) {
+	cfg := &config.Config{Location: "London", Days: 3}
+
+	primary := &mockFetcher{name: "weatherapi"}
+	secondary := &mockFetcher{
+		name:     "nws",
+		response: &forecast.Response{},
+	}
+	b := &mockBreaker{open: map[string]bool{"weatherapi": true}}
+
+	svc := NewWeatherWithDeps(cfg, nil, primary, secondary).WithBreaker(b)
+
+	result, err := svc.GetWeather(context.Background())
+	if err != nil {
+		t.Fatalf("GetWeather() error = %v", err)
+	}
+
+	if len(primary.fetchCalls) != 0 {
+		t.Error("GetWeather() should skip a provider with an open breaker")
+	}
+	if result.Provider != "nws" {
+		t.Errorf("Provider = %q, want %q", result.Provider, "nws")
+	}
+}
+
+func TestGetWeather_AllBreakersOpen(t *testing.T) {
+	cfg := &config.Config{Location: "London", Days: 3}
+
+	only := &mockFetcher{name: "weatherapi", response: &forecast.Response{}}
+	b := &mockBreaker{open: map[string]bool{"weatherapi": true}}
+
+	svc := NewWeatherWithDeps(cfg, nil, only).WithBreaker(b)
+
+	if _, err := svc.GetWeather(context.Background()); err != nil {
+		t.Fatalf("GetWeather() error = %v", err)
+	}
+	if len(only.fetchCalls) != 1 {
+		t.Error("GetWeather() should still try providers when every breaker is open")
+	}
+}
+
+func TestGetWeather_AllProvidersFail(t *testing.T) {
+	cfg := &config.Config{Location: "London", Days: 3}
+
+	primary := &mockFetcher{name: "weatherapi", err: errors.New("boom")}
+	secondary := &mockFetcher{name: "open-meteo", err: &httpjson.StatusError{StatusCode: http.StatusBadRequest}}
+	b := &mockBreaker{}
+
+	svc := NewWeatherWithDeps(cfg, newMockCache(), primary, secondary).WithBreaker(b)
+
+	_, err := svc.GetWeather(context.Background())
+	if err == nil {
+		t.Fatal("GetWeather() expected error, got nil")
+	}
+
+	for _, name := range []string{"weatherapi", "open-meteo"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error = %q, want mention of %q", err.Error(), name)
+		}
+	}
+
+	// Neither a generic error nor a 400 says anything about provider health.
+	if len(b.failures) != 0 {
+		t.Errorf("breaker failures = %v, want none", b.failures)
+	}
+}
+
+func TestGetWeather_QuotaExceededTripsBreaker(t *testing.T) {
+	cfg := &config.Config{Location: "London", Days: 3}
+
+	quotaErr := &weather.APIError{
+		Code:    2007,
+		Message: "API key has exceeded calls per month quota.",
+		Status:  &httpjson.StatusError{StatusCode: http.StatusForbidden},
+	}
+	primary := &mockFetcher{name: "weatherapi", err: quotaErr}
+	secondary := &mockFetcher{name: "open-meteo", response: &forecast.Response{}}
+	b := &mockBreaker{}
+
+	svc := NewWeatherWithDeps(cfg, nil, primary, secondary).WithBreaker(b)
+
+	if _, err := svc.GetWeather(context.Background()); err != nil {
+		t.Fatalf("GetWeather() error = %v", err)
+	}
+	if len(b.failures) != 1 || b.failures[0] != "weatherapi" {
+		t.Errorf("breaker failures = %v, want [weatherapi]", b.failures)
+	}
+}
+
+func TestNewWeather_SkipsKeyedProvidersWithoutKey(t *testing.T) {
+	cfg := &config.Config{
+		Providers: []string{"weatherapi", "open-meteo"},
+		Location:  "London",
+		Days:      3,
+	}
+
+	svc, err := NewWeather(cfg)
+	if err != nil {
+		t.Fatalf("NewWeather() error = %v", err)
+	}
+
+	if len(svc.fetchers) != 1 || svc.fetchers[0].Name() != "open-meteo" {
+		t.Errorf("fetchers = %d, want only open-meteo", len(svc.fetchers))
+	}
+
+	cfg.Providers = []string{"weatherapi"}
+	if _, err := NewWeather(cfg); !errors.Is(err, credentials.ErrNoAPIKey) {
+		t.Errorf("NewWeather() error = %v, want ErrNoAPIKey", err)
+	}
+}
+
+func TestGetWeather_StaleFallback(t *testing.T) {
+	unavailable := &httpjson.StatusError{StatusCode: http.StatusServiceUnavailable}
+	notFound := fmt.Errorf("lookup failed: %w", forecast.ErrLocationNotFound)
+
+	tests := []struct {
+		name      string
+		offline   bool
+		refresh   bool
+		fresh     bool
+		expired   bool
+		fetchErr  error
+		wantErr   error
+		wantStale bool
+		wantFetch int
+	}{
+		{name: "provider down serves expired entry", expired: true, fetchErr: unavailable, wantStale: true, wantFetch: 1},
+		{name: "provider down without entry", fetchErr: unavailable, wantErr: unavailable, wantFetch: 1},
+		{name: "bad request is not hidden", expired: true, fetchErr: notFound, wantErr: forecast.ErrLocationNotFound, wantFetch: 1},
+		{name: "offline serves expired entry", offline: true, expired: true, wantStale: true},
+		{name: "offline serves fresh entry", offline: true, fresh: true},
+		{name: "offline without entry", offline: true, wantErr: cache.ErrNotCached},
+		{name: "refresh skips fresh entry", refresh: true, fresh: true, wantFetch: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Location: "London", Days: 3, Offline: tt.offline, Refresh: tt.refresh}
+			c := newMockCache()
+			cached := &forecast.Response{Location: forecast.Location{Name: "London"}}
+			if tt.fresh {
+				c.data["london"] = cached
+			}
+			if tt.expired {
+				c.expired["london"] = cached
+			}
+			fetched := &forecast.Response{Location: forecast.Location{Name: "London (fetched)"}}
+			fetcher := &mockFetcher{response: fetched, err: tt.fetchErr}
+
+			result, err := NewWeatherWithDeps(cfg, c, fetcher).GetWeather(context.Background())
+
+			if len(fetcher.fetchCalls) != tt.wantFetch {
+				t.Errorf("fetch calls = %d, want %d", len(fetcher.fetchCalls), tt.wantFetch)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetWeather() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetWeather() error = %v", err)
+			}
+			if result.Stale != tt.wantStale {
+				t.Errorf("Stale = %v, want %v", result.Stale, tt.wantStale)
+			}
+			if tt.wantStale && (!result.FetchedAt.Equal(expiredAt) || cached.Stale) {
+				t.Errorf("FetchedAt = %v, want %v on a copy of the cached data", result.FetchedAt, expiredAt)
+			}
+			if tt.refresh && result != fetched {
+				t.Error("GetWeather() with refresh should return the fetched data")
+			}
+		})
+	}
+}
+
+func TestGetWeather_RefreshesCurrent(t *testing.T) {
+	unavailable := &httpjson.StatusError{StatusCode: http.StatusServiceUnavailable}
+
+	tests := []struct {
+		name        string
+		provider    string
+		currentErr  error
+		wantCurrent int
+		wantFetch   int
+		wantTemp    float32
+	}{
+		{name: "fetches current conditions only", provider: "mock", wantCurrent: 1, wantTemp: 18},
+		{name: "other provider fetches in full", provider: "open-meteo", wantFetch: 1, wantTemp: 20},
+		{name: "failed refresh fetches in full", provider: "mock", currentErr: unavailable, wantCurrent: 1, wantFetch: 1, wantTemp: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Location: "London", Days: 3}
+			c := newMockCache()
+			c.data["london"] = &forecast.Response{
+				Location: forecast.Location{Name: "London"},
+				Current:  forecast.Current{TempC: 10},
+				Days:     make([]forecast.Day, 3),
+				Provider: tt.provider,
+			}
+			c.oldCurrent["london"] = true
+
+			fetcher := &mockFetcher{
+				response:   &forecast.Response{Current: forecast.Current{TempC: 20}},
+				current:    &forecast.Response{Current: forecast.Current{TempC: 18}},
+				currentErr: tt.currentErr,
+			}
+
+			result, err := NewWeatherWithDeps(cfg, c, fetcher).GetWeather(context.Background())
+			if err != nil {
+				t.Fatalf("GetWeather() error = %v", err)
+			}
+
+			if len(fetcher.currentCalls) != tt.wantCurrent || len(fetcher.fetchCalls) != tt.wantFetch {
+				t.Errorf("current calls = %d, fetch calls = %d; want %d and %d",
+					len(fetcher.currentCalls), len(fetcher.fetchCalls), tt.wantCurrent, tt.wantFetch)
+			}
+			if result.Current.TempC != tt.wantTemp {
+				t.Errorf("Current.TempC = %v, want %v", result.Current.TempC, tt.wantTemp)
+			}
+			if tt.wantFetch == 0 {
+				if len(result.Days) != 3 || len(c.currentCalls) != 1 {
+					t.Errorf("got %d forecast days and %d StoreCurrent calls; want the cached forecast and one store",
+						len(result.Days), len(c.currentCalls))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"context"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Key identifies a key press. Printable characters are KeyRune.
+type Key int
+
+const (
+	KeyRune Key = iota
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyPageUp
+	KeyPageDown
+	KeyHome
+	KeyEnd
+	KeyTab
+	KeyBackTab
+	KeyEnter
+	KeyEscape
+	KeyCtrlC
+)
+
+// Event is a single key press read from the terminal.
+type Event struct {
+	Key  Key
+	Rune rune
+}
+
+// escapeKeys maps the escape sequences sent by common terminals, without the
+// leading ESC.
+var escapeKeys = map[string]Key{
+	"[A":  KeyUp,
+	"[B":  KeyDown,
+	"[C":  KeyRight,
+	"[D":  KeyLeft,
+	"OA":  KeyUp,
+	"OB":  KeyDown,
+	"OC":  KeyRight,
+	"OD":  KeyLeft,
+	"[5~": KeyPageUp,
+	"[6~": KeyPageDown,
+	"[H":  KeyHome,
+	"[F":  KeyEnd,
+	"OH":  KeyHome,
+	"OF":  KeyEnd,
+	"[1~": KeyHome,
+	"[4~": KeyEnd,
+	"[Z":  KeyBackTab,
+}
+
+// ParseKeys decodes the key presses in one read from a raw-mode terminal.
+// Unrecognized escape sequences are dropped.
+func ParseKeys(b []byte) []Event {
+	var events []Event
+	s := string(b)
+
+	for len(s) > 0 {
+		if s[0] == '\x1b' {
+			event, n := parseEscape(s[1:])
+			if event != nil {
+				events = append(events, *event)
+			}
+			s = s[1+n:]
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+
+		switch r {
+		case '\r', '\n':
+			events = append(events, Event{Key: KeyEnter})
+		case '\t':
+			events = append(events, Event{Key: KeyTab})
+		case 3:
+			events = append(events, Event{Key: KeyCtrlC})
+		default:
+			if r >= ' ' && r != 0x7f {
+				events = append(events, Event{Key: KeyRune, Rune: r})
+			}
+		}
+	}
+	return events
+}
+
+// parseEscape decodes the sequence following an ESC byte, returning the key
+// and how many bytes it used. A lone ESC is the escape key.
+func parseEscape(s string) (*Event, int) {
+	if s == "" || (s[0] != '[' && s[0] != 'O') {
+		return &Event{Key: KeyEscape}, 0
+	}
+
+	for seq, key := range escapeKeys {
+		if strings.HasPrefix(s, seq) {
+			return &Event{Key: key}, len(seq)
+		}
+	}
+
+	// Skip an unknown CSI sequence up to its final byte.
+	for i := 1; i < len(s); i++ {
+		if s[i] >= 0x40 && s[i] <= 0x7e {
+			return nil, i + 1
+		}
+	}
+	return nil, len(s)
+}
+
+// readKeys reads key presses from r until it fails or ctx is done. The
+// channel is closed when reading stops.
+func readKeys(ctx context.Context, r io.Reader) <-chan []Event {
+	events := make(chan []Event)
+	go func() {
+		defer close(events)
+		buf := make([]byte, 64)
+		for {
+			n, err := r.Read(buf)
+			if n > 0 {
+				select {
+				case events <- ParseKeys(buf[:n]):
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return events
+}
@@ -0,0 +1,506 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/screen"
+	"github.com/jtotty/weather-cli/internal/ui"
+	"github.com/jtotty/weather-cli/internal/units"
+	"github.com/jtotty/weather-cli/internal/weather"
+)
+
+// Tab is one of the views selectable along the top of the screen.
+type Tab int
+
+const (
+	TabCurrent Tab = iota
+	TabHourly
+	TabDaily
+	TabAlerts
+	tabCount
+)
+
+var tabNames = [tabCount]string{"Current", "Hourly", "Daily", "Alerts"}
+
+// Layout constants: the header is the title, tab bar and a rule; the footer
+// is a rule and the key help or status line.
+const (
+	headerLines = 3
+	footerLines = 2
+	// minDetailWidth is the narrowest screen that shows the detail pane
+	// beside the hourly list rather than below it.
+	minDetailWidth = 72
+	detailWidth    = 30
+)
+
+// Location is an entry in the location switcher.
+type Location struct {
+	Label string
+	Query string
+}
+
+// Action tells the caller what to do after an event.
+type Action int
+
+const (
+	ActionNone Action = iota
+	ActionQuit
+	// ActionLoad asks for the forecast for Model.Query.
+	ActionLoad
+)
+
+// Model holds the interface state. It has no I/O of its own: Update applies
+// key presses and View renders the screen.
+type Model struct {
+	data      *forecast.Response
+	units     units.Units
+	locations []Location
+	location  int
+	now       func() time.Time
+
+	tab Tab
+	// selected is the highlighted row of list tabs; offset is the first
+	// visible row (or line, for text tabs) of each tab.
+	selected [tabCount]int
+	offset   [tabCount]int
+
+	switcher  bool
+	switchSel int
+
+	status string
+	err    error
+}
+
+// NewModel creates a model for the given locations; the first is loaded
+// first. now is used to select the current hour.
+func NewModel(locations []Location, u units.Units, now func() time.Time) *Model {
+	return &Model{locations: locations, units: u, now: now}
+}
+
+// Query returns the location to load for ActionLoad.
+func (m *Model) Query() string {
+	return m.locations[m.location].Query
+}
+
+// SetData replaces the forecast, selecting the current hour and resetting
+// scroll positions.
+func (m *Model) SetData(data *forecast.Response) {
+	m.data = data
+	m.err = nil
+	m.status = ""
+	m.selected = [tabCount]int{}
+	m.offset = [tabCount]int{}
+
+	now := m.now()
+	for i, hour := range m.hours() {
+		if time.Unix(hour.TimeUnix, 0).Add(time.Hour).After(now) {
+			m.selected[TabHourly] = i
+			break
+		}
+	}
+}
+
+// SetError records a failed load; the previous forecast stays visible.
+func (m *Model) SetError(err error) {
+	m.err = err
+	m.status = ""
+}
+
+// SetStatus shows a message in place of the key help, e.g. while loading.
+func (m *Model) SetStatus(status string) {
+	m.status = status
+}
+
+// Tab returns the active tab.
+func (m *Model) Tab() Tab {
+	return m.tab
+}
+
+// runeKeys maps vi-style letters to the keys they stand for.
+var runeKeys = map[rune]Key{'k': KeyUp, 'j': KeyDown, 'g': KeyHome, 'G': KeyEnd}
+
+// scrollKeys maps keys to how far they move the selection or view. Home and
+// End move far enough to be clamped to the first or last row.
+var scrollKeys = map[Key]int{
+	KeyUp:       -1,
+	KeyDown:     1,
+	KeyPageUp:   -pageSize,
+	KeyPageDown: pageSize,
+	KeyHome:     -1 << 30,
+	KeyEnd:      1 << 30,
+}
+
+// pageSize is how many rows Page Up and Page Down move.
+const pageSize = 10
+
+// Update applies a key press.
+func (m *Model) Update(ev Event) Action {
+	if key, ok := runeKeys[ev.Rune]; ok && ev.Key == KeyRune {
+		ev = Event{Key: key}
+	}
+
+	switch {
+	case ev.Key == KeyCtrlC:
+		return ActionQuit
+	case m.switcher:
+		return m.updateSwitcher(ev)
+	case ev.Key == KeyRune:
+		return m.updateRune(ev.Rune)
+	case ev.Key == KeyTab || ev.Key == KeyRight:
+		m.tab = (m.tab + 1) % tabCount
+	case ev.Key == KeyBackTab || ev.Key == KeyLeft:
+		m.tab = (m.tab + tabCount - 1) % tabCount
+	default:
+		m.scroll(ev.Key)
+	}
+	return ActionNone
+}
+
+func (m *Model) updateRune(r rune) Action {
+	switch {
+	case r == 'q' || r == 'Q':
+		return ActionQuit
+	case r == 'r':
+		return ActionLoad
+	case r == 'l' && len(m.locations) > 1:
+		m.switcher = true
+		m.switchSel = m.location
+	case r >= '1' && r < '1'+rune(tabCount):
+		m.tab = Tab(r - '1')
+	}
+	return ActionNone
+}
+
+func (m *Model) updateSwitcher(ev Event) Action {
+	switch {
+	case ev.Key == KeyEscape || (ev.Key == KeyRune && (ev.Rune == 'q' || ev.Rune == 'l')):
+		m.switcher = false
+	case ev.Key == KeyEnter:
+		m.switcher = false
+		m.location = m.switchSel
+		return ActionLoad
+	default:
+		m.switchSel = clamp(m.switchSel+scrollKeys[ev.Key], 0, len(m.locations)-1)
+	}
+	return ActionNone
+}
+
+// scroll moves the selection of list tabs, or the view of text tabs.
+// Offsets are clamped to the content when the view is drawn.
+func (m *Model) scroll(key Key) {
+	delta, ok := scrollKeys[key]
+	if !ok {
+		return
+	}
+
+	if n := m.listLen(); n >= 0 {
+		m.selected[m.tab] = clamp(m.selected[m.tab]+delta, 0, n-1)
+		return
+	}
+	m.offset[m.tab] = clamp(m.offset[m.tab]+delta, 0, 1<<30)
+}
+
+// listLen returns the number of rows in a list tab, or -1 for text tabs.
+func (m *Model) listLen() int {
+	switch m.tab {
+	case TabHourly:
+		return len(m.hours())
+	case TabDaily:
+		if m.data == nil {
+			return 0
+		}
+		return len(m.data.Days)
+	default:
+		return -1
+	}
+}
+
+// hours returns every hour of every forecast day, in order.
+func (m *Model) hours() []forecast.Hour {
+	if m.data == nil {
+		return nil
+	}
+	var hours []forecast.Hour
+	for _, day := range m.data.Days {
+		hours = append(hours, day.Hours...)
+	}
+	return hours
+}
+
+// hourTime returns the start of hour on the location's clock, or the
+// viewer's when the provider did not report the time zone.
+func (m *Model) hourTime(hour forecast.Hour) time.Time {
+	t := time.Unix(hour.TimeUnix, 0)
+	if zone, ok := m.data.Location.Zone(); ok {
+		return t.In(zone)
+	}
+	return t
+}
+
+// View renders the screen as width-limited lines, at most height of them.
+func (m *Model) View(width, height int) []string {
+	rows := max(height-headerLines-footerLines, 1)
+
+	lines := []string{m.title(), m.tabBar(), strings.Repeat("─", width)}
+	var body []string
+	if m.switcher {
+		body = m.switcherView(rows)
+	} else {
+		body = m.body(width, rows)
+	}
+	for len(body) < rows {
+		body = append(body, "")
+	}
+	lines = append(lines, body[:rows]...)
+	lines = append(lines, strings.Repeat("─", width), m.footer())
+
+	return screen.Fit(strings.Join(lines, "\n"), width, height)
+}
+
+func (m *Model) title() string {
+	if m.data == nil {
+		return m.locations[m.location].Label
+	}
+
+	title := m.data.Location.Name
+	if m.data.Location.Country != "" {
+		title += ", " + m.data.Location.Country
+	}
+	if m.data.Provider != "" {
+		title += "  (" + m.data.Provider + ")"
+	}
+	if banner := weather.StaleBanner(m.data, m.now()); banner != "" {
+		title += "  " + banner
+	}
+	return title
+}
+
+func (m *Model) tabBar() string {
+	names := make([]string, tabCount)
+	for i, name := range tabNames {
+		label := fmt.Sprintf(" %d %s ", i+1, name)
+		if Tab(i) == m.tab {
+			label = ui.Highlight("[" + strings.TrimSpace(label) + "]")
+		}
+		names[i] = label
+	}
+	return strings.Join(names, " ")
+}
+
+func (m *Model) footer() string {
+	switch {
+	case m.status != "":
+		return m.status
+	case m.err != nil:
+		return "Error: " + m.err.Error()
+	}
+
+	help := "←/→ tabs  ↑/↓ scroll  r refresh  q quit"
+	if len(m.locations) > 1 {
+		help = "←/→ tabs  ↑/↓ scroll  l locations  r refresh  q quit"
+	}
+	return help
+}
+
+func (m *Model) body(width, rows int) []string {
+	if m.data == nil {
+		return []string{"No data available"}
+	}
+
+	switch m.tab {
+	case TabHourly:
+		return m.hourlyView(width, rows)
+	case TabDaily:
+		return m.listView(m.dailyRows(), rows)
+	case TabAlerts:
+		return m.textView(m.alertLines(width), rows)
+	default:
+		return m.textView(m.currentLines(), rows)
+	}
+}
+
+func (m *Model) currentLines() []string {
+	d, err := weather.NewDisplay(m.data, true)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	d.WithUnits(m.units)
+
+	text := d.CurrentConditions() + "\n\n" + d.Twilight()
+	return strings.Split(strings.TrimRight(text, "\n"), "\n")
+}
+
+func (m *Model) alertLines(width int) []string {
+	if len(m.data.Alerts) == 0 {
+		return []string{"No weather warnings"}
+	}
+
+	var lines []string
+	for i, alert := range m.data.Alerts {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, ui.Highlight(alert.Event))
+		lines = append(lines, wrap(alert.Description, width)...)
+	}
+	return lines
+}
+
+// textView shows rows lines of text starting at the tab's offset.
+func (m *Model) textView(lines []string, rows int) []string {
+	m.offset[m.tab] = clamp(m.offset[m.tab], 0, max(len(lines)-rows, 0))
+	return lines[m.offset[m.tab]:min(m.offset[m.tab]+rows, len(lines))]
+}
+
+// listView shows rows entries around the selection, marking it.
+func (m *Model) listView(entries []string, rows int) []string {
+	if len(entries) == 0 {
+		return []string{"No data available"}
+	}
+
+	sel := clamp(m.selected[m.tab], 0, len(entries)-1)
+	m.selected[m.tab] = sel
+	off := m.offset[m.tab]
+	if sel < off {
+		off = sel
+	}
+	if sel >= off+rows {
+		off = sel - rows + 1
+	}
+	m.offset[m.tab] = off
+
+	view := make([]string, 0, rows)
+	for i := off; i < min(off+rows, len(entries)); i++ {
+		if i == sel {
+			view = append(view, "> "+entries[i])
+		} else {
+			view = append(view, "  "+entries[i])
+		}
+	}
+	return view
+}
+
+func (m *Model) hourlyView(width, rows int) []string {
+	hours := m.hours()
+	entries := make([]string, len(hours))
+	for i, hour := range hours {
+		entries[i] = fmt.Sprintf("%s | %s | %s | %s",
+			m.hourTime(hour).Format("Mon 15:04"),
+			m.temp(hour.TempC, hour.TempF),
+			percent(hour.ChanceOfRain),
+			hour.Condition.Text)
+	}
+
+	if len(hours) == 0 {
+		return []string{"No hourly data available"}
+	}
+
+	if width < minDetailWidth {
+		detail := m.hourDetail(hours[clamp(m.selected[TabHourly], 0, len(hours)-1)])
+		listRows := max(rows-len(detail)-1, 1)
+		return append(append(m.listView(entries, listRows), ""), detail...)
+	}
+
+	list := m.listView(entries, rows)
+	detail := m.hourDetail(hours[m.selected[TabHourly]])
+	listWidth := width - detailWidth - 3
+
+	lines := make([]string, max(len(list), len(detail)))
+	for i := range lines {
+		left := ""
+		if i < len(list) {
+			left = screen.Truncate(list[i], listWidth)
+		}
+		right := ""
+		if i < len(detail) {
+			right = detail[i]
+		}
+		lines[i] = left + strings.Repeat(" ", max(listWidth-screen.Width(left), 0)) + " │ " + right
+	}
+	return lines
+}
+
+func (m *Model) hourDetail(hour forecast.Hour) []string {
+	t := m.hourTime(hour)
+	return []string{
+		ui.Highlight(t.Format("Monday 2 January, 15:04")),
+		ui.GetWeatherIcon(hour.Condition.Text) + " " + hour.Condition.Text,
+		"Temperature: " + strings.TrimSpace(m.temp(hour.TempC, hour.TempF)),
+		"Chance of rain: " + strings.TrimSpace(percent(hour.ChanceOfRain)),
+	}
+}
+
+func (m *Model) dailyRows() []string {
+	days := m.data.Days
+	rows := make([]string, len(days))
+	for i, fd := range days {
+		label := fd.Date
+		if date, err := time.Parse("2006-01-02", fd.Date); err == nil {
+			label = date.Format("Mon 02")
+		}
+		rows[i] = fmt.Sprintf("%s | %s | %s | %s | %s",
+			label,
+			m.temp(fd.Summary.MaxTempC, fd.Summary.MaxTempF),
+			m.temp(fd.Summary.MinTempC, fd.Summary.MinTempF),
+			percent(fd.Summary.ChanceOfRain),
+			fd.Summary.Condition.Text)
+	}
+	return rows
+}
+
+func (m *Model) switcherView(rows int) []string {
+	lines := []string{"Switch location (enter to select, esc to cancel):", ""}
+	for i, loc := range m.locations {
+		prefix := "  "
+		if i == m.switchSel {
+			prefix = "> "
+		}
+		lines = append(lines, prefix+loc.Label)
+	}
+	if len(lines) > rows {
+		start := clamp(m.switchSel+2-rows+1, 0, len(lines)-rows)
+		lines = lines[start : start+rows]
+	}
+	return lines
+}
+
+func (m *Model) temp(c, f float32) string {
+	return ui.ColorizeTempIn(c, m.units.TempValue(c, f), m.units.TempSymbol())
+}
+
+// percent formats an optional chance as a four-column percentage, showing
+// "-" when it was not reported.
+func percent(v *float32) string {
+	if v == nil {
+		return "   -"
+	}
+	return fmt.Sprintf("%3.0f%%", *v)
+}
+
+// wrap breaks text into lines of at most width columns at spaces.
+func wrap(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.TrimSpace(text), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			switch {
+			case line == "":
+				line = word
+			case screen.Width(line)+1+screen.Width(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func clamp(n, lo, hi int) int {
+	return max(lo, min(n, hi))
+}
@@ -0,0 +1,106 @@
+// Package tui implements the full-screen "weather-cli tui" interface. The
+// Model holds the state and renders it; Run connects it to a terminal.
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/screen"
+	"github.com/jtotty/weather-cli/internal/units"
+)
+
+// Options configures Run.
+type Options struct {
+	// Locations feed the location switcher; the first is shown first.
+	Locations []Location
+	// Load returns the forecast for a location query.
+	Load  func(ctx context.Context, query string) (*forecast.Response, error)
+	Units units.Units
+
+	// In should be a terminal in raw mode, so keys arrive unbuffered.
+	In  io.Reader
+	Out io.Writer
+	// Size reports the terminal size.
+	Size func() (width, height int, err error)
+	// Resize signals terminal resizes; nil uses screen.NotifyResize.
+	Resize <-chan struct{}
+	// Now defaults to time.Now.
+	Now func() time.Time
+}
+
+// Run shows the interface on the alternate screen until the user quits or
+// ctx is canceled, then restores the screen. An error loading the first
+// location is returned before the screen is touched.
+func Run(ctx context.Context, opts Options) error {
+	if len(opts.Locations) == 0 {
+		return errors.New("no location to show")
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	if opts.Resize == nil {
+		opts.Resize = screen.NotifyResize(ctx)
+	}
+
+	m := NewModel(opts.Locations, opts.Units, opts.Now)
+	data, err := opts.Load(ctx, m.Query())
+	if err != nil {
+		return err
+	}
+	m.SetData(data)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	keys := readKeys(ctx, opts.In)
+
+	fmt.Fprint(opts.Out, screen.EnterAlt+screen.HideCursor)
+	defer fmt.Fprint(opts.Out, screen.ShowCursor+screen.ExitAlt)
+
+	draw := func() {
+		width, height, err := opts.Size()
+		if err != nil || width <= 0 || height <= 0 {
+			width, height = 80, 24
+		}
+		fmt.Fprint(opts.Out, screen.Frame(m.View(width, height)))
+	}
+	draw()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-opts.Resize:
+			draw()
+		case events, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, ev := range events {
+				switch m.Update(ev) {
+				case ActionQuit:
+					return nil
+				case ActionLoad:
+					m.SetStatus("Loading " + opts.Locations[m.location].Label + "...")
+					draw()
+					load(ctx, m, opts.Load)
+				}
+			}
+			draw()
+		}
+	}
+}
+
+// load fetches the model's location, keeping the old forecast on error.
+func load(ctx context.Context, m *Model, fetch func(context.Context, string) (*forecast.Response, error)) {
+	data, err := fetch(ctx, m.Query())
+	if err != nil {
+		m.SetError(err)
+		return
+	}
+	m.SetData(data)
+}
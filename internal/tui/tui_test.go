@@ -0,0 +1,248 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/screen"
+	"github.com/jtotty/weather-cli/internal/units"
+)
+
+// start is midnight local time on the first forecast day.
+var start = time.Date(2025, 12, 1, 0, 0, 0, 0, time.Local)
+
+func fixture(name string) *forecast.Response {
+	data := &forecast.Response{
+		Location: forecast.Location{Name: name, Country: "UK"},
+		Current:  forecast.Current{TempC: 12, Condition: forecast.Condition{Text: "Cloudy"}},
+		Alerts: []forecast.Alert{
+			{Event: "Wind warning", Description: "Strong winds expected along the coast through the evening."},
+		},
+	}
+	for d := 0; d < 2; d++ {
+		day := forecast.Day{
+			Date:    start.AddDate(0, 0, d).Format("2006-01-02"),
+			Summary: forecast.Summary{MaxTempC: float32(14 + d), MinTempC: 6, ChanceOfRain: forecast.Ptr[float32](40)},
+		}
+		for h := 0; h < 24; h++ {
+			day.Hours = append(day.Hours, forecast.Hour{
+				TimeUnix:     start.Add(time.Duration(d*24+h) * time.Hour).Unix(),
+				TempC:        float32(h),
+				ChanceOfRain: forecast.Ptr(float32(h * 4 % 100)),
+				Condition:    forecast.Condition{Text: "Cloudy"},
+			})
+		}
+		data.Days = append(data.Days, day)
+	}
+	return data
+}
+
+func newModel(locations ...Location) *Model {
+	if len(locations) == 0 {
+		locations = []Location{{Label: "London", Query: "London"}}
+	}
+	now := func() time.Time { return start.Add(10*time.Hour + 30*time.Minute) }
+	m := NewModel(locations, units.Default(), now)
+	m.SetData(fixture(locations[0].Label))
+	return m
+}
+
+// draw renders m onto a virtual terminal the way Run does.
+func draw(m *Model, v *screen.Virtual) string {
+	width, height, _ := v.Size()
+	fmt.Fprint(v, screen.Frame(m.View(width, height)))
+	return v.String()
+}
+
+func press(m *Model, keys string) {
+	for _, ev := range ParseKeys([]byte(keys)) {
+		m.Update(ev)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := ParseKeys([]byte("\x1b[A\x1b[B\x1bOC\x1b[6~\x1b[Z\x1b[99;5uq\r\t\x1b\x03"))
+	want := []Event{
+		{Key: KeyUp}, {Key: KeyDown}, {Key: KeyRight}, {Key: KeyPageDown}, {Key: KeyBackTab},
+		{Key: KeyRune, Rune: 'q'}, {Key: KeyEnter}, {Key: KeyTab}, {Key: KeyEscape}, {Key: KeyCtrlC},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("ParseKeys() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestModel_Tabs(t *testing.T) {
+	m := newModel()
+	v := screen.NewVirtual(80, 20)
+
+	out := draw(m, v)
+	if !strings.Contains(out, "London, UK") || !strings.Contains(out, "[1 Current]") {
+		t.Errorf("initial screen = %q, want title and current tab", out)
+	}
+	if !strings.Contains(out, "Cloudy") {
+		t.Errorf("current tab = %q, want current conditions", out)
+	}
+
+	tests := []struct {
+		keys string
+		tab  Tab
+		want string
+	}{
+		{"\x1b[C", TabHourly, "[2 Hourly]"},
+		{"\t", TabDaily, "Mon 01"},
+		{"4", TabAlerts, "Wind warning"},
+		{"\x1b[C", TabCurrent, "[1 Current]"},
+		{"\x1b[D", TabAlerts, "Strong winds"},
+	}
+	for _, tt := range tests {
+		press(m, tt.keys)
+		if m.Tab() != tt.tab {
+			t.Errorf("after %q tab = %d, want %d", tt.keys, m.Tab(), tt.tab)
+		}
+		if out := draw(m, v); !strings.Contains(out, tt.want) {
+			t.Errorf("after %q screen = %q, want %q", tt.keys, out, tt.want)
+		}
+	}
+}
+
+func TestModel_HourlyScrollsAcrossDays(t *testing.T) {
+	m := newModel()
+	v := screen.NewVirtual(100, 12)
+	press(m, "2")
+
+	out := draw(m, v)
+	if !strings.Contains(out, "> Mon 10:00") {
+		t.Fatalf("hourly tab = %q, want the current hour selected", out)
+	}
+	if !strings.Contains(out, "Monday 1 December, 10:00") {
+		t.Errorf("hourly tab = %q, want detail pane for the selected hour", out)
+	}
+
+	// Scroll past midnight into the second day.
+	press(m, strings.Repeat("\x1b[B", 16))
+	out = draw(m, v)
+	if !strings.Contains(out, "> Tue 02:00") || !strings.Contains(out, "Tuesday 2 December, 02:00") {
+		t.Errorf("after scrolling screen = %q, want Tue 02:00 selected", out)
+	}
+	if strings.Contains(out, "Mon 10:00") {
+		t.Errorf("after scrolling screen = %q, list should have scrolled", out)
+	}
+
+	press(m, "G")
+	if out := draw(m, v); !strings.Contains(out, "> Tue 23:00") {
+		t.Errorf("after End screen = %q, want last hour selected", out)
+	}
+	press(m, "\x1b[H")
+	if out := draw(m, v); !strings.Contains(out, "> Mon 00:00") {
+		t.Errorf("after Home screen = %q, want first hour selected", out)
+	}
+}
+
+func TestModel_NarrowHourlyStacksDetail(t *testing.T) {
+	m := newModel()
+	v := screen.NewVirtual(50, 16)
+	press(m, "2")
+
+	lines := strings.Split(draw(m, v), "\n")
+	for _, line := range lines {
+		if strings.Contains(line, "│") {
+			t.Fatalf("narrow screen = %q, detail pane should be below the list", lines)
+		}
+	}
+	if !strings.Contains(strings.Join(lines, "\n"), "Chance of rain: 40%") {
+		t.Errorf("narrow screen = %q, want detail for 10:00", lines)
+	}
+}
+
+func TestModel_LocationSwitcher(t *testing.T) {
+	m := newModel(
+		Location{Label: "London", Query: "London"},
+		Location{Label: "@home (Home)", Query: "51.5,-0.1"},
+	)
+	v := screen.NewVirtual(80, 12)
+
+	press(m, "l")
+	if out := draw(m, v); !strings.Contains(out, "> London") || !strings.Contains(out, "  @home (Home)") {
+		t.Fatalf("switcher = %q, want locations listed", out)
+	}
+
+	press(m, "j")
+	if action := m.Update(Event{Key: KeyEnter}); action != ActionLoad {
+		t.Fatalf("Enter in switcher = %v, want ActionLoad", action)
+	}
+	if m.Query() != "51.5,-0.1" {
+		t.Errorf("Query() = %q, want saved coordinates", m.Query())
+	}
+
+	m.SetError(errors.New("no matching location found"))
+	if out := draw(m, v); !strings.Contains(out, "Error: no matching location found") || !strings.Contains(out, "London, UK") {
+		t.Errorf("after failed load screen = %q, want error and previous forecast", out)
+	}
+}
+
+func TestModel_Quit(t *testing.T) {
+	m := newModel()
+	for _, ev := range []Event{{Key: KeyRune, Rune: 'q'}, {Key: KeyCtrlC}} {
+		if action := m.Update(ev); action != ActionQuit {
+			t.Errorf("Update(%+v) = %v, want ActionQuit", ev, action)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	v := screen.NewVirtual(80, 20)
+	fmt.Fprint(v, "$ weather-cli tui")
+
+	var loaded []string
+	err := Run(context.Background(), Options{
+		Locations: []Location{{Label: "London", Query: "London"}, {Label: "Paris", Query: "Paris"}},
+		Load: func(_ context.Context, query string) (*forecast.Response, error) {
+			loaded = append(loaded, query)
+			return fixture(query), nil
+		},
+		Units:  units.Default(),
+		In:     strings.NewReader("2\x1b[Bl\x1b[B\rq"),
+		Out:    v,
+		Size:   v.Size,
+		Resize: make(chan struct{}),
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if strings.Join(loaded, ",") != "London,Paris" {
+		t.Errorf("loaded %v, want London then Paris", loaded)
+	}
+	if v.AltScreen || !v.CursorVisible || v.String() != "$ weather-cli tui" {
+		t.Errorf("terminal not restored: alt=%v cursor=%v screen=%q", v.AltScreen, v.CursorVisible, v.String())
+	}
+}
+
+func TestRun_FirstLoadError(t *testing.T) {
+	v := screen.NewVirtual(80, 20)
+	err := Run(context.Background(), Options{
+		Locations: []Location{{Label: "Atlantis", Query: "Atlantis"}},
+		Load: func(context.Context, string) (*forecast.Response, error) {
+			return nil, errors.New("no matching location found")
+		},
+		In:     strings.NewReader(""),
+		Out:    v,
+		Size:   v.Size,
+		Resize: make(chan struct{}),
+	})
+
+	if err == nil || v.AltScreen || v.String() != "" {
+		t.Errorf("Run() error = %v, screen = %q; want error before drawing", err, v.String())
+	}
+}
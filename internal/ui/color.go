@@ -1,6 +1,10 @@
 package ui
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+	"strings"
+)
 
 // ANSI color codes (24-bit true color foreground)
 // Format: \033[38;2;R;G;Bm
@@ -53,6 +57,58 @@ var tempColors = []struct {
 	{150, "\033[38;2;61;2;22m"},
 }
 
+// ColorMode controls when ANSI colors are written.
+type ColorMode string
+
+const (
+	ColorAuto   ColorMode = "auto"
+	ColorAlways ColorMode = "always"
+	ColorNever  ColorMode = "never"
+)
+
+var colorEnabled = true
+
+// ParseColorMode parses "auto", "always" or "never". An empty string is auto.
+func ParseColorMode(s string) (ColorMode, error) {
+	switch mode := ColorMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case "":
+		return ColorAuto, nil
+	case ColorAuto, ColorAlways, ColorNever:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown color mode %q (available: auto, always, never)", s)
+	}
+}
+
+// Enabled reports whether the mode colors output. Auto colors terminals
+// unless NO_COLOR is set.
+func (m ColorMode) Enabled(isTerminal bool) bool {
+	switch m {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	default:
+		return isTerminal && os.Getenv("NO_COLOR") == ""
+	}
+}
+
+// SetColorEnabled turns ANSI colors on or off for all colorizing helpers.
+func SetColorEnabled(enabled bool) {
+	colorEnabled = enabled
+}
+
+// Highlight shows s in reverse video, to draw attention to a changed value.
+// Colors inside s are kept, and reverse video is turned back on after each
+// reset they end with.
+func Highlight(s string) string {
+	if !colorEnabled {
+		return s
+	}
+	s = strings.ReplaceAll(s, ColorReset, ColorReset+"\033[7m")
+	return "\033[7m" + s + "\033[27m"
+}
+
 // celsiusToFahrenheit converts Celsius to Fahrenheit
 func celsiusToFahrenheit(c float32) float32 {
 	return (c * 9 / 5) + 32
@@ -60,8 +116,18 @@ func celsiusToFahrenheit(c float32) float32 {
 
 // ColorizeTemp returns a temperature string with ANSI color coding.
 func ColorizeTemp(temp float32) string {
-	color := getTempColor(temp)
-	return fmt.Sprintf("%s%3.0f°C%s", color, temp, ColorReset)
+	return ColorizeTempIn(temp, temp, "°C")
+}
+
+// ColorizeTempIn colors by the Celsius temperature but prints value, the same
+// temperature in another unit, followed by symbol.
+func ColorizeTempIn(celsius, value float32, symbol string) string {
+	if !colorEnabled {
+		return fmt.Sprintf("%3.0f%s", value, symbol)
+	}
+
+	color := getTempColor(celsius)
+	return fmt.Sprintf("%s%3.0f%s%s", color, value, symbol, ColorReset)
 }
 
 // getTempColor returns the appropriate ANSI color code for a temperature in Celsius.
@@ -74,6 +74,21 @@ func TestColorizeTemp_Format(t *testing.T) {
 	}
 }
 
+func TestColorizeTempIn(t *testing.T) {
+	result := ColorizeTempIn(30, 86, "°F")
+
+	if !strings.HasPrefix(result, getTempColor(30)) {
+		t.Errorf("ColorizeTempIn() = %q, want color for 30°C", result)
+	}
+
+	stripped := strings.TrimPrefix(result, getTempColor(30))
+	stripped = strings.TrimSuffix(stripped, ColorReset)
+
+	if stripped != " 86°F" {
+		t.Errorf("ColorizeTempIn() formatted as %q, want %q", stripped, " 86°F")
+	}
+}
+
 func TestGetTempColor_GradientOrder(t *testing.T) {
 	// Verify that colder temps get different colors than warmer temps
 	coldColor := getTempColor(-20)
@@ -127,3 +142,59 @@ func TestCelsiusToFahrenheit(t *testing.T) {
 		})
 	}
 }
+
+func TestParseColorMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ColorMode
+		wantErr bool
+	}{
+		{"", ColorAuto, false},
+		{"Always", ColorAlways, false},
+		{"never", ColorNever, false},
+		{"sometimes", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseColorMode(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseColorMode(%q) = %q, %v; want %q, wantErr %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestColorMode_Enabled(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	if !ColorAuto.Enabled(true) || ColorAuto.Enabled(false) {
+		t.Error("auto should color terminals only")
+	}
+	if !ColorAlways.Enabled(false) || ColorNever.Enabled(true) {
+		t.Error("always/never should ignore the terminal")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if ColorAuto.Enabled(true) {
+		t.Error("auto should respect NO_COLOR")
+	}
+}
+
+func TestSetColorEnabled(t *testing.T) {
+	SetColorEnabled(false)
+	defer SetColorEnabled(true)
+
+	if got := ColorizeTemp(20); got != " 20°C" {
+		t.Errorf("ColorizeTemp() with colors off = %q, want %q", got, " 20°C")
+	}
+	if got := Highlight("12"); got != "12" {
+		t.Errorf("Highlight() with colors off = %q, want %q", got, "12")
+	}
+}
+
+func TestHighlight_KeepsColors(t *testing.T) {
+	temp := ColorizeTemp(20)
+	got := Highlight(temp + " Sunny")
+	want := "\033[7m" + strings.TrimSuffix(temp, ColorReset) + ColorReset + "\033[7m Sunny\033[27m"
+	if got != want {
+		t.Errorf("Highlight() = %q, want %q", got, want)
+	}
+}
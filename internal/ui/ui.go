@@ -20,6 +20,19 @@ var icons = map[string]emoji.Emoji{
 	"humidity": emoji.Droplet,
 	"sunrise":  emoji.Sunrise,
 	"sunset":   emoji.Sunset,
+	"moon":     emoji.CrescentMoon,
+}
+
+var moonIcons = map[string]emoji.Emoji{
+	"new_moon":        emoji.NewMoon,
+	"waxing_crescent": emoji.WaxingCrescentMoon,
+	"first_quarter":   emoji.FirstQuarterMoon,
+	"waxing_gibbous":  emoji.WaxingGibbousMoon,
+	"full_moon":       emoji.FullMoon,
+	"waning_gibbous":  emoji.WaningGibbousMoon,
+	"last_quarter":    emoji.LastQuarterMoon,
+	"third_quarter":   emoji.LastQuarterMoon,
+	"waning_crescent": emoji.WaningCrescentMoon,
 }
 
 var weatherIcons = map[string]emoji.Emoji{
@@ -105,6 +118,19 @@ func GetWeatherIcon(name string) string {
 	return "Err: Icon not loaded"
 }
 
+// GetMoonIcon returns the icon for a moon phase such as "Waxing Gibbous",
+// or "" for an unknown phase.
+func GetMoonIcon(phase string) string {
+	key := strings.TrimSpace(strings.ToLower(phase))
+	key = strings.ReplaceAll(key, " ", "_")
+
+	if icon, ok := moonIcons[key]; ok {
+		return icon.String()
+	}
+
+	return ""
+}
+
 func GetAqiIcon(num float32) string {
 	aqi := int(num)
 	if aqi < 0 {
@@ -99,3 +99,23 @@ func TestCreateBorder_Length(t *testing.T) {
 		})
 	}
 }
+
+func TestGetMoonIcon(t *testing.T) {
+	tests := []struct {
+		phase string
+		want  string
+	}{
+		{"New Moon", "\U0001f311"},
+		{"Waxing Gibbous", "\U0001f314"},
+		{"full moon", "\U0001f315"},
+		{"Third Quarter", "\U0001f317"},
+		{"Last Quarter", "\U0001f317"},
+		{"Blue Moon", ""},
+	}
+
+	for _, tt := range tests {
+		if got := GetMoonIcon(tt.phase); got != tt.want {
+			t.Errorf("GetMoonIcon(%q) = %q, want %q", tt.phase, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package units
+
+// Conversions for providers that report only one system.
+
+func CelsiusToFahrenheit(c float32) float32 {
+	return c*9/5 + 32
+}
+
+func FahrenheitToCelsius(f float32) float32 {
+	return (f - 32) * 5 / 9
+}
+
+func MphToKph(mph float32) float32 {
+	return mph * 1.609344
+}
+
+func KphToMph(kph float32) float32 {
+	return kph / 1.609344
+}
+
+func MetersPerSecondToMph(ms float32) float32 {
+	return ms * 2.236936
+}
+
+func MillimetersToInches(mm float32) float32 {
+	return mm / 25.4
+}
+
+func MillibarsToInches(mb float32) float32 {
+	return mb * 0.02953
+}
+
+func KilometersToMiles(km float32) float32 {
+	return km * 0.621371
+}
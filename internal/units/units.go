@@ -0,0 +1,217 @@
+// Package units converts weather quantities between measurement systems.
+package units
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type System string
+
+const (
+	Metric   System = "metric"
+	Imperial System = "imperial"
+	UK       System = "uk"
+	SI       System = "si"
+)
+
+// DefaultSystem matches the original display: Celsius with wind in mph.
+const DefaultSystem = UK
+
+type (
+	Temp     string
+	Speed    string
+	Pressure string
+	Precip   string
+	Distance string
+)
+
+const (
+	Celsius    Temp = "c"
+	Fahrenheit Temp = "f"
+	Kelvin     Temp = "k"
+
+	Mph   Speed = "mph"
+	Kph   Speed = "kph"
+	Ms    Speed = "ms"
+	Knots Speed = "kn"
+
+	Millibar    Pressure = "mb"
+	Hectopascal Pressure = "hpa"
+	InchesHg    Pressure = "inhg"
+
+	Millimeters Precip = "mm"
+	Inches      Precip = "in"
+
+	Kilometers Distance = "km"
+	Miles      Distance = "mi"
+)
+
+// Units selects the unit used to present each quantity.
+type Units struct {
+	Temp     Temp
+	Speed    Speed
+	Pressure Pressure
+	Precip   Precip
+	Distance Distance
+}
+
+var systems = map[System]Units{
+	Metric:   {Celsius, Kph, Millibar, Millimeters, Kilometers},
+	Imperial: {Fahrenheit, Mph, InchesHg, Inches, Miles},
+	UK:       {Celsius, Mph, Millibar, Millimeters, Miles},
+	SI:       {Kelvin, Ms, Hectopascal, Millimeters, Kilometers},
+}
+
+// Quantities lists the names accepted as per-quantity overrides.
+var Quantities = []string{"temp", "wind", "pressure", "precip", "distance"}
+
+var allowed = map[string][]string{
+	"temp":     {string(Celsius), string(Fahrenheit), string(Kelvin)},
+	"wind":     {string(Mph), string(Kph), string(Ms), string(Knots)},
+	"pressure": {string(Millibar), string(Hectopascal), string(InchesHg)},
+	"precip":   {string(Millimeters), string(Inches)},
+	"distance": {string(Kilometers), string(Miles)},
+}
+
+// Default returns the units of DefaultSystem.
+func Default() Units {
+	return systems[DefaultSystem]
+}
+
+// SystemNames returns the supported system names, sorted.
+func SystemNames() []string {
+	names := make([]string, 0, len(systems))
+	for s := range systems {
+		names = append(names, string(s))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Parse resolves a system name and per-quantity overrides (for example
+// {"wind": "kph"}) into Units. An empty system selects DefaultSystem.
+func Parse(system string, overrides map[string]string) (Units, error) {
+	system = strings.ToLower(strings.TrimSpace(system))
+	if system == "" {
+		system = string(DefaultSystem)
+	}
+
+	u, ok := systems[System(system)]
+	if !ok {
+		return Units{}, fmt.Errorf("unknown unit system %q (available: %s)", system, strings.Join(SystemNames(), ", "))
+	}
+
+	for quantity, unit := range overrides {
+		if err := u.Set(quantity, unit); err != nil {
+			return Units{}, err
+		}
+	}
+
+	return u, nil
+}
+
+// Set overrides the unit for a single quantity.
+func (u *Units) Set(quantity, unit string) error {
+	quantity = strings.ToLower(strings.TrimSpace(quantity))
+	unit = strings.ToLower(strings.TrimSpace(unit))
+
+	options, ok := allowed[quantity]
+	if !ok {
+		return fmt.Errorf("unknown quantity %q (available: %s)", quantity, strings.Join(Quantities, ", "))
+	}
+
+	valid := false
+	for _, o := range options {
+		if o == unit {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("unknown %s unit %q (available: %s)", quantity, unit, strings.Join(options, ", "))
+	}
+
+	switch quantity {
+	case "temp":
+		u.Temp = Temp(unit)
+	case "wind":
+		u.Speed = Speed(unit)
+	case "pressure":
+		u.Pressure = Pressure(unit)
+	case "precip":
+		u.Precip = Precip(unit)
+	case "distance":
+		u.Distance = Distance(unit)
+	}
+
+	return nil
+}
+
+// The conversions below take both variants weatherapi.com reports so the
+// upstream value is used as-is whenever the requested unit matches one.
+
+// TempValue returns the temperature in the selected unit.
+func (u Units) TempValue(c, f float32) float32 {
+	switch u.Temp {
+	case Fahrenheit:
+		return f
+	case Kelvin:
+		return c + 273.15
+	default:
+		return c
+	}
+}
+
+// SpeedValue returns the wind speed in the selected unit.
+func (u Units) SpeedValue(mph, kph float32) float32 {
+	switch u.Speed {
+	case Kph:
+		return kph
+	case Ms:
+		return kph / 3.6
+	case Knots:
+		return mph * 0.868976
+	default:
+		return mph
+	}
+}
+
+// PressureValue returns the pressure in the selected unit.
+func (u Units) PressureValue(mb, in float32) float32 {
+	if u.Pressure == InchesHg {
+		return in
+	}
+	return mb
+}
+
+// PrecipValue returns the precipitation in the selected unit.
+func (u Units) PrecipValue(mm, in float32) float32 {
+	if u.Precip == Inches {
+		return in
+	}
+	return mm
+}
+
+// DistanceValue returns the distance in the selected unit.
+func (u Units) DistanceValue(km, mi float32) float32 {
+	if u.Distance == Miles {
+		return mi
+	}
+	return km
+}
+
+var (
+	tempSymbols     = map[Temp]string{Celsius: "°C", Fahrenheit: "°F", Kelvin: "K"}
+	speedSymbols    = map[Speed]string{Mph: "mph", Kph: "km/h", Ms: "m/s", Knots: "kn"}
+	pressureSymbols = map[Pressure]string{Millibar: "mb", Hectopascal: "hPa", InchesHg: "inHg"}
+	precipSymbols   = map[Precip]string{Millimeters: "mm", Inches: "in"}
+	distanceSymbols = map[Distance]string{Kilometers: "km", Miles: "mi"}
+)
+
+func (u Units) TempSymbol() string     { return tempSymbols[u.Temp] }
+func (u Units) SpeedSymbol() string    { return speedSymbols[u.Speed] }
+func (u Units) PressureSymbol() string { return pressureSymbols[u.Pressure] }
+func (u Units) PrecipSymbol() string   { return precipSymbols[u.Precip] }
+func (u Units) DistanceSymbol() string { return distanceSymbols[u.Distance] }
@@ -0,0 +1,132 @@
+package units
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParse_Systems(t *testing.T) {
+	tests := []struct {
+		system string
+		want   Units
+	}{
+		{"", Units{Celsius, Mph, Millibar, Millimeters, Miles}},
+		{"metric", Units{Celsius, Kph, Millibar, Millimeters, Kilometers}},
+		{"Imperial", Units{Fahrenheit, Mph, InchesHg, Inches, Miles}},
+		{"uk", Units{Celsius, Mph, Millibar, Millimeters, Miles}},
+		{"si", Units{Kelvin, Ms, Hectopascal, Millimeters, Kilometers}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.system, func(t *testing.T) {
+			got, err := Parse(tt.system, nil)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.system, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Overrides(t *testing.T) {
+	got, err := Parse("imperial", map[string]string{"wind": "KPH", "temp": "c"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if got.Speed != Kph || got.Temp != Celsius || got.Precip != Inches {
+		t.Errorf("Parse() = %+v, want imperial with kph and celsius", got)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		system    string
+		overrides map[string]string
+		wantErr   string
+	}{
+		{"unknown system", "nautical", nil, "unknown unit system"},
+		{"unknown quantity", "metric", map[string]string{"humidity": "pct"}, "unknown quantity"},
+		{"unknown unit", "metric", map[string]string{"wind": "furlongs"}, "unknown wind unit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.system, tt.overrides)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValues(t *testing.T) {
+	near := func(got, want float32) bool { return math.Abs(float64(got-want)) < 0.01 }
+
+	metric, _ := Parse("metric", nil)
+	imperial, _ := Parse("imperial", nil)
+	si, _ := Parse("si", nil)
+	knots := Default()
+	_ = knots.Set("wind", "kn")
+
+	tests := []struct {
+		name string
+		got  float32
+		want float32
+	}{
+		{"metric temp", metric.TempValue(20, 68), 20},
+		{"imperial temp uses upstream °F", imperial.TempValue(20, 68.2), 68.2},
+		{"si temp", si.TempValue(20, 68), 293.15},
+		{"metric wind", metric.SpeedValue(10, 16.1), 16.1},
+		{"imperial wind", imperial.SpeedValue(10, 16.1), 10},
+		{"si wind", si.SpeedValue(10, 36), 10},
+		{"knots", knots.SpeedValue(10, 16.1), 8.69},
+		{"imperial pressure", imperial.PressureValue(1013, 29.91), 29.91},
+		{"metric precip", metric.PrecipValue(2.5, 0.1), 2.5},
+		{"imperial precip", imperial.PrecipValue(2.5, 0.1), 0.1},
+		{"metric distance", metric.DistanceValue(10, 6), 10},
+		{"imperial distance", imperial.DistanceValue(10, 6), 6},
+	}
+
+	for _, tt := range tests {
+		if !near(tt.got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestSymbols(t *testing.T) {
+	imperial, _ := Parse("imperial", nil)
+	si, _ := Parse("si", nil)
+
+	if got := imperial.TempSymbol() + imperial.SpeedSymbol() + imperial.PressureSymbol(); got != "°FmphinHg" {
+		t.Errorf("imperial symbols = %q", got)
+	}
+	if got := si.TempSymbol(); got != "K" {
+		t.Errorf("si TempSymbol() = %q, want K", got)
+	}
+	if got := si.SpeedSymbol(); got != "m/s" {
+		t.Errorf("si SpeedSymbol() = %q, want m/s", got)
+	}
+}
+
+func TestConversions(t *testing.T) {
+	near := func(got, want float32) bool { return math.Abs(float64(got-want)) < 0.01 }
+
+	if !near(CelsiusToFahrenheit(100), 212) || !near(FahrenheitToCelsius(32), 0) {
+		t.Error("temperature conversions are wrong")
+	}
+	if !near(MphToKph(10), 16.09) || !near(KphToMph(16.09), 10) {
+		t.Error("speed conversions are wrong")
+	}
+	if !near(MetersPerSecondToMph(10), 22.37) {
+		t.Error("m/s conversion is wrong")
+	}
+	if !near(MillimetersToInches(25.4), 1) || !near(MillibarsToInches(1013), 29.91) || !near(KilometersToMiles(10), 6.21) {
+		t.Error("precip, pressure or distance conversions are wrong")
+	}
+}
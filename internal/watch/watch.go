@@ -0,0 +1,195 @@
+// Package watch implements the live-refreshing "weather-cli watch" dashboard.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/screen"
+	"github.com/jtotty/weather-cli/internal/ui"
+)
+
+// DefaultInterval is how often the dashboard refreshes. Data is re-fetched
+// only once the cached forecast has expired.
+const DefaultInterval = time.Minute
+
+// Fallback size when the terminal cannot be measured.
+const (
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// Options configures a dashboard.
+type Options struct {
+	// Interval between refreshes; zero means DefaultInterval.
+	Interval time.Duration
+	// Fetch returns the latest forecast, typically from the service cache.
+	Fetch func(ctx context.Context) (*forecast.Response, error)
+	// Render formats a forecast for display.
+	Render func(data *forecast.Response) (string, error)
+	// Size reports the terminal size.
+	Size func() (width, height int, err error)
+	// Resize signals terminal resizes; nil uses screen.NotifyResize.
+	Resize <-chan struct{}
+	Out    io.Writer
+	// Now defaults to time.Now.
+	Now func() time.Time
+}
+
+// Run draws the dashboard on the alternate screen and refreshes it until ctx
+// is canceled, then restores the terminal. An error fetching the first
+// forecast is returned before the screen is touched; later errors are shown
+// in the status line while the last good forecast stays on screen.
+func Run(ctx context.Context, opts Options) error {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	if opts.Resize == nil {
+		opts.Resize = screen.NotifyResize(ctx)
+	}
+
+	d := &dashboard{opts: opts}
+	data, err := opts.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+	if err := d.update(data); err != nil {
+		return err
+	}
+
+	fmt.Fprint(opts.Out, screen.EnterAlt+screen.HideCursor)
+	defer fmt.Fprint(opts.Out, screen.ShowCursor+screen.ExitAlt)
+	d.draw()
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-opts.Resize:
+			d.draw()
+		case <-ticker.C:
+			data, err := opts.Fetch(ctx)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err == nil {
+				err = d.update(data)
+			}
+			d.err = err
+			d.draw()
+		}
+	}
+}
+
+type dashboard struct {
+	opts Options
+
+	// plain is the last rendering without highlights, to diff against.
+	plain   string
+	text    string
+	updated time.Time
+	// err is the last refresh error, shown until a refresh succeeds.
+	err error
+}
+
+// update renders a newly fetched forecast, highlighting what changed since
+// the previous one.
+func (d *dashboard) update(data *forecast.Response) error {
+	text, err := d.opts.Render(data)
+	if err != nil {
+		return err
+	}
+	text = strings.TrimPrefix(text, "\n")
+
+	d.text = text
+	if d.plain != "" {
+		d.text = highlightChanges(d.plain, text)
+	}
+	d.plain = text
+	d.updated = d.opts.Now()
+	return nil
+}
+
+// draw repaints the screen in place, fitting the forecast to the terminal
+// and keeping the last line for the status.
+func (d *dashboard) draw() {
+	width, height, err := d.opts.Size()
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = defaultWidth, defaultHeight
+	}
+
+	lines := screen.Fit(d.text, width, height-2)
+	lines = append(lines, "", screen.Truncate(d.status(), width))
+
+	fmt.Fprint(d.opts.Out, screen.Frame(lines))
+}
+
+func (d *dashboard) status() string {
+	updated := d.updated.Format("15:04:05")
+	if d.err != nil {
+		return fmt.Sprintf("Refresh failed at %s: %v (showing data from %s)",
+			d.opts.Now().Format("15:04:05"), d.err, updated)
+	}
+	return fmt.Sprintf("Updated %s · refreshing every %s · Ctrl+C to quit", updated, d.opts.Interval)
+}
+
+// highlightChanges marks the values in next that differ from prev. Lines are
+// matched by their label ("Wind", "14:00", "Tue 02") rather than position,
+// so rows that scroll away as the day passes do not count as changes.
+func highlightChanges(prev, next string) string {
+	previous := make(map[string]string)
+	for _, line := range strings.Split(prev, "\n") {
+		previous[lineKey(line)] = line
+	}
+
+	lines := strings.Split(next, "\n")
+	for i, line := range lines {
+		if before, ok := previous[lineKey(line)]; ok && before != line {
+			lines[i] = highlightLine(before, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lineKey returns the label before the first ": " or " | " of a line.
+func lineKey(line string) string {
+	plain := screen.Strip(line)
+	end := len(plain)
+	for _, sep := range []string{": ", " | "} {
+		if i := strings.Index(plain, sep); i >= 0 && i < end {
+			end = i
+		}
+	}
+	return plain[:end]
+}
+
+// highlightLine highlights the words of line that differ from before. When
+// the number of words changed, everything after the label is highlighted.
+// Either way the line keeps its colors.
+func highlightLine(before, line string) string {
+	oldWords := strings.Split(before, " ")
+	words := strings.Split(line, " ")
+
+	if len(oldWords) != len(words) {
+		key, rest := screen.Cut(line, len(lineKey(line)))
+		return key + ui.Highlight(rest)
+	}
+
+	for i, word := range words {
+		plain := screen.Strip(word)
+		if plain != "" && plain != screen.Strip(oldWords[i]) {
+			words[i] = ui.Highlight(word)
+		}
+	}
+	return strings.Join(words, " ")
+}
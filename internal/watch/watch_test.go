@@ -0,0 +1,184 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/screen"
+)
+
+func render(data *forecast.Response) (string, error) {
+	return fmt.Sprintf("\nWeather for %s\nTemp: %.0f°C", data.Location.Name, data.Current.TempC), nil
+}
+
+func fixedSize() (int, int, error) { return 80, 10, nil }
+
+func TestRun_FirstFetchError(t *testing.T) {
+	var out bytes.Buffer
+	err := Run(context.Background(), Options{
+		Fetch: func(context.Context) (*forecast.Response, error) {
+			return nil, errors.New("no matching location found")
+		},
+		Render: render,
+		Size:   fixedSize,
+		Resize: make(chan struct{}),
+		Out:    &out,
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "no matching location") {
+		t.Errorf("Run() error = %v, want fetch error", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Run() wrote %q before the first forecast", out.String())
+	}
+}
+
+func TestRun_Refreshes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	responses := []func() (*forecast.Response, error){
+		func() (*forecast.Response, error) { return weatherAt(12), nil },
+		func() (*forecast.Response, error) { return nil, errors.New("connection refused") },
+		func() (*forecast.Response, error) { return weatherAt(13), nil },
+	}
+	calls := 0
+	fetch := func(context.Context) (*forecast.Response, error) {
+		if calls == len(responses) {
+			cancel()
+			return nil, ctx.Err()
+		}
+		calls++
+		return responses[calls-1]()
+	}
+
+	var out bytes.Buffer
+	err := Run(ctx, Options{
+		Interval: time.Millisecond,
+		Fetch:    fetch,
+		Render:   render,
+		Size:     fixedSize,
+		Resize:   make(chan struct{}),
+		Out:      &out,
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	got := out.String()
+	if !strings.HasPrefix(got, screen.EnterAlt) || !strings.HasSuffix(got, screen.ShowCursor+screen.ExitAlt) {
+		t.Errorf("output does not enter and restore the alternate screen: %q", got)
+	}
+	if !strings.Contains(got, "Refresh failed") || !strings.Contains(got, "connection refused") {
+		t.Errorf("output = %q, want refresh error in status line", got)
+	}
+	if !strings.Contains(got, "\033[7m13°C\033[27m") {
+		t.Errorf("output = %q, want changed temperature highlighted", got)
+	}
+	if strings.Contains(got, "\033[7mWeather") {
+		t.Errorf("output = %q, unchanged lines should not be highlighted", got)
+	}
+}
+
+func TestRun_FitsTerminal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var out bytes.Buffer
+	resize := make(chan struct{}, 1)
+	resize <- struct{}{}
+
+	draws := 0
+	err := Run(ctx, Options{
+		Interval: time.Hour,
+		Fetch:    func(context.Context) (*forecast.Response, error) { return weatherAt(12), nil },
+		Render:   render,
+		Size: func() (int, int, error) {
+			draws++
+			if draws == 2 {
+				cancel()
+			}
+			return 10, 3, nil
+		},
+		Resize: resize,
+		Out:    &out,
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if draws != 2 {
+		t.Errorf("drew %d times, want initial draw plus one on resize", draws)
+	}
+	// A 3-line terminal holds one line of forecast plus the status.
+	if got := out.String(); !strings.Contains(got, "Weather fo") || strings.Contains(got, "Temp") {
+		t.Errorf("output = %q, want forecast cut to the terminal", got)
+	}
+}
+
+func TestHighlightChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		prev string
+		next string
+		want string
+	}{
+		{
+			name: "unchanged",
+			prev: "Wind: SW 10 mph",
+			next: "Wind: SW 10 mph",
+			want: "Wind: SW 10 mph",
+		},
+		{
+			name: "changed word",
+			prev: "Wind: SW 10 mph",
+			next: "Wind: SW 14 mph",
+			want: "Wind: SW \033[7m14\033[27m mph",
+		},
+		{
+			name: "rows matched by label",
+			prev: "13:00 | 10°C\n14:00 | 11°C",
+			next: "14:00 | 11°C\n15:00 | 12°C",
+			want: "14:00 | 11°C\n15:00 | 12°C",
+		},
+		{
+			name: "different word count",
+			prev: "Current Conditions: Sunny, 12°C",
+			next: "Current Conditions: Partly cloudy, 12°C",
+			want: "Current Conditions\033[7m: Partly cloudy, 12°C\033[27m",
+		},
+		{
+			name: "colors kept",
+			prev: "14:00 | \033[31m 20°C\033[0m | Sunny",
+			next: "14:00 | \033[31m 20°C\033[0m | Cloudy",
+			want: "14:00 | \033[31m 20°C\033[0m | \033[7mCloudy\033[27m",
+		},
+		{
+			name: "colors kept with different word count",
+			prev: "Current Conditions: Sunny, \033[31m 20°C\033[0m",
+			next: "Current Conditions: Partly cloudy, \033[31m 20°C\033[0m",
+			want: "Current Conditions\033[7m: Partly cloudy, \033[31m 20°C\033[0m\033[7m\033[27m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightChanges(tt.prev, tt.next); got != tt.want {
+				t.Errorf("highlightChanges() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func weatherAt(temp float32) *forecast.Response {
+	return &forecast.Response{
+		Location: forecast.Location{Name: "London"},
+		Current:  forecast.Current{TempC: temp},
+	}
+}
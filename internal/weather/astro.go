@@ -0,0 +1,193 @@
+package weather
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/astro"
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/ui"
+)
+
+// Astronomy returns the sun and moon times for the first forecast day:
+// every twilight boundary, day length and the moon phase.
+func (d *Display) Astronomy() string {
+	if d.data == nil || len(d.data.Days) == 0 {
+		return "Astronomy: No data available\n"
+	}
+
+	day := d.data.Days[0]
+	a := day.Astro
+
+	var rows [][2]string
+	add := func(label, value string) {
+		if value != "" {
+			rows = append(rows, [2]string{label, value})
+		}
+	}
+
+	sun, hasSun := d.sun(0)
+	if hasSun {
+		add("Astronomical dawn", d.clock(sun.Astronomical, true))
+		add("Nautical dawn", d.clock(sun.Nautical, true))
+		add("Civil dawn", d.clock(sun.Civil, true))
+	}
+	sunrise, sunset := a.Sunrise, a.Sunset
+	if hasSun && sunrise == "" && sunset == "" {
+		sunrise, sunset = d.clock(sun.Daylight, true), d.clock(sun.Daylight, false)
+	}
+	add("Sunrise", withIcon("sunrise", sunrise))
+	add("Sunset", withIcon("sunset", sunset))
+	if hasSun {
+		add("Civil dusk", d.clock(sun.Civil, false))
+		add("Nautical dusk", d.clock(sun.Nautical, false))
+		add("Astronomical dusk", d.clock(sun.Astronomical, false))
+	}
+	add("Day length", d.dayLength())
+	add("Moon phase", moonPhase(a))
+	add("Moonrise", a.Moonrise)
+	add("Moonset", a.Moonset)
+	if a.IsSunUp != nil && a.IsMoonUp != nil && d.isToday(day.Date) {
+		add("Now", fmt.Sprintf("sun %s, moon %s", upOrDown(*a.IsSunUp), upOrDown(*a.IsMoonUp)))
+	}
+
+	output := strings.Builder{}
+	output.WriteString("Astronomy")
+	if date, err := time.Parse("2006-01-02", day.Date); err == nil {
+		output.WriteString(" (" + date.Format("Mon, Jan 2") + ")")
+	}
+	output.WriteString(":")
+	for _, row := range rows {
+		fmt.Fprintf(&output, "\n%-18s %s", row[0]+":", row[1])
+	}
+	return output.String()
+}
+
+// sun computes the sun's crossings for a forecast day from the location's
+// coordinates, reporting false when they are unknown.
+func (d *Display) sun(index int) (astro.Sun, bool) {
+	loc := d.data.Location
+	days := d.data.Days
+	if (loc.Lat == 0 && loc.Lon == 0) || index >= len(days) {
+		return astro.Sun{}, false
+	}
+
+	date, err := time.Parse("2006-01-02", days[index].Date)
+	if err != nil {
+		return astro.Sun{}, false
+	}
+	return astro.SunOn(date, loc.Lat, loc.Lon), true
+}
+
+// clock formats when the sun rises above (dawn) or sets below the
+// crossing's altitude, in the location's time zone.
+func (d *Display) clock(c astro.Crossing, dawn bool) string {
+	switch {
+	case c.AlwaysAbove:
+		return "none (the sun stays above)"
+	case c.AlwaysBelow:
+		return "none (the sun stays below)"
+	}
+
+	t := c.Set
+	if dawn {
+		t = c.Rise
+	}
+
+	zone, ok := d.data.Location.Zone()
+	if !ok {
+		return t.UTC().Format("03:04 PM") + " UTC"
+	}
+	return t.In(zone).Format("03:04 PM")
+}
+
+// dayLength returns how long the sun is up on the first forecast day and
+// the change since the day before, or "" when it cannot be worked out.
+func (d *Display) dayLength() string {
+	today, ok := d.sun(0)
+	if !ok {
+		return sunriseToSunset(d.data.Days[0].Astro)
+	}
+
+	text := formatDayLength(today.Daylight)
+	date, _ := time.Parse("2006-01-02", d.data.Days[0].Date)
+	loc := d.data.Location
+	yesterday := astro.SunOn(date.AddDate(0, 0, -1), loc.Lat, loc.Lon)
+
+	if change := today.Daylight.Duration() - yesterday.Daylight.Duration(); change != 0 {
+		text += " (" + formatChange(change) + " vs yesterday)"
+	}
+	return text
+}
+
+// sunriseToSunset works out the day length from the provider's sunrise and
+// sunset when the coordinates are unknown.
+func sunriseToSunset(a forecast.Astro) string {
+	rise, riseErr := time.Parse("03:04 PM", a.Sunrise)
+	set, setErr := time.Parse("03:04 PM", a.Sunset)
+	if riseErr != nil || setErr != nil || !set.After(rise) {
+		return ""
+	}
+	return formatHoursMinutes(set.Sub(rise))
+}
+
+func formatDayLength(c astro.Crossing) string {
+	switch {
+	case c.AlwaysAbove:
+		return "24h 0m (midnight sun)"
+	case c.AlwaysBelow:
+		return "0h 0m (polar night)"
+	default:
+		return formatHoursMinutes(c.Duration())
+	}
+}
+
+func formatHoursMinutes(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// formatChange formats a day-length change such as "+2m 10s".
+func formatChange(d time.Duration) string {
+	sign := "+"
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%s%dm %ds", sign, int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// moonPhase formats the phase with its icon and illumination.
+func moonPhase(a forecast.Astro) string {
+	if a.MoonPhase == "" {
+		return ""
+	}
+	text := a.MoonPhase
+	if icon := ui.GetMoonIcon(a.MoonPhase); icon != "" {
+		text = icon + " " + text
+	}
+	if a.MoonIllumination == nil {
+		return text
+	}
+	return fmt.Sprintf("%s, %d%% illuminated", text, *a.MoonIllumination)
+}
+
+func withIcon(icon, value string) string {
+	if value == "" {
+		return ""
+	}
+	return ui.GetIcon(icon) + " " + value
+}
+
+func upOrDown(up bool) string {
+	if up {
+		return "up"
+	}
+	return "down"
+}
+
+// isToday reports whether date is the location's current date.
+func (d *Display) isToday(date string) bool {
+	return strings.HasPrefix(d.data.Location.LocalTime, date)
+}
@@ -0,0 +1,110 @@
+package weather
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+)
+
+func astroResponse(loc forecast.Location, date string, a forecast.Astro) *forecast.Response {
+	return &forecast.Response{
+		Location: loc,
+		Days:     []forecast.Day{{Date: date, Astro: a}},
+	}
+}
+
+var equinoxAstro = forecast.Astro{
+	Sunrise:          "06:02 AM",
+	Sunset:           "06:14 PM",
+	Moonrise:         "07:10 AM",
+	Moonset:          "No moonset",
+	MoonPhase:        "Waxing Crescent",
+	MoonIllumination: forecast.Ptr(3),
+	IsSunUp:          forecast.Ptr(true),
+	IsMoonUp:         forecast.Ptr(false),
+}
+
+func TestAstronomy(t *testing.T) {
+	london := forecast.Location{Lat: 51.5, Lon: -0.13, TimeZone: "Europe/London", LocalTime: "2026-03-20 10:00"}
+
+	tests := []struct {
+		name    string
+		data    *forecast.Response
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "coordinates add local twilight",
+			data: astroResponse(london, "2026-03-20", equinoxAstro),
+			want: []string{
+				"Astronomy (Fri, Mar 20):",
+				"Astronomical dawn: 04:10 AM",
+				"Civil dawn:        05:30 AM",
+				"Sunrise:           🌅 06:02 AM",
+				"Civil dusk:        06:45 PM",
+				"Astronomical dusk: 08:05 PM",
+				"Day length:        12h 8m (+3m 58s vs yesterday)",
+				"Moon phase:        🌒 Waxing Crescent, 3% illuminated",
+				"Moonset:           No moonset",
+				"Now:               sun up, moon down",
+			},
+		},
+		{
+			name:    "no coordinates uses the provider times",
+			data:    astroResponse(forecast.Location{}, "2026-03-20", equinoxAstro),
+			want:    []string{"Sunrise:           🌅 06:02 AM", "Day length:        12h 12m\n"},
+			notWant: []string{"dawn", "vs yesterday", "Now:"},
+		},
+		{
+			name:    "unknown time zone shows UTC",
+			data:    astroResponse(forecast.Location{Lat: 51.5, Lon: -0.13}, "2026-03-20", forecast.Astro{}),
+			want:    []string{"Sunrise:           🌅 06:03 AM UTC", "Civil dawn:        05:30 AM UTC"},
+			notWant: []string{"Moon"},
+		},
+		{
+			name: "midnight sun",
+			data: astroResponse(forecast.Location{Lat: 69.65, Lon: 18.96, TimeZone: "Europe/Oslo"}, "2026-06-21", forecast.Astro{}),
+			want: []string{
+				"Civil dusk:        none (the sun stays above)",
+				"Day length:        24h 0m (midnight sun)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			display, err := NewDisplay(tt.data, true)
+			if err != nil {
+				t.Fatalf("NewDisplay() error = %v", err)
+			}
+
+			got := display.Astronomy()
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Astronomy() missing %q in:\n%s", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("Astronomy() contains %q in:\n%s", notWant, got)
+				}
+			}
+		})
+	}
+}
+
+func TestTwilight_DayLengthAndMoon(t *testing.T) {
+	london := forecast.Location{Lat: 51.5, Lon: -0.13, TimeZone: "Europe/London"}
+	display, err := NewDisplay(astroResponse(london, "2026-03-20", equinoxAstro), true)
+	if err != nil {
+		t.Fatalf("NewDisplay() error = %v", err)
+	}
+
+	want := "Sunrise: 🌅 06:02 AM | Sunset: 🌇 06:14 PM\n" +
+		"Day length: 12h 8m (+3m 58s vs yesterday)\n" +
+		"Moon: 🌒 Waxing Crescent, 3% illuminated | Moonrise: 07:10 AM | Moonset: No moonset"
+	if got := display.Twilight(); got != want {
+		t.Errorf("Twilight() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,201 @@
+package weather
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/screen"
+	"github.com/jtotty/weather-cli/internal/ui"
+	"github.com/jtotty/weather-cli/internal/units"
+)
+
+// maxConditionWidth truncates condition texts so columns stay narrow.
+const maxConditionWidth = 18
+
+// CompareColumn is one location in a comparison. Err is set instead of Data
+// when the location could not be fetched.
+type CompareColumn struct {
+	Title string
+	Data  *forecast.Response
+	Err   error
+}
+
+// Comparison renders several locations side by side.
+type Comparison struct {
+	columns []CompareColumn
+	units   units.Units
+}
+
+// NewComparison creates a comparison of the given columns, in order.
+func NewComparison(columns []CompareColumn) *Comparison {
+	return &Comparison{columns: columns, units: units.Default()}
+}
+
+// WithUnits sets the units values are shown in.
+func (c *Comparison) WithUnits(u units.Units) *Comparison {
+	c.units = u
+	return c
+}
+
+// available returns the columns that have data.
+func (c *Comparison) available() []CompareColumn {
+	var cols []CompareColumn
+	for _, col := range c.columns {
+		if col.Err == nil && col.Data != nil {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// Current renders a table of current conditions, one column per location.
+func (c *Comparison) Current() string {
+	cols := c.available()
+	if len(cols) == 0 {
+		return "Current Conditions: No data available\n"
+	}
+
+	rows := [][]string{
+		{"Condition"}, {"Temp"}, {"Feels like"}, {"Wind"}, {"Humidity"}, {"AQI (PM2.5)"},
+	}
+	for _, col := range cols {
+		cur := col.Data.Current
+		rows[0] = append(rows[0], truncate(cur.Condition.Text, maxConditionWidth))
+		rows[1] = append(rows[1], formatTemp(c.units, cur.TempC, cur.TempF))
+		feelsLike := "-"
+		if cur.FeelsLikeC != nil && cur.FeelsLikeF != nil {
+			feelsLike = formatTemp(c.units, *cur.FeelsLikeC, *cur.FeelsLikeF)
+		}
+		rows[2] = append(rows[2], feelsLike)
+		rows[3] = append(rows[3], formatWind(c.units, cur))
+		rows[4] = append(rows[4], column(cur.Humidity, 1, "%.0f%%"))
+		aqi := "-"
+		if cur.AirQuality != nil {
+			aqi = fmt.Sprintf("%s %.0f", ui.GetAqiIcon(cur.AirQuality.PM25), cur.AirQuality.PM25)
+		}
+		rows[5] = append(rows[5], aqi)
+	}
+
+	return "Current Conditions:\n" + table(header(cols), rows)
+}
+
+// Daily renders a matrix of high / low / chance of rain per day. Rows follow
+// the first location's dates; locations are aligned by day index.
+func (c *Comparison) Daily() string {
+	cols := c.available()
+	if len(cols) == 0 {
+		return "Daily Forecast: No data available\n"
+	}
+
+	var rows [][]string
+	for i, fd := range cols[0].Data.Days {
+		label := fd.Date
+		if date, err := time.Parse("2006-01-02", fd.Date); err == nil {
+			label = date.Format("Mon 02")
+		}
+
+		row := []string{label}
+		for _, col := range cols {
+			days := col.Data.Days
+			if i >= len(days) {
+				row = append(row, "-")
+				continue
+			}
+			day := days[i].Summary
+			row = append(row, fmt.Sprintf("%s / %s %s",
+				formatTemp(c.units, day.MaxTempC, day.MaxTempF),
+				formatTemp(c.units, day.MinTempC, day.MinTempF),
+				column(day.ChanceOfRain, 4, "%3.0f%%")))
+		}
+		rows = append(rows, row)
+	}
+
+	return "Daily High / Low / Rain:\n" + table(header(cols), rows)
+}
+
+// Errors lists the locations that could not be fetched, or "" if none.
+func (c *Comparison) Errors() string {
+	var b strings.Builder
+	for _, col := range c.columns {
+		if col.Err != nil {
+			fmt.Fprintf(&b, "%s: %v\n", col.Title, col.Err)
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "Unavailable:\n" + b.String()
+}
+
+// Stale lists the locations shown from expired cache entries, or "" if none.
+func (c *Comparison) Stale() string {
+	var b strings.Builder
+	for _, col := range c.available() {
+		if banner := StaleBanner(col.Data, time.Now()); banner != "" {
+			fmt.Fprintf(&b, "%s: %s\n", col.Title, banner)
+		}
+	}
+	return b.String()
+}
+
+// Render outputs the comparison to stdout.
+func (c *Comparison) Render() {
+	if stale := c.Stale(); stale != "" {
+		fmt.Print(stale)
+		fmt.Println()
+	}
+	fmt.Print(c.Current())
+	fmt.Println()
+	fmt.Print(c.Daily())
+
+	if errs := c.Errors(); errs != "" {
+		fmt.Println()
+		fmt.Print(errs)
+	}
+}
+
+func header(cols []CompareColumn) []string {
+	h := []string{""}
+	for _, col := range cols {
+		h = append(h, col.Title)
+	}
+	return h
+}
+
+// table lays out rows under header with " | " separators, padding each
+// column to its widest visible cell.
+func table(header []string, rows [][]string) string {
+	all := append([][]string{header}, rows...)
+
+	widths := make([]int, len(header))
+	for _, row := range all {
+		for i, cell := range row {
+			widths[i] = max(widths[i], screen.Width(cell))
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range all {
+		for i, cell := range row {
+			if i > 0 {
+				b.WriteString(" | ")
+			}
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-screen.Width(cell)))
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
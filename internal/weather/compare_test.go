@@ -0,0 +1,109 @@
+package weather
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/screen"
+)
+
+func compareData(name string, temp float32, days ...forecast.Summary) *forecast.Response {
+	data := &forecast.Response{
+		Location: forecast.Location{Name: name},
+		Current: forecast.Current{
+			TempC:         temp,
+			FeelsLikeC:    forecast.Ptr(temp - 2),
+			FeelsLikeF:    forecast.Ptr((temp-2)*9/5 + 32),
+			Condition:     forecast.Condition{Text: "Partly cloudy"},
+			WindDirection: "SW",
+			WindMph:       forecast.Ptr[float32](10),
+			WindKph:       forecast.Ptr[float32](16.1),
+			Humidity:      forecast.Ptr[float32](70),
+		},
+	}
+	for i, day := range days {
+		date := []string{"2025-12-01", "2025-12-02", "2025-12-03"}[i]
+		data.Days = append(data.Days, forecast.Day{Date: date, Summary: day})
+	}
+	return data
+}
+
+func TestComparison_Current(t *testing.T) {
+	c := NewComparison([]CompareColumn{
+		{Title: "London", Data: compareData("London", 12)},
+		{Title: "Paris", Data: compareData("Paris", 15)},
+		{Title: "Atlantis", Err: errors.New("no matching location found")},
+	})
+
+	result := c.Current()
+
+	for _, want := range []string{"London", "Paris", "12°C", "15°C", "10°C", "13°C", "SW 10 mph", "70%"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Current() = %q, want string containing %q", result, want)
+		}
+	}
+	if strings.Contains(result, "Atlantis") {
+		t.Errorf("Current() = %q, should not include failed locations", result)
+	}
+
+	// Every row has the same separator positions.
+	lines := strings.Split(strings.TrimSuffix(result, "\n"), "\n")[1:]
+	first := strings.Index(lines[0], "|")
+	for _, line := range lines {
+		if i := strings.Index(line, "|"); screen.Width(line[:i]) != screen.Width(lines[0][:first]) {
+			t.Errorf("misaligned row %q", line)
+		}
+	}
+}
+
+func TestComparison_Daily(t *testing.T) {
+	c := NewComparison([]CompareColumn{
+		{Title: "London", Data: compareData("London", 12,
+			forecast.Summary{MaxTempC: 14, MinTempC: 6, ChanceOfRain: forecast.Ptr[float32](80)},
+			forecast.Summary{MaxTempC: 11, MinTempC: 4, ChanceOfRain: forecast.Ptr[float32](20)})},
+		{Title: "Madrid", Data: compareData("Madrid", 20,
+			forecast.Summary{MaxTempC: 22, MinTempC: 9, ChanceOfRain: forecast.Ptr[float32](0)})},
+	})
+
+	result := screen.Strip(c.Daily())
+
+	for _, want := range []string{"Mon 01", "Tue 02", "14°C /   6°C  80%", "22°C /   9°C   0%"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Daily() = %q, want string containing %q", result, want)
+		}
+	}
+
+	// Madrid has no second day.
+	lines := strings.Split(strings.TrimSpace(result), "\n")
+	if last := lines[len(lines)-1]; !strings.HasSuffix(last, "| -") {
+		t.Errorf("last row = %q, want missing day shown as -", last)
+	}
+}
+
+func TestComparison_NoData(t *testing.T) {
+	c := NewComparison([]CompareColumn{
+		{Title: "Atlantis", Err: errors.New("no matching location found")},
+		{Title: "@cabin", Err: errors.New("unknown location @cabin")},
+	})
+
+	if !strings.Contains(c.Current(), "No data available") {
+		t.Errorf("Current() = %q, want no data message", c.Current())
+	}
+	if !strings.Contains(c.Daily(), "No data available") {
+		t.Errorf("Daily() = %q, want no data message", c.Daily())
+	}
+
+	want := "Unavailable:\nAtlantis: no matching location found\n@cabin: unknown location @cabin\n"
+	if got := c.Errors(); got != want {
+		t.Errorf("Errors() = %q, want %q", got, want)
+	}
+}
+
+func TestComparison_ErrorsEmpty(t *testing.T) {
+	c := NewComparison([]CompareColumn{{Title: "London", Data: compareData("London", 12)}})
+	if got := c.Errors(); got != "" {
+		t.Errorf("Errors() = %q, want empty", got)
+	}
+}
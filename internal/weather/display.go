@@ -5,38 +5,122 @@ import (
 	"strings"
 	"time"
 
-	api "github.com/jtotty/weather-cli/internal/api/weather"
+	"github.com/jtotty/weather-cli/internal/forecast"
 	"github.com/jtotty/weather-cli/internal/ui"
+	"github.com/jtotty/weather-cli/internal/units"
 )
 
+// Sections lists the display sections in render order.
+var Sections = []string{"time", "current", "hourly", "daily", "twilight", "astro", "alerts"}
+
+// defaultSections are shown when no sections are selected; the detailed
+// astronomy section is only shown on request.
+var defaultSections = []string{"time", "current", "hourly", "daily", "twilight", "alerts"}
+
+// IsSection reports whether name is one of Sections.
+func IsSection(name string) bool {
+	for _, s := range Sections {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AstronomyOnly reports whether sections show nothing but sun and moon
+// data, which a cached forecast keeps fresh for longer than the rest.
+func AstronomyOnly(sections []string) bool {
+	for _, s := range sections {
+		if s != "twilight" && s != "astro" {
+			return false
+		}
+	}
+	return len(sections) > 0
+}
+
 type Display struct {
-	data    *api.Response
-	isLocal bool
+	data     *forecast.Response
+	isLocal  bool
+	units    units.Units
+	sections []string
+	details  bool
+	// viewerTime adds the viewer's own clock to hourly tables.
+	viewerTime bool
+	now        func() time.Time
 }
 
-func NewDisplay(data *api.Response, isLocal bool) (*Display, error) {
+func NewDisplay(data *forecast.Response, isLocal bool) (*Display, error) {
 	if data == nil {
 		return nil, fmt.Errorf("weather data is nil")
 	}
 
-	if len(data.Forecast.Forecastday) == 0 {
+	if len(data.Days) == 0 {
 		return nil, fmt.Errorf("no forecast data available")
 	}
 
 	return &Display{
-		data:    data,
-		isLocal: isLocal,
+		data:     data,
+		isLocal:  isLocal,
+		units:    units.Default(),
+		sections: defaultSections,
+		now:      time.Now,
 	}, nil
 }
 
+// WithUnits sets the units values are shown in.
+func (d *Display) WithUnits(u units.Units) *Display {
+	d.units = u
+	return d
+}
+
+// WithSections limits Render to the named sections. An empty list shows
+// the default sections.
+func (d *Display) WithSections(sections []string) *Display {
+	if len(sections) == 0 {
+		sections = defaultSections
+	}
+	d.sections = sections
+	return d
+}
+
+// WithDetails adds pressure, visibility, gusts, cloud cover, UV and dew
+// point to the current conditions and extra columns to the tables.
+func (d *Display) WithDetails(on bool) *Display {
+	d.details = on
+	return d
+}
+
+// WithViewerTime shows each hour on the viewer's clock alongside the
+// location's, for forecasts of other time zones.
+func (d *Display) WithViewerTime(on bool) *Display {
+	d.viewerTime = on
+	return d
+}
+
+func (d *Display) temp(c, f float32) string {
+	return formatTemp(d.units, c, f)
+}
+
+// formatTemp colors a temperature and shows it in the selected unit.
+func formatTemp(u units.Units, c, f float32) string {
+	return ui.ColorizeTempIn(c, u.TempValue(c, f), u.TempSymbol())
+}
+
 func (d *Display) Heading() string {
+	return d.heading("Weather Forecast for ")
+}
+
+func (d *Display) heading(title string) string {
 	location := d.data.Location
 
 	text := strings.Builder{}
-	text.WriteString("\nWeather Forecast for ")
+	text.WriteString("\n")
+	text.WriteString(title)
 	text.WriteString(location.Name)
-	text.WriteString(", ")
-	text.WriteString(location.Country)
+	if location.Country != "" {
+		text.WriteString(", ")
+		text.WriteString(location.Country)
+	}
 
 	// Calculate border length (excluding leading newline)
 	headerLen := text.Len() - 1
@@ -48,13 +132,13 @@ func (d *Display) Heading() string {
 }
 
 func (d *Display) Time() string {
-	if d.data == nil || d.data.Location == (api.Location{}) {
+	if d.data == nil || d.data.Location == (forecast.Location{}) {
 		return "Time: No data available\n"
 	}
 
 	location := d.data.Location
 	timeFormat := "Mon, Jan 2 - 15:04"
-	now := time.Now()
+	now := d.now()
 
 	localTime, err := time.Parse("2006-01-02 15:04", location.LocalTime)
 	if err != nil {
@@ -71,7 +155,7 @@ func (d *Display) Time() string {
 }
 
 func (d *Display) CurrentConditions() string {
-	if d.data == nil || d.data.Current == (api.Current{}) {
+	if d.data == nil || d.data.Current == (forecast.Current{}) {
 		return "Current Conditions: No data available\n"
 	}
 
@@ -83,124 +167,307 @@ func (d *Display) CurrentConditions() string {
 	output.WriteString(" ")
 	output.WriteString(c.Condition.Text)
 	output.WriteString(", ")
-	output.WriteString(ui.ColorizeTemp(c.TempC))
-	output.WriteString(" (Feels like ")
-	output.WriteString(ui.ColorizeTemp(c.FeelsLike))
-	output.WriteString(")\n")
+	output.WriteString(d.temp(c.TempC, c.TempF))
+	if c.FeelsLikeC != nil && c.FeelsLikeF != nil {
+		output.WriteString(" (Feels like ")
+		output.WriteString(d.temp(*c.FeelsLikeC, *c.FeelsLikeF))
+		output.WriteString(")")
+	}
+	output.WriteString("\n")
 
 	output.WriteString("Wind: ")
 	output.WriteString(ui.GetIcon("wind"))
 	output.WriteString(" ")
-	output.WriteString(c.WindDirection)
-	output.WriteString(" ")
-	fmt.Fprintf(&output, "%.0f", c.WindSpeed)
-	output.WriteString(" mph | ")
+	output.WriteString(formatWind(d.units, c))
+	output.WriteString(" | ")
 
 	output.WriteString("Humidity: ")
 	output.WriteString(ui.GetIcon("humidity"))
 	output.WriteString(" ")
-	fmt.Fprintf(&output, "%.0f", c.Humidity)
-	output.WriteString("% | ")
+	output.WriteString(column(c.Humidity, 1, "%.0f%%"))
+
+	if c.AirQuality != nil {
+		output.WriteString(" | AQI: ")
+		output.WriteString(ui.GetAqiIcon(c.AirQuality.PM25))
+		output.WriteString(" ")
+		fmt.Fprintf(&output, "%.0f", c.AirQuality.PM25)
+		output.WriteString(" (PM2.5)")
+	}
 
-	output.WriteString("AQI: ")
-	output.WriteString(ui.GetAqiIcon(c.AirQuality.PM25))
-	output.WriteString(" ")
-	fmt.Fprintf(&output, "%.0f", c.AirQuality.PM25)
-	output.WriteString(" (PM2.5)")
+	if d.details {
+		output.WriteString("\n")
+		output.WriteString(d.currentDetails())
+	}
 
 	return output.String()
 }
 
+// currentDetails formats the --details lines of the current conditions,
+// omitting readings the provider did not report.
+func (d *Display) currentDetails() string {
+	c := d.data.Current
+	u := d.units
+
+	var first, second []string
+	if p := optional(c.PressureMb, c.PressureIn, u.PressureValue); p != nil {
+		pressure := fmt.Sprintf("%.0f", *p)
+		if u.Pressure == units.InchesHg {
+			pressure = fmt.Sprintf("%.2f", *p)
+		}
+		first = append(first, "Pressure: "+pressure+" "+u.PressureSymbol())
+	}
+	if v := optional(c.VisKm, c.VisMiles, u.DistanceValue); v != nil {
+		first = append(first, fmt.Sprintf("Visibility: %.0f %s", *v, u.DistanceSymbol()))
+	}
+	if c.Cloud != nil {
+		first = append(first, fmt.Sprintf("Cloud: %.0f%%", *c.Cloud))
+	}
+	if c.UV != nil {
+		first = append(first, fmt.Sprintf("UV: %.0f", *c.UV))
+	}
+	if g := optional(c.GustMph, c.GustKph, u.SpeedValue); g != nil {
+		second = append(second, fmt.Sprintf("Gusts: %.0f %s", *g, u.SpeedSymbol()))
+	}
+	if p := optional(c.PrecipMm, c.PrecipIn, u.PrecipValue); p != nil {
+		second = append(second, fmt.Sprintf("Precip: %.1f %s", *p, u.PrecipSymbol()))
+	}
+	for _, t := range []struct {
+		label string
+		c, f  *float32
+	}{
+		{"Dew point", c.DewPointC, c.DewPointF},
+		{"Heat index", c.HeatIndexC, c.HeatIndexF},
+		{"Wind chill", c.WindChillC, c.WindChillF},
+	} {
+		if t.c != nil && t.f != nil {
+			second = append(second, t.label+": "+d.temp(*t.c, *t.f))
+		}
+	}
+
+	var lines []string
+	for _, line := range [][]string{first, second} {
+		if len(line) > 0 {
+			lines = append(lines, strings.Join(line, " | "))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// optional picks the metric or imperial variant of a reading with value,
+// or returns nil when the provider did not report it.
+func optional(metric, imperial *float32, value func(metric, imperial float32) float32) *float32 {
+	if metric == nil || imperial == nil {
+		return nil
+	}
+	v := value(*metric, *imperial)
+	return &v
+}
+
+// formatWind shows the current wind direction and speed in the selected
+// unit, "-" when neither was reported.
+func formatWind(u units.Units, c forecast.Current) string {
+	parts := make([]string, 0, 2)
+	if c.WindDirection != "" {
+		parts = append(parts, c.WindDirection)
+	}
+	if speed := optional(c.WindMph, c.WindKph, u.SpeedValue); speed != nil {
+		parts = append(parts, fmt.Sprintf("%.0f %s", *speed, u.SpeedSymbol()))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " ")
+}
+
+// column formats an optional reading right-aligned in width columns with
+// format, showing "-" when it was not reported.
+func column(v *float32, width int, format string) string {
+	if v == nil {
+		return fmt.Sprintf("%*s", width, "-")
+	}
+	return fmt.Sprintf(format, *v)
+}
+
 func (d *Display) HourlyForecast() string {
-	if d.data == nil || len(d.data.Forecast.Forecastday) == 0 {
+	if d.data == nil || len(d.data.Days) == 0 {
 		return "Hourly Forecast: No data available\n"
 	}
 
-	hours := d.data.Forecast.Forecastday[0].Hour
+	hours := d.upcomingHours()
 	if len(hours) == 0 {
 		return "Hourly Forecast: No hourly data available\n"
 	}
 
 	output := strings.Builder{}
 	output.WriteString("Hourly Forecast:\n")
-	output.WriteString("Time  | Temp  | Rain | Condition\n")
+	output.WriteString(d.hourHeader())
 
-	currentTime := time.Now()
-	year, month, day := currentTime.Date()
-	startOfNextDay := time.Date(year, month, day, 0, 0, 0, 0, currentTime.Location()).Add(24 * time.Hour)
+	var previous time.Time
+	for i, hour := range hours {
+		date := d.hourTime(hour)
+		if i > 0 && date.YearDay() != previous.YearDay() {
+			output.WriteString("\n")
+			output.WriteString(date.Format("Mon 02"))
+		}
+		previous = date
 
-	for _, hour := range hours {
-		date := time.Unix(hour.TimeEpoch, 0)
+		output.WriteString("\n")
+		output.WriteString(d.hourRow(hour, date))
+	}
 
-		if date.Before(currentTime) {
-			continue
-		}
+	return output.String()
+}
 
-		if date.Equal(startOfNextDay) {
-			break
+// minHourlyRows is how many hours the hourly table shows at least, taking
+// them from the next day once few hours of today remain.
+const minHourlyRows = 12
+
+// upcomingHours returns the hours still to come today at the location,
+// followed by tomorrow's when fewer than minHourlyRows remain.
+func (d *Display) upcomingHours() []forecast.Hour {
+	now := d.now()
+	today := now.In(d.locationZone()).Format("2006-01-02")
+
+	var hours []forecast.Hour
+	for _, day := range d.data.Days {
+		for _, hour := range day.Hours {
+			if time.Unix(hour.TimeUnix, 0).Before(now) {
+				continue
+			}
+			if day.Date > today && len(hours) >= minHourlyRows {
+				return hours
+			}
+			hours = append(hours, hour)
 		}
+	}
+	return hours
+}
 
-		newLine := "\n"
-		if date.Add(time.Hour).Equal(startOfNextDay) {
-			newLine = ""
-		}
+// locationZone returns the location's time zone, falling back to the
+// viewer's when the provider did not report one.
+func (d *Display) locationZone() *time.Location {
+	if zone, ok := d.data.Location.Zone(); ok {
+		return zone
+	}
+	return d.now().Location()
+}
+
+// hourTime returns the start of hour on the location's clock.
+func (d *Display) hourTime(hour forecast.Hour) time.Time {
+	return time.Unix(hour.TimeUnix, 0).In(d.locationZone())
+}
 
-		output.WriteString(
-			fmt.Sprintf(
-				"%s | %s | %3.0f%% | %s %s%s",
-				date.Format("15:04"),
-				ui.ColorizeTemp(hour.TempC),
-				hour.ChanceOfRain,
-				ui.GetWeatherIcon(hour.Condition.Text),
-				hour.Condition.Text,
-				newLine,
-			),
+// hourHeader returns the header of the hourly table.
+func (d *Display) hourHeader() string {
+	header := "Time  | "
+	if d.viewerTime {
+		header += "Your time | "
+	}
+	header += "Temp  | Rain | "
+	if d.details {
+		header += "Wind    | Gust | Cloud | UV | "
+	}
+	return header + "Condition"
+}
+
+// hourRow formats an hour for the hourly table. date is the start of the
+// hour on the location's clock.
+func (d *Display) hourRow(hour forecast.Hour, date time.Time) string {
+	var details string
+	if d.details {
+		details = fmt.Sprintf(
+			"%s %-3s | %s | %s | %s | ",
+			column(optional(hour.WindMph, hour.WindKph, d.units.SpeedValue), 3, "%3.0f"),
+			hour.WindDirection,
+			column(optional(hour.GustMph, hour.GustKph, d.units.SpeedValue), 4, "%4.0f"),
+			column(hour.Cloud, 5, "%4.0f%%"),
+			column(hour.UV, 2, "%2.0f"),
 		)
 	}
 
-	return output.String()
+	clock := date.Format("15:04")
+	if d.viewerTime {
+		clock += " | " + date.In(d.now().Location()).Format("Mon 15:04")
+	}
+
+	return fmt.Sprintf(
+		"%s | %s | %s | %s%s %s",
+		clock,
+		d.temp(hour.TempC, hour.TempF),
+		column(hour.ChanceOfRain, 4, "%3.0f%%"),
+		details,
+		ui.GetWeatherIcon(hour.Condition.Text),
+		hour.Condition.Text,
+	)
 }
 
 func (d *Display) DailyForecast() string {
-	if d.data == nil || len(d.data.Forecast.Forecastday) <= 1 {
+	if d.data == nil || len(d.data.Days) <= 1 {
 		return "Daily Forecast: No data available\n"
 	}
 
 	output := strings.Builder{}
 	output.WriteString("Daily Forecast:\n")
-	output.WriteString("Day    | High  | Low   | Rain | Condition\n")
+	output.WriteString(d.dayHeader())
+	output.WriteString("\n")
 
 	// Skip today (index 0), show future days only
-	forecastDays := d.data.Forecast.Forecastday[1:]
+	forecastDays := d.data.Days[1:]
 	for i := range forecastDays {
-		day := &forecastDays[i]
-		date, err := time.Parse("2006-01-02", day.Date)
-		if err != nil {
-			continue
+		if row, ok := d.dayRow(&forecastDays[i]); ok {
+			output.WriteString(row)
+			output.WriteString("\n")
 		}
+	}
 
-		output.WriteString(
-			fmt.Sprintf(
-				"%s | %s | %s | %3d%% | %s %s\n",
-				date.Format("Mon 02"),
-				ui.ColorizeTemp(day.Day.MaxTempC),
-				ui.ColorizeTemp(day.Day.MinTempC),
-				day.Day.ChanceOfRain,
-				ui.GetWeatherIcon(day.Day.Condition.Text),
-				day.Day.Condition.Text,
-			),
+	return strings.TrimSuffix(output.String(), "\n")
+}
+
+// dayRow formats a day for the daily table, reporting false when its date
+// cannot be parsed.
+func (d *Display) dayRow(day *forecast.Day) (string, bool) {
+	date, err := time.Parse("2006-01-02", day.Date)
+	if err != nil {
+		return "", false
+	}
+
+	s := &day.Summary
+	var details string
+	if d.details {
+		details = fmt.Sprintf(
+			"%s | %s | %s | ",
+			column(optional(s.MaxWindMph, s.MaxWindKph, d.units.SpeedValue), 4, "%4.0f"),
+			column(optional(s.TotalPrecipMm, s.TotalPrecipIn, d.units.PrecipValue), 6, "%6.1f"),
+			column(s.UV, 2, "%2.0f"),
 		)
 	}
 
-	return strings.TrimSuffix(output.String(), "\n")
+	return fmt.Sprintf(
+		"%s | %s | %s | %s | %s%s %s",
+		date.Format("Mon 02"),
+		d.temp(s.MaxTempC, s.MaxTempF),
+		d.temp(s.MinTempC, s.MinTempF),
+		column(s.ChanceOfRain, 4, "%3.0f%%"),
+		details,
+		ui.GetWeatherIcon(s.Condition.Text),
+		s.Condition.Text,
+	), true
+}
+
+// dayHeader returns the header of the daily table.
+func (d *Display) dayHeader() string {
+	if d.details {
+		return "Day    | High  | Low   | Rain | Wind | Precip | UV | Condition"
+	}
+	return "Day    | High  | Low   | Rain | Condition"
 }
 
 func (d *Display) Twilight() string {
-	if d.data == nil || len(d.data.Forecast.Forecastday) == 0 {
+	if d.data == nil || len(d.data.Days) == 0 {
 		return "Twilight: No data available\n"
 	}
 
-	astro := d.data.Forecast.Forecastday[0].Astro
+	astro := d.data.Days[0].Astro
 	if astro.Sunrise == "" || astro.Sunset == "" {
 		return "Twilight: No sunrise or sunset data available\n"
 	}
@@ -216,18 +483,32 @@ func (d *Display) Twilight() string {
 	output.WriteString(" ")
 	output.WriteString(astro.Sunset)
 
+	if length := d.dayLength(); length != "" {
+		output.WriteString("\nDay length: ")
+		output.WriteString(length)
+	}
+	if phase := moonPhase(astro); phase != "" {
+		output.WriteString("\nMoon: ")
+		output.WriteString(phase)
+		for _, m := range [][2]string{{"Moonrise", astro.Moonrise}, {"Moonset", astro.Moonset}} {
+			if m[1] != "" {
+				output.WriteString(" | " + m[0] + ": " + m[1])
+			}
+		}
+	}
+
 	return output.String()
 }
 
 func (d *Display) Warnings() string {
-	if d.data == nil || len(d.data.Alerts.Alert) == 0 {
+	if d.data == nil || len(d.data.Alerts) == 0 {
 		return "Weather Warnings: None\n"
 	}
 
 	output := strings.Builder{}
 	output.WriteString("Weather Warnings: ")
 
-	for i, alert := range d.data.Alerts.Alert {
+	for i, alert := range d.data.Alerts {
 		if i > 0 {
 			output.WriteString("\n")
 		}
@@ -237,25 +518,86 @@ func (d *Display) Warnings() string {
 	return output.String()
 }
 
-// Render outputs the complete weather display to stdout.
-func (d *Display) Render() {
-	fmt.Print(d.Heading())
-	ui.Spacer()
+func (d *Display) Footer() string {
+	if d.data == nil || d.data.Provider == "" {
+		return ""
+	}
+
+	return "Data provided by " + d.data.Provider
+}
+
+// StaleBanner warns that data was served from an expired cache entry, such
+// as "Offline: data is 3 hours old", or returns "" for fresh data.
+func StaleBanner(data *forecast.Response, now time.Time) string {
+	if data == nil || !data.Stale {
+		return ""
+	}
+	return ui.Highlight("Offline: data is " + formatAge(now.Sub(data.FetchedAt)) + " old")
+}
 
-	fmt.Print(d.Time())
-	ui.Spacer()
+// formatAge formats an age in whole minutes, hours or days.
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Hour:
+		return plural(int(age.Minutes()), "minute")
+	case age < 48*time.Hour:
+		return plural(int(age.Hours()), "hour")
+	default:
+		return plural(int(age.Hours()/24), "day")
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
 
-	fmt.Print(d.CurrentConditions())
-	ui.Spacer()
+// String returns the heading, any stale-data banner, the selected sections
+// and the footer.
+func (d *Display) String() string {
+	var b strings.Builder
+	b.WriteString(d.Heading())
+	if banner := StaleBanner(d.data, d.now()); banner != "" {
+		b.WriteString("\n")
+		b.WriteString(banner)
+	}
 
-	fmt.Print(d.HourlyForecast())
-	ui.Spacer()
+	for _, name := range d.sections {
+		b.WriteString("\n\n")
+		b.WriteString(d.section(name))
+	}
 
-	fmt.Print(d.DailyForecast())
-	ui.Spacer()
+	if footer := d.Footer(); footer != "" {
+		b.WriteString("\n\n")
+		b.WriteString(footer)
+	}
+	return b.String()
+}
 
-	fmt.Print(d.Twilight())
-	ui.Spacer()
+// Render outputs the weather display to stdout.
+func (d *Display) Render() {
+	fmt.Print(d.String())
+}
 
-	fmt.Print(d.Warnings())
+func (d *Display) section(name string) string {
+	switch name {
+	case "time":
+		return d.Time()
+	case "current":
+		return d.CurrentConditions()
+	case "hourly":
+		return d.HourlyForecast()
+	case "daily":
+		return d.DailyForecast()
+	case "twilight":
+		return d.Twilight()
+	case "astro":
+		return d.Astronomy()
+	case "alerts":
+		return d.Warnings()
+	default:
+		return ""
+	}
 }
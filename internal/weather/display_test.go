@@ -3,14 +3,16 @@ package weather
 import (
 	"strings"
 	"testing"
+	"time"
 
-	api "github.com/jtotty/weather-cli/internal/api/weather"
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/units"
 )
 
 func TestNewDisplay_Validation(t *testing.T) {
 	tests := []struct {
 		name    string
-		data    *api.Response
+		data    *forecast.Response
 		wantErr string
 	}{
 		{
@@ -20,15 +22,15 @@ func TestNewDisplay_Validation(t *testing.T) {
 		},
 		{
 			name: "empty forecast",
-			data: &api.Response{
-				Forecast: api.Forecast{Forecastday: []api.ForecastDay{}},
+			data: &forecast.Response{
+				Days: []forecast.Day{},
 			},
 			wantErr: "no forecast",
 		},
 		{
 			name: "valid data",
-			data: &api.Response{
-				Forecast: api.Forecast{Forecastday: []api.ForecastDay{{}}},
+			data: &forecast.Response{
+				Days: []forecast.Day{{}},
 			},
 			wantErr: "",
 		},
@@ -61,11 +63,9 @@ func TestNewDisplay_Validation(t *testing.T) {
 }
 
 func TestHourlyForecast_EmptyHours(t *testing.T) {
-	data := &api.Response{
-		Forecast: api.Forecast{
-			Forecastday: []api.ForecastDay{
-				{Hour: []api.Hour{}},
-			},
+	data := &forecast.Response{
+		Days: []forecast.Day{
+			{Hours: []forecast.Hour{}},
 		},
 	}
 
@@ -82,11 +82,9 @@ func TestHourlyForecast_EmptyHours(t *testing.T) {
 }
 
 func TestTwilight_MissingData(t *testing.T) {
-	data := &api.Response{
-		Forecast: api.Forecast{
-			Forecastday: []api.ForecastDay{
-				{Astro: api.Astro{Sunrise: "", Sunset: ""}},
-			},
+	data := &forecast.Response{
+		Days: []forecast.Day{
+			{Astro: forecast.Astro{Sunrise: "", Sunset: ""}},
 		},
 	}
 
@@ -111,22 +109,22 @@ func TestTwilight_MissingData(t *testing.T) {
 func TestWarnings_MultipleAlerts(t *testing.T) {
 	tests := []struct {
 		name   string
-		alerts []api.Alert
+		alerts []forecast.Alert
 		want   string
 	}{
 		{
 			name:   "no alerts",
-			alerts: []api.Alert{},
+			alerts: []forecast.Alert{},
 			want:   "None",
 		},
 		{
 			name:   "one alert",
-			alerts: []api.Alert{{Event: "Flood Warning"}},
+			alerts: []forecast.Alert{{Event: "Flood Warning"}},
 			want:   "Flood Warning",
 		},
 		{
 			name: "multiple alerts",
-			alerts: []api.Alert{
+			alerts: []forecast.Alert{
 				{Event: "Flood Warning"},
 				{Event: "Wind Advisory"},
 			},
@@ -136,9 +134,9 @@ func TestWarnings_MultipleAlerts(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			data := &api.Response{
-				Forecast: api.Forecast{Forecastday: []api.ForecastDay{{}}},
-				Alerts:   api.Alerts{Alert: tt.alerts},
+			data := &forecast.Response{
+				Days:   []forecast.Day{{}},
+				Alerts: tt.alerts,
 			}
 
 			display, err := NewDisplay(data, true)
@@ -164,10 +162,8 @@ func TestWarnings_MultipleAlerts(t *testing.T) {
 
 func TestDailyForecast_NoData(t *testing.T) {
 	// Only one day (today) means no future days to show
-	data := &api.Response{
-		Forecast: api.Forecast{
-			Forecastday: []api.ForecastDay{{}},
-		},
+	data := &forecast.Response{
+		Days: []forecast.Day{{}},
 	}
 
 	display, err := NewDisplay(data, true)
@@ -184,38 +180,36 @@ func TestDailyForecast_NoData(t *testing.T) {
 }
 
 func TestDailyForecast_MultipleDays(t *testing.T) {
-	data := &api.Response{
-		Forecast: api.Forecast{
-			Forecastday: []api.ForecastDay{
-				{
-					// Today - should be skipped
-					Date: "2025-12-01",
-					Day: api.Day{
-						MaxTempC:     15,
-						MinTempC:     8,
-						ChanceOfRain: 20,
-						Condition:    api.Condition{Text: "Sunny"},
-					},
+	data := &forecast.Response{
+		Days: []forecast.Day{
+			{
+				// Today - should be skipped
+				Date: "2025-12-01",
+				Summary: forecast.Summary{
+					MaxTempC:     15,
+					MinTempC:     8,
+					ChanceOfRain: forecast.Ptr[float32](20),
+					Condition:    forecast.Condition{Text: "Sunny"},
 				},
-				{
-					// Tomorrow - should be shown
-					Date: "2025-12-02",
-					Day: api.Day{
-						MaxTempC:     12,
-						MinTempC:     5,
-						ChanceOfRain: 60,
-						Condition:    api.Condition{Text: "Light rain"},
-					},
+			},
+			{
+				// Tomorrow - should be shown
+				Date: "2025-12-02",
+				Summary: forecast.Summary{
+					MaxTempC:     12,
+					MinTempC:     5,
+					ChanceOfRain: forecast.Ptr[float32](60),
+					Condition:    forecast.Condition{Text: "Light rain"},
 				},
-				{
-					// Day after - should be shown
-					Date: "2025-12-03",
-					Day: api.Day{
-						MaxTempC:     10,
-						MinTempC:     3,
-						ChanceOfRain: 30,
-						Condition:    api.Condition{Text: "Cloudy"},
-					},
+			},
+			{
+				// Day after - should be shown
+				Date: "2025-12-03",
+				Summary: forecast.Summary{
+					MaxTempC:     10,
+					MinTempC:     3,
+					ChanceOfRain: forecast.Ptr[float32](30),
+					Condition:    forecast.Condition{Text: "Cloudy"},
 				},
 			},
 		},
@@ -267,3 +261,377 @@ func TestDailyForecast_MultipleDays(t *testing.T) {
 		t.Errorf("DailyForecast() = %q, want string containing 'Light rain'", result)
 	}
 }
+
+func TestHeading(t *testing.T) {
+	tests := []struct {
+		name     string
+		location forecast.Location
+		want     string
+	}{
+		{
+			name:     "name and country",
+			location: forecast.Location{Name: "London", Country: "UK"},
+			want:     "Weather Forecast for London, UK\n",
+		},
+		{
+			name:     "coordinates without country",
+			location: forecast.Location{Name: "51.5000,-0.1000"},
+			want:     "Weather Forecast for 51.5000,-0.1000\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &forecast.Response{
+				Location: tt.location,
+				Days:     []forecast.Day{{}},
+			}
+
+			display, err := NewDisplay(data, true)
+			if err != nil {
+				t.Fatalf("unexpected error creating display: %v", err)
+			}
+
+			result := display.Heading()
+
+			if !strings.Contains(result, tt.want) {
+				t.Errorf("Heading() = %q, want string containing %q", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestFooter(t *testing.T) {
+	data := &forecast.Response{
+		Days: []forecast.Day{{}},
+	}
+
+	display, err := NewDisplay(data, true)
+	if err != nil {
+		t.Fatalf("unexpected error creating display: %v", err)
+	}
+
+	if got := display.Footer(); got != "" {
+		t.Errorf("Footer() = %q, want empty without provider", got)
+	}
+
+	data.Provider = "open-meteo"
+	if got := display.Footer(); !strings.Contains(got, "open-meteo") {
+		t.Errorf("Footer() = %q, want string containing provider", got)
+	}
+}
+
+func TestStaleBanner(t *testing.T) {
+	now := time.Date(2026, 9, 10, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{45 * time.Minute, "data is 45 minutes old"},
+		{time.Hour + 10*time.Minute, "data is 1 hour old"},
+		{5 * time.Hour, "data is 5 hours old"},
+		{72 * time.Hour, "data is 3 days old"},
+	}
+
+	for _, tt := range tests {
+		data := &forecast.Response{Stale: true, FetchedAt: now.Add(-tt.age)}
+		if got := StaleBanner(data, now); !strings.Contains(got, tt.want) {
+			t.Errorf("StaleBanner(%v old) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+
+	if got := StaleBanner(&forecast.Response{FetchedAt: now.Add(-time.Hour)}, now); got != "" {
+		t.Errorf("StaleBanner(fresh) = %q, want empty", got)
+	}
+}
+
+func TestCurrentConditions_Units(t *testing.T) {
+	data := &forecast.Response{
+		Current: forecast.Current{
+			TempC:         20,
+			TempF:         68,
+			FeelsLikeC:    forecast.Ptr[float32](18),
+			FeelsLikeF:    forecast.Ptr[float32](64.4),
+			WindMph:       forecast.Ptr[float32](10),
+			WindKph:       forecast.Ptr[float32](16.1),
+			WindDirection: "SW",
+			Condition:     forecast.Condition{Text: "Sunny"},
+		},
+		Days: []forecast.Day{{}},
+	}
+
+	tests := []struct {
+		name   string
+		system string
+		want   []string
+	}{
+		{"default", "", []string{"20°C", "18°C", "10 mph"}},
+		{"metric", "metric", []string{"20°C", "16 km/h"}},
+		{"imperial", "imperial", []string{"68°F", "64°F", "10 mph"}},
+		{"si", "si", []string{"293K", "4 m/s"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := units.Parse(tt.system, nil)
+			if err != nil {
+				t.Fatalf("units.Parse() error = %v", err)
+			}
+
+			display, err := NewDisplay(data, true)
+			if err != nil {
+				t.Fatalf("unexpected error creating display: %v", err)
+			}
+
+			result := display.WithUnits(u).CurrentConditions()
+
+			for _, want := range tt.want {
+				if !strings.Contains(result, want) {
+					t.Errorf("CurrentConditions() = %q, want string containing %q", result, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithSections(t *testing.T) {
+	data := &forecast.Response{
+		Days: []forecast.Day{{}},
+	}
+
+	display, err := NewDisplay(data, true)
+	if err != nil {
+		t.Fatalf("unexpected error creating display: %v", err)
+	}
+
+	display.WithSections([]string{"alerts", "current"})
+	if got := strings.Join(display.sections, ","); got != "alerts,current" {
+		t.Errorf("sections = %q, want %q", got, "alerts,current")
+	}
+
+	display.WithSections(nil)
+	if got := strings.Join(display.sections, ","); got != strings.Join(defaultSections, ",") {
+		t.Errorf("WithSections(nil) kept %v, want the default sections", display.sections)
+	}
+
+	if got := display.section("alerts"); !strings.Contains(got, "None") {
+		t.Errorf("section(alerts) = %q, want warnings", got)
+	}
+}
+
+func TestAstronomyOnly(t *testing.T) {
+	tests := []struct {
+		sections []string
+		want     bool
+	}{
+		{nil, false},
+		{[]string{"astro"}, true},
+		{[]string{"twilight", "astro"}, true},
+		{[]string{"astro", "current"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := AstronomyOnly(tt.sections); got != tt.want {
+			t.Errorf("AstronomyOnly(%v) = %v, want %v", tt.sections, got, tt.want)
+		}
+	}
+}
+
+func TestWithDetails(t *testing.T) {
+	data := &forecast.Response{
+		Current: forecast.Current{
+			TempC:      20,
+			PressureMb: forecast.Ptr[float32](1013),
+			PressureIn: forecast.Ptr[float32](29.91),
+			VisKm:      forecast.Ptr[float32](9),
+			VisMiles:   forecast.Ptr[float32](5),
+			Cloud:      forecast.Ptr[float32](25),
+			UV:         forecast.Ptr[float32](8),
+			GustMph:    forecast.Ptr[float32](12.6),
+			GustKph:    forecast.Ptr[float32](20.2),
+			DewPointC:  forecast.Ptr[float32](14.7),
+			DewPointF:  forecast.Ptr[float32](58.4),
+			Condition:  forecast.Condition{Text: "Sunny"},
+		},
+		Days: []forecast.Day{
+			{Date: "2025-12-01"},
+			{Date: "2025-12-02", Summary: forecast.Summary{
+				MaxWindMph:    forecast.Ptr[float32](18),
+				MaxWindKph:    forecast.Ptr[float32](29),
+				TotalPrecipMm: forecast.Ptr[float32](4.2),
+				TotalPrecipIn: forecast.Ptr[float32](0.17),
+				UV:            forecast.Ptr[float32](3),
+				Condition:     forecast.Condition{Text: "Light rain"},
+			}},
+			{Date: "2025-12-03", Summary: forecast.Summary{MaxWindMph: forecast.Ptr[float32](9), MaxWindKph: forecast.Ptr[float32](14.5), Condition: forecast.Condition{Text: "Cloudy"}}},
+		},
+	}
+	hour := forecast.Hour{
+		WindMph:       forecast.Ptr[float32](5.6),
+		WindKph:       forecast.Ptr[float32](9),
+		WindDirection: "E",
+		GustMph:       forecast.Ptr[float32](8.7),
+		GustKph:       forecast.Ptr[float32](14),
+		Cloud:         forecast.Ptr[float32](2),
+		UV:            forecast.Ptr[float32](1),
+		Condition:     forecast.Condition{Text: "Clear"},
+	}
+
+	tests := []struct {
+		name    string
+		system  string
+		details bool
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "uk",
+			details: true,
+			want: []string{
+				"Pressure: 1013 mb | Visibility: 5 mi | Cloud: 25% | UV: 8",
+				"Gusts: 13 mph | Dew point: ",
+				"Wind | Precip | UV",
+				"  18 |    4.2 |  3 | ",
+				"   9 |      - |  - | ",
+				"Wind    | Gust | Cloud | UV",
+				"  6 E   |    9 |    2% |  1 | ",
+			},
+			notWant: []string{"Heat index", "Wind chill", "Precip: ", "AQI"},
+		},
+		{
+			name:    "imperial",
+			system:  "imperial",
+			details: true,
+			want:    []string{"Pressure: 29.91 inHg", "Visibility: 5 mi", "58°F"},
+		},
+		{
+			name:    "off",
+			want:    []string{"Time  | Temp  | Rain | Condition"},
+			notWant: []string{"Pressure", "Gust", "Precip"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := units.Parse(tt.system, nil)
+			if err != nil {
+				t.Fatalf("units.Parse() error = %v", err)
+			}
+
+			display, err := NewDisplay(data, true)
+			if err != nil {
+				t.Fatalf("unexpected error creating display: %v", err)
+			}
+			display.WithUnits(u).WithDetails(tt.details)
+
+			result := strings.Join([]string{
+				display.CurrentConditions(),
+				display.DailyForecast(),
+				display.hourHeader(),
+				display.hourRow(hour, time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)),
+			}, "\n")
+
+			for _, want := range tt.want {
+				if !strings.Contains(result, want) {
+					t.Errorf("output missing %q in:\n%s", want, result)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(result, notWant) {
+					t.Errorf("output contains %q in:\n%s", notWant, result)
+				}
+			}
+		})
+	}
+}
+
+func TestHourlyForecast_LocationTime(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	day := func(date string) forecast.Day {
+		start, _ := time.ParseInLocation("2006-01-02", date, tokyo)
+		fd := forecast.Day{Date: date}
+		for i := range 24 {
+			fd.Hours = append(fd.Hours, forecast.Hour{
+				TimeUnix:  start.Add(time.Duration(i) * time.Hour).Unix(),
+				Condition: forecast.Condition{Text: "Clear"},
+			})
+		}
+		return fd
+	}
+	data := &forecast.Response{
+		Location: forecast.Location{Name: "Tokyo", TimeZone: "Asia/Tokyo"},
+		Days:     []forecast.Day{day("2026-10-17"), day("2026-10-18")},
+	}
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		viewerTime bool
+		want       []string
+		notWant    []string
+	}{
+		{
+			name:    "rest of today",
+			now:     time.Date(2026, 10, 17, 8, 30, 0, 0, tokyo),
+			want:    []string{"\n09:00 |", "\n23:00 |"},
+			notWant: []string{"08:00", "Sun 18"},
+		},
+		{
+			name:    "late evening spans into tomorrow",
+			now:     time.Date(2026, 10, 17, 14, 30, 0, 0, london),
+			want:    []string{"\n23:00 |", "\nSun 18\n00:00 |", "\n10:00 |"},
+			notWant: []string{"22:00", "11:00"},
+		},
+		{
+			name:       "viewer time alongside",
+			now:        time.Date(2026, 10, 17, 14, 30, 0, 0, london),
+			viewerTime: true,
+			want:       []string{"Time  | Your time | Temp", "\n23:00 | Sat 15:00 |", "\n10:00 | Sun 02:00 |"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			display, err := NewDisplay(data, false)
+			if err != nil {
+				t.Fatalf("unexpected error creating display: %v", err)
+			}
+			display.now = func() time.Time { return tt.now }
+
+			result := display.WithViewerTime(tt.viewerTime).HourlyForecast()
+			for _, want := range tt.want {
+				if !strings.Contains(result, want) {
+					t.Errorf("HourlyForecast() missing %q in:\n%s", want, result)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(result, notWant) {
+					t.Errorf("HourlyForecast() contains %q in:\n%s", notWant, result)
+				}
+			}
+		})
+	}
+}
+
+func TestHourRow_UnreportedDetails(t *testing.T) {
+	display, err := NewDisplay(&forecast.Response{Days: []forecast.Day{{}}}, true)
+	if err != nil {
+		t.Fatalf("unexpected error creating display: %v", err)
+	}
+	display.WithDetails(true)
+
+	hour := forecast.Hour{TempC: 10, Condition: forecast.Condition{Text: "Clear"}}
+	got := display.hourRow(hour, time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC))
+	if want := "  -     |    - |     - |  - | "; !strings.Contains(got, want) {
+		t.Errorf("hourRow() = %q, want unreported details shown as %q", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+package weather
+
+import "strings"
+
+// History renders observed weather: the daily table with a row per day
+// and, when a single day was requested, that day's hourly table.
+func (d *Display) History() string {
+	days := d.data.Days
+
+	var b strings.Builder
+	b.WriteString(d.heading("Weather History for "))
+	b.WriteString("\n\nDaily Summary:\n")
+	b.WriteString(d.dayHeader())
+	for i := range days {
+		if row, ok := d.dayRow(&days[i]); ok {
+			b.WriteString("\n")
+			b.WriteString(row)
+		}
+	}
+
+	if len(days) == 1 && len(days[0].Hours) > 0 {
+		b.WriteString("\n\nHourly:\n")
+		b.WriteString(d.hourHeader())
+		for _, hour := range days[0].Hours {
+			b.WriteString("\n")
+			b.WriteString(d.hourRow(hour, d.hourTime(hour)))
+		}
+		b.WriteString("\n\n")
+		b.WriteString(d.Twilight())
+	}
+
+	if footer := d.Footer(); footer != "" {
+		b.WriteString("\n\n")
+		b.WriteString(footer)
+	}
+	return b.String()
+}
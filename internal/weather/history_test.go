@@ -0,0 +1,82 @@
+package weather
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/screen"
+)
+
+func historyDay(date string, maxTemp float32, hours int) forecast.Day {
+	start, _ := time.ParseInLocation("2006-01-02", date, time.Local)
+	day := forecast.Day{
+		Date:    date,
+		Summary: forecast.Summary{MaxTempC: maxTemp, MinTempC: 9, ChanceOfRain: forecast.Ptr[float32](100), Condition: forecast.Condition{Text: "Light rain"}},
+		Astro:   forecast.Astro{Sunrise: "06:21 AM", Sunset: "07:44 PM"},
+	}
+	for i := range hours {
+		day.Hours = append(day.Hours, forecast.Hour{
+			TimeUnix:  start.Add(time.Duration(i) * time.Hour).Unix(),
+			TempC:     float32(10 + i),
+			Condition: forecast.Condition{Text: "Cloudy"},
+		})
+	}
+	return day
+}
+
+func TestHistory(t *testing.T) {
+	tests := []struct {
+		name       string
+		days       []forecast.Day
+		want       []string
+		wantHourly bool
+	}{
+		{
+			name: "single day shows hours",
+			days: []forecast.Day{historyDay("2026-09-01", 21, 24)},
+			want: []string{
+				"Weather History for London, United Kingdom",
+				"Tue 01 |  21°C |   9°C | 100% |",
+				"00:00 |  10°C",
+				"23:00 |  33°C",
+				"Sunrise:",
+			},
+			wantHourly: true,
+		},
+		{
+			name: "range shows every day",
+			days: []forecast.Day{historyDay("2026-09-01", 21, 24), historyDay("2026-09-02", 18, 24)},
+			want: []string{"Tue 01 |  21°C", "Wed 02 |  18°C"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &forecast.Response{
+				Location: forecast.Location{Name: "London", Country: "United Kingdom"},
+				Days:     tt.days,
+				Provider: "weatherapi",
+			}
+			display, err := NewDisplay(data, false)
+			if err != nil {
+				t.Fatalf("NewDisplay() error = %v", err)
+			}
+
+			got := screen.Strip(display.History())
+
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("History() = %q, want it to contain %q", got, want)
+				}
+			}
+			if strings.Contains(got, "Hourly:") != tt.wantHourly {
+				t.Errorf("History() hourly table shown = %v, want %v", !tt.wantHourly, tt.wantHourly)
+			}
+			if !strings.HasSuffix(got, "Data provided by weatherapi") {
+				t.Errorf("History() = %q, want the provider footer last", got)
+			}
+		})
+	}
+}
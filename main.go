@@ -6,18 +6,28 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"time"
 
+	api "github.com/jtotty/weather-cli/internal/api/weather"
 	"github.com/jtotty/weather-cli/internal/cli"
 	"github.com/jtotty/weather-cli/internal/config"
 	"github.com/jtotty/weather-cli/internal/credentials"
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/output"
 	"github.com/jtotty/weather-cli/internal/service"
+	"github.com/jtotty/weather-cli/internal/ui"
+	"github.com/jtotty/weather-cli/internal/units"
 	"github.com/jtotty/weather-cli/internal/weather"
+	"golang.org/x/term"
 )
 
 var version = "dev"
 
 func main() {
-	cmd := cli.Parse(os.Args)
+	cmd, err := cli.Parse(os.Args)
+	if err != nil {
+		cli.ExitWithUsageError(err)
+	}
 
 	switch cmd.Type {
 	case cli.CommandHelp:
@@ -32,31 +42,122 @@ func main() {
 		if err := cli.RunDeleteKey(); err != nil {
 			cli.ExitWithError(fmt.Errorf("failed to delete API key: %w", err))
 		}
+	case cli.CommandConfig:
+		if err := cli.RunConfig(cmd.Args, os.Stdout); err != nil {
+			cli.ExitWithError(err)
+		}
+	case cli.CommandCache:
+		if err := cli.RunCache(cmd.Args, os.Stdout); err != nil {
+			cli.ExitWithError(err)
+		}
+	case cli.CommandCompletion:
+		if err := cli.RunCompletion(cmd.Args, os.Stdout); err != nil {
+			cli.ExitWithError(err)
+		}
+	case cli.CommandComplete:
+		if err := cli.RunComplete(cmd.Args, os.Stdout); err != nil {
+			os.Exit(1)
+		}
+	case cli.CommandLocations:
+		if err := cli.RunLocations(context.Background(), cmd.Args, os.Stdout); err != nil {
+			cli.ExitWithError(err)
+		}
 	case cli.CommandWeather:
 		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 		defer cancel()
-		runWeather(ctx, cmd.Location)
+		runWeather(ctx, cmd)
+	case cli.CommandCompare:
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		runCompare(ctx, cmd)
+	case cli.CommandWatch:
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		runWatch(ctx, cmd)
+	case cli.CommandTUI:
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		runTUI(ctx, cmd)
+	case cli.CommandSearch:
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		runSearch(ctx, cmd)
+	case cli.CommandHistory:
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		runHistory(ctx, cmd)
+	}
+}
+
+// prepare builds the configuration for a forecast command: the config file
+// and environment, then the location, then command-line flags.
+func prepare(cmd cli.Command) (*config.Config, output.Format, units.Units) {
+	format, err := output.ParseFormat(cmd.Format)
+	if err != nil {
+		cli.ExitWithError(err)
+	}
+
+	cfg, err := loadConfig(cmd.Provider, cmd.Offline)
+	if err != nil {
+		cli.ExitWithError(err)
+	}
+
+	if cmd.Location != "" {
+		cfg.SetLocation(cmd.Location)
+	}
+
+	if err := cfg.ResolveAlias(); err != nil {
+		cli.ExitWithError(err)
+	}
+
+	if err := applyFlags(cfg, cmd); err != nil {
+		cli.ExitWithError(err)
 	}
+
+	u, err := cfg.Units()
+	if err != nil {
+		cli.ExitWithError(err)
+	}
+
+	return cfg, format, u
 }
 
-func runWeather(ctx context.Context, location string) {
-	cfg, err := loadConfig()
+func runWeather(ctx context.Context, cmd cli.Command) {
+	cfg, format, units := prepare(cmd)
+
+	if cfg.Template != "" && cmd.Format != "" {
+		cli.ExitWithError(errors.New("--format and --template cannot be combined"))
+	}
+
+	resolveLocation(ctx, cfg)
+
+	svc, err := service.NewWeather(cfg)
 	if err != nil {
 		cli.ExitWithError(err)
 	}
 
-	if location != "" {
-		cfg.SetLocation(location)
+	// Sun and moon data stays fresh in the cache for longer than the rest
+	// of the forecast, so a text display of it alone can use it for longer.
+	get := svc.GetWeather
+	if cfg.Template == "" && format == output.FormatText && weather.AstronomyOnly(cfg.Sections) {
+		get = svc.GetAstronomy
 	}
 
-	svc := service.NewWeather(cfg)
-	data, err := svc.GetWeather(ctx)
+	data, err := get(ctx)
 	if err != nil {
-		if errors.Is(err, context.Canceled) {
-			fmt.Fprintln(os.Stderr, "\nRequest canceled.")
-			os.Exit(130)
+		if errors.Is(err, api.ErrInvalidKey) && cli.OfferSetup() {
+			runWeather(ctx, cmd)
+			return
 		}
-		cli.ExitWithError(fmt.Errorf("error fetching weather: %w", err))
+		cli.ExitWithFetchError(err, locationLabel(cfg))
+	}
+
+	if cfg.LocationName != "" {
+		data.Location.Name = cfg.LocationName
+	}
+
+	if writeReport(cfg, format, data) {
+		return
 	}
 
 	display, err := weather.NewDisplay(data, cfg.IsLocal)
@@ -64,12 +165,111 @@ func runWeather(ctx context.Context, location string) {
 		cli.ExitWithError(fmt.Errorf("error creating display: %w", err))
 	}
 
-	display.Render()
+	display.WithUnits(units).WithSections(cfg.Sections).WithDetails(cmd.Details).WithViewerTime(cmd.ViewerTime).Render()
+}
+
+// writeReport renders data through the configured template or structured
+// format, reporting false when it should be displayed as text instead.
+func writeReport(cfg *config.Config, format output.Format, data *forecast.Response) bool {
+	if cfg.Template != "" || format != output.FormatText {
+		if banner := weather.StaleBanner(data, time.Now()); banner != "" {
+			fmt.Fprintln(os.Stderr, banner)
+		}
+	}
+
+	if cfg.Template != "" {
+		if err := output.Template(os.Stdout, cfg.Template, output.NewReport(data)); err != nil {
+			cli.ExitWithError(fmt.Errorf("error rendering template: %w", err))
+		}
+		return true
+	}
+
+	if format != output.FormatText {
+		if err := output.Render(os.Stdout, format, output.NewReport(data)); err != nil {
+			cli.ExitWithError(fmt.Errorf("error writing %s output: %w", format, err))
+		}
+		return true
+	}
+
+	return false
 }
 
-func loadConfig() (*config.Config, error) {
-	cfg, err := config.New()
+// locationLabel names the configured location in error messages.
+func locationLabel(cfg *config.Config) string {
+	switch {
+	case cfg.LocationName != "":
+		return cfg.LocationName
+	case cfg.IsLocal:
+		return "your current location"
+	default:
+		return cfg.Location
+	}
+}
+
+// applyFlags overrides the loaded configuration with command-line flags.
+func applyFlags(cfg *config.Config, cmd cli.Command) error {
+	if cmd.Template != "" {
+		cfg.Template = cmd.Template
+	}
+
+	if cmd.Units != "" {
+		cfg.SetUnits(cmd.Units)
+	}
+
+	if cmd.Days != 0 {
+		if err := cfg.SetDays(cmd.Days); err != nil {
+			return fmt.Errorf("--days: %w", err)
+		}
+	}
+
+	if cmd.Sections != "" {
+		if err := cfg.SetSections(cmd.Sections); err != nil {
+			return fmt.Errorf("--sections: %w", err)
+		}
+	}
+
+	if cmd.Color != "" {
+		cfg.Colors = cmd.Color
+	}
+
+	if cmd.AQI != nil {
+		cfg.IncludeAQI = *cmd.AQI
+	}
+
+	if cmd.Alerts != nil {
+		cfg.Alerts = *cmd.Alerts
+	}
+
+	cfg.Offline, cfg.Refresh = cmd.Offline, cmd.Refresh
+
+	mode, err := ui.ParseColorMode(cfg.Colors)
+	if err != nil {
+		return err
+	}
+	ui.SetColorEnabled(mode.Enabled(term.IsTerminal(int(os.Stdout.Fd()))))
+
+	return nil
+}
+
+// loadConfig loads the configuration and API key, running setup when a key
+// is needed. Offline commands never need one.
+func loadConfig(providers string, offline bool) (*config.Config, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if providers != "" {
+		cfg.SetProviders(providers)
+	}
+
+	apiKey, err := credentials.GetAPIKey()
 	if err == nil {
+		cfg.APIKey = apiKey
+		return cfg, nil
+	}
+
+	if offline || !cfg.RequiresAPIKey() {
 		return cfg, nil
 	}
 
@@ -79,7 +279,7 @@ func loadConfig() (*config.Config, error) {
 		if setupErr := cli.RunSetup(); setupErr != nil {
 			return nil, fmt.Errorf("setup failed: %w", setupErr)
 		}
-		return config.New()
+		return loadConfig(providers, offline)
 	}
 
 	return nil, fmt.Errorf("error loading config: %w", err)
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	api "github.com/jtotty/weather-cli/internal/api/weather"
+	"github.com/jtotty/weather-cli/internal/cache"
+	"github.com/jtotty/weather-cli/internal/cli"
+	"github.com/jtotty/weather-cli/internal/config"
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/output"
+	"golang.org/x/term"
+)
+
+// runSearch lists the weatherapi.com locations matching the query.
+func runSearch(ctx context.Context, cmd cli.Command) {
+	if cmd.Location == "" {
+		cli.ExitWithUsageError(errors.New("usage: weather-cli search QUERY"))
+	}
+
+	format, err := output.ParseFormat(cmd.Format)
+	if err != nil {
+		cli.ExitWithError(err)
+	}
+
+	cfg, err := loadConfig(api.ProviderName, false)
+	if err != nil {
+		cli.ExitWithError(err)
+	}
+
+	client := api.NewClient(cfg.APIKey).WithRetry(cfg.RetryPolicy())
+	if err := cli.RunSearch(ctx, client, cmd.Location, format, os.Stdout); err != nil {
+		if errors.Is(err, forecast.ErrLocationNotFound) || errors.Is(err, api.ErrInvalidKey) || errors.Is(err, context.Canceled) {
+			cli.ExitWithFetchError(err, cmd.Location)
+		}
+		cli.ExitWithError(err)
+	}
+}
+
+// resolveLocation pins the location with pinLocation when weatherapi.com
+// is the first provider; the others geocode names themselves.
+func resolveLocation(ctx context.Context, cfg *config.Config) {
+	if cfg.ProviderChain()[0] == api.ProviderName {
+		pinLocation(ctx, cfg)
+	}
+}
+
+// pinLocation replaces a free-text location with the coordinates of one
+// weatherapi.com search result, asking which one is meant when several
+// places match, so the request and its cache entry refer to a single place.
+// A place the user picked, or the only match, is remembered, so later runs
+// neither search nor ask again; --refresh searches anew.
+func pinLocation(ctx context.Context, cfg *config.Config) {
+	if cfg.LocationName != "" || !cli.NeedsSearch(cfg.Location) {
+		return
+	}
+
+	picker := cli.Picker{
+		In:          os.Stdin,
+		Out:         os.Stderr,
+		Interactive: term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stderr.Fd())),
+	}
+	if places, err := cache.NewNamespace(cache.PlacesNamespace, cache.NoExpiry); err == nil {
+		picker.Places = places
+	}
+
+	place, ok := picker.Remembered(cfg.Location)
+	if !ok || cfg.Refresh {
+		if cfg.Offline || cfg.APIKey == "" {
+			return
+		}
+
+		client := api.NewClient(cfg.APIKey).WithRetry(cfg.RetryPolicy())
+		var err error
+		place, ok, err = picker.Choose(ctx, client, cfg.Location)
+		if err != nil {
+			cli.ExitWithFetchError(err, cfg.Location)
+		}
+		if !ok {
+			return
+		}
+	}
+
+	query := cfg.Location
+	cfg.SetLocation(place.Query())
+	cfg.LocationName = place.Name
+	cfg.LocationQuery = query
+}
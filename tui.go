@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jtotty/weather-cli/internal/cli"
+	"github.com/jtotty/weather-cli/internal/config"
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/service"
+	"github.com/jtotty/weather-cli/internal/tui"
+	"golang.org/x/term"
+)
+
+// runTUI runs the interactive interface with the terminal in raw mode. The
+// location switcher offers the starting location and every saved location.
+func runTUI(ctx context.Context, cmd cli.Command) {
+	cfg, _, units := prepare(cmd)
+
+	in, out := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(in) || !term.IsTerminal(out) {
+		cli.ExitWithError(errors.New("tui needs an interactive terminal"))
+	}
+
+	resolveLocation(ctx, cfg)
+
+	svc, err := service.NewWeather(cfg)
+	if err != nil {
+		cli.ExitWithError(err)
+	}
+
+	locations, names := tuiLocations(cfg)
+
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		cli.ExitWithError(fmt.Errorf("failed to set up terminal: %w", err))
+	}
+
+	err = tui.Run(ctx, tui.Options{
+		Locations: locations,
+		Units:     units,
+		In:        os.Stdin,
+		Out:       os.Stdout,
+		Size:      func() (int, int, error) { return term.GetSize(out) },
+		Load: func(ctx context.Context, query string) (*forecast.Response, error) {
+			data, err := svc.GetWeatherFor(ctx, query)
+			if err == nil && names[query] != "" {
+				data.Location.Name = names[query]
+			}
+			return data, err
+		},
+	})
+
+	if restoreErr := term.Restore(in, state); restoreErr != nil {
+		cli.ExitWithError(fmt.Errorf("failed to restore terminal: %w", restoreErr))
+	}
+	if err != nil {
+		cli.ExitWithFetchError(err, locationLabel(cfg))
+	}
+}
+
+// tuiLocations lists the configured location followed by the saved ones,
+// with the display names to use for saved coordinates.
+func tuiLocations(cfg *config.Config) ([]tui.Location, map[string]string) {
+	names := make(map[string]string)
+
+	label := cfg.Location
+	switch {
+	case cfg.LocationName != "":
+		label = cfg.LocationName
+		names[cfg.Location] = cfg.LocationName
+	case cfg.IsLocal:
+		label = "Current location"
+	}
+	locations := []tui.Location{{Label: label, Query: cfg.Location}}
+
+	for _, alias := range cfg.LocationAliases() {
+		query, name, err := cfg.ExpandAlias(config.AliasPrefix + alias)
+		if err != nil || query == cfg.Location {
+			continue
+		}
+		names[query] = name
+		locations = append(locations, tui.Location{
+			Label: fmt.Sprintf("%s%s (%s)", config.AliasPrefix, alias, name),
+			Query: query,
+		})
+	}
+	return locations, names
+}
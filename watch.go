@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/jtotty/weather-cli/internal/cli"
+	"github.com/jtotty/weather-cli/internal/forecast"
+	"github.com/jtotty/weather-cli/internal/output"
+	"github.com/jtotty/weather-cli/internal/service"
+	"github.com/jtotty/weather-cli/internal/watch"
+	display "github.com/jtotty/weather-cli/internal/weather"
+	"golang.org/x/term"
+)
+
+// runWatch shows a live dashboard until interrupted. Each refresh goes
+// through the service, so the network is only used once the cached
+// forecast has expired.
+func runWatch(ctx context.Context, cmd cli.Command) {
+	cfg, format, units := prepare(cmd)
+
+	if format != output.FormatText || cfg.Template != "" {
+		cli.ExitWithError(errors.New("watch only supports text output"))
+	}
+
+	fd := int(os.Stdout.Fd())
+	if !term.IsTerminal(fd) {
+		cli.ExitWithError(errors.New("watch needs a terminal; use --format json for scripts"))
+	}
+
+	resolveLocation(ctx, cfg)
+
+	svc, err := service.NewWeather(cfg)
+	if err != nil {
+		cli.ExitWithError(err)
+	}
+
+	err = watch.Run(ctx, watch.Options{
+		Interval: cmd.Interval,
+		Out:      os.Stdout,
+		Size:     func() (int, int, error) { return term.GetSize(fd) },
+		Fetch: func(ctx context.Context) (*forecast.Response, error) {
+			data, err := svc.GetWeather(ctx)
+			// --refresh bypasses the cache for the first fetch only;
+			// later ticks refetch once the cached sections expire.
+			cfg.Refresh = false
+			if err == nil && cfg.LocationName != "" {
+				data.Location.Name = cfg.LocationName
+			}
+			return data, err
+		},
+		Render: func(data *forecast.Response) (string, error) {
+			d, err := display.NewDisplay(data, cfg.IsLocal)
+			if err != nil {
+				return "", err
+			}
+			return d.WithUnits(units).WithSections(cfg.Sections).WithDetails(cmd.Details).WithViewerTime(cmd.ViewerTime).String(), nil
+		},
+	})
+	if err != nil {
+		cli.ExitWithFetchError(err, locationLabel(cfg))
+	}
+}